@@ -92,6 +92,34 @@ var (
 	#Shortened with aliased commands
 	vultr-cli bs r 67181686-5455-4ebb-81eb-7299f3506e2c -s=20
 	`
+
+	cloneLong = `Clone a block storage to a new block storage in the same region. The new block storage is created
+with the size and block type of the source unless --size or --block-type are given. Both are attached live to the
+helper instance given with --instance, which must be in the same region, and the data is copied with dd over ssh.
+The volumes attached by the clone are detached again once the copy is done, also when it fails. With --no-copy, both
+stay attached so the data can be copied on the instance.
+
+The source must be detached, since a disk mounted on the helper instance may change while it is copied. With --force,
+a source already attached to the helper instance is copied as it is, giving a crash-consistent copy only`
+	cloneExample = `
+	# Full example
+	vultr-cli block-storage clone 67181686-5455-4ebb-81eb-7299f3506e2c \
+		--instance=a7898453-dd9e-4b47-bdab-9dd7a3448f1f --label="data-copy" --ssh-key="/root/.ssh/id_ed25519"
+
+	# Create and attach the clone without copying the data
+	vultr-cli bs clone 67181686-5455-4ebb-81eb-7299f3506e2c -i=a7898453-dd9e-4b47-bdab-9dd7a3448f1f --no-copy
+	`
+
+	reportLong = `Report every block storage with the label of the instance it is attached to, its mount status and
+its monthly cost, totalling the size and cost of all block storages. The cost is computed from the size and the
+monthly rate per GB of the block type in the region, which is derived from the costs billed by the API`
+	reportExample = `
+	# Full example
+	vultr-cli block-storage report
+
+	# As JSON
+	vultr-cli bs report --output="json"
+	`
 )
 
 // NewCmdBlockStorage provides the command for block storage to the CLI
@@ -448,6 +476,73 @@ func NewCmdBlockStorage(base *cli.Base) *cobra.Command { //nolint:gocyclo
 		os.Exit(1)
 	}
 
+	// Clone
+	clone := &cobra.Command{
+		Use:     "clone <Block Storage ID>",
+		Short:   "Clone a block storage through a helper instance",
+		Long:    cloneLong,
+		Example: cloneExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a block storage ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := cloneFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// text output shows each step as it runs
+			steps, err := o.newClone(opts).run()
+			if o.Base.Printer.IsText() {
+				return err
+			}
+
+			return o.Base.Printer.DisplayWithError(&CloneStepsPrinter{Steps: steps}, err)
+		},
+	}
+
+	clone.Flags().StringP("instance", "i", "", "ID of the helper instance to which both block storages are attached")
+	if err := clone.MarkFlagRequired("instance"); err != nil {
+		fmt.Printf("error marking block storage clone 'instance' flag required: %v\n", err)
+		os.Exit(1)
+	}
+
+	clone.Flags().StringP("label", "l", "", "(optional) label of the new block storage")
+	clone.Flags().IntP("size", "s", 0, "(optional) size of the new block storage, at least the size of the source")
+	clone.Flags().StringP("block-type", "b", "", "(optional) block type of the new block storage")
+	clone.Flags().String("ssh-user", cloneDefaultUser, "(optional) user to connect to the helper instance as")
+	clone.Flags().String("ssh-key", "", "(optional) path of the private key to connect to the helper instance with")
+	clone.Flags().Int("ssh-port", cloneDefaultPort, "(optional) ssh port of the helper instance")
+	clone.Flags().Bool("no-copy", false, "(optional) create and attach the new block storage without copying the data")
+	clone.Flags().Bool("keep-attached", false, "(optional) leave both block storages attached to the helper instance")
+	clone.Flags().Bool(
+		"force",
+		false,
+		"(optional) copy a source attached to the helper instance, which is only crash-consistent",
+	)
+
+	// Report
+	report := &cobra.Command{
+		Use:     "report",
+		Short:   "Report the attachment and cost of every block storage",
+		Long:    reportLong,
+		Example: reportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := o.report()
+			if err != nil {
+				return fmt.Errorf("error retrieving block storage report : %v", err)
+			}
+
+			data := &ReportPrinter{Volumes: entries}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
 	cmd.AddCommand(
 		list,
 		get,
@@ -457,6 +552,8 @@ func NewCmdBlockStorage(base *cli.Base) *cobra.Command { //nolint:gocyclo
 		attach,
 		detach,
 		resize,
+		clone,
+		report,
 	)
 
 	return cmd
@@ -0,0 +1,425 @@
+package blockstorage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	cloneWaitInterval  = 5 * time.Second
+	cloneWaitTimeout   = 10 * time.Minute
+	cloneDefaultUser   = "root"
+	cloneDefaultPort   = 22
+	cloneDeviceTimeout = 60
+
+	// virtio truncates the serial of a disk, which holds the mount ID, to
+	// 20 characters
+	virtioSerialLength = 20
+
+	statusActive = "active"
+
+	stepPassed  = "ok"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+)
+
+// CloneStep records a single step of a block storage clone
+type CloneStep struct {
+	Time   string `json:"time"`
+	Step   string `json:"step"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// cloneOptions holds the flags of block-storage clone
+type cloneOptions struct {
+	InstanceID   string
+	Label        string
+	Size         int
+	BlockType    string
+	SSHUser      string
+	SSHKey       string
+	SSHPort      int
+	NoCopy       bool
+	KeepAttached bool
+	Force        bool
+}
+
+// cloneRun copies a block storage to a new one through a helper instance
+// which both are attached to, recording each step
+type cloneRun struct {
+	o        *options
+	opts     *cloneOptions
+	steps    []CloneStep
+	header   bool
+	source   *govultr.BlockStorage
+	clone    *govultr.BlockStorage
+	instance *govultr.Instance
+	attached []string
+}
+
+// devicePath returns the path of the disk of a block storage on an instance
+func devicePath(mountID string) string {
+	serial := mountID
+	if len(serial) > virtioSerialLength {
+		serial = serial[:virtioSerialLength]
+	}
+
+	return "/dev/disk/by-id/virtio-" + serial
+}
+
+// copyScript returns the shell script which waits for both disks to appear
+// and copies the source disk to the clone
+func copyScript(source, clone string) string {
+	return fmt.Sprintf(
+		`set -e; for i in $(seq %d); do [ -b %s ] && [ -b %s ] && break; sleep 1; done; `+
+			`dd if=%s of=%s bs=4M conv=fsync status=progress`,
+		cloneDeviceTimeout, source, clone, source, clone,
+	)
+}
+
+// sshArgs returns the ssh arguments which run the script on the host
+func sshArgs(opts *cloneOptions, host, script string) []string {
+	args := []string{"-o", "BatchMode=yes", "-p", strconv.Itoa(opts.SSHPort)}
+	if opts.SSHKey != "" {
+		args = append(args, "-i", opts.SSHKey)
+	}
+
+	return append(args, fmt.Sprintf("%s@%s", opts.SSHUser, host), script)
+}
+
+// ======================================
+
+func (o *options) newClone(opts *cloneOptions) *cloneRun {
+	return &cloneRun{o: o, opts: opts, header: true}
+}
+
+// record adds a step, displaying it as it happens with text output
+func (c *cloneRun) record(step, result, detail string) {
+	s := CloneStep{
+		Time:   time.Now().Format(time.RFC3339),
+		Step:   step,
+		Result: result,
+		Detail: detail,
+	}
+	c.steps = append(c.steps, s)
+
+	if c.o.Base.Printer.IsText() {
+		c.o.Base.Printer.Display(&CloneStepsPrinter{Steps: []CloneStep{s}, Header: c.header}, nil)
+		c.header = false
+	}
+}
+
+// fail records a failed step and returns it as an error
+func (c *cloneRun) fail(step string, err error) error {
+	c.record(step, stepFailed, err.Error())
+	return fmt.Errorf("clone aborted at %s : %v", step, err)
+}
+
+// run performs the clone steps in order, stopping at the first failure. The
+// volumes attached by the clone are detached again, also after a failure
+func (c *cloneRun) run() ([]CloneStep, error) {
+	steps := []func() error{
+		c.inspect,
+		c.create,
+		c.attach,
+		c.copyData,
+	}
+
+	var err error
+	for _, step := range steps {
+		if err = step(); err != nil {
+			break
+		}
+	}
+
+	if errDe := c.detach(); err == nil {
+		err = errDe
+	}
+
+	if err != nil && c.clone != nil {
+		err = fmt.Errorf("%v, clone %s has been left in place", err, c.clone.ID)
+	}
+
+	return c.steps, err
+}
+
+// inspect checks that the source block storage can be attached to the helper
+// instance. A source already attached to it may be in use, so it is only
+// copied with --force
+func (c *cloneRun) inspect() error {
+	source, _, err := c.o.Base.Client.BlockStorage.Get(c.o.Base.Context, c.o.Base.Args[0])
+	if err != nil {
+		return c.fail("inspect", fmt.Errorf("error retrieving block storage : %v", err))
+	}
+
+	instance, _, err := c.o.Base.Client.Instance.Get(c.o.Base.Context, c.opts.InstanceID)
+	if err != nil {
+		return c.fail("inspect", fmt.Errorf("error retrieving helper instance : %v", err))
+	}
+
+	switch {
+	case instance.Region != source.Region:
+		return c.fail("inspect", fmt.Errorf(
+			"helper instance is in %s but the block storage is in %s",
+			instance.Region,
+			source.Region,
+		))
+	case source.AttachedToInstance != "" && source.AttachedToInstance != instance.ID:
+		return c.fail("inspect", fmt.Errorf(
+			"block storage is attached to instance %s, detach it before cloning",
+			source.AttachedToInstance,
+		))
+	case source.AttachedToInstance != "" && !c.opts.NoCopy && !c.opts.Force:
+		return c.fail("inspect", errors.New(
+			"block storage is attached to the helper instance and may be mounted, "+
+				"detach it or pass --force for a crash-consistent copy",
+		))
+	case c.opts.Size != 0 && c.opts.Size < source.SizeGB:
+		return c.fail("inspect", fmt.Errorf("clone size must be at least %d GB", source.SizeGB))
+	case !c.opts.NoCopy && instance.MainIP == "":
+		return c.fail("inspect", errors.New("helper instance has no IP address to copy through"))
+	}
+
+	c.source, c.instance = source, instance
+
+	detail := fmt.Sprintf("%d GB %s in %s", source.SizeGB, source.BlockType, source.Region)
+	if source.AttachedToInstance != "" && !c.opts.NoCopy {
+		detail += ", attached to the helper instance so the copy is only crash-consistent"
+	}
+	c.record("inspect", stepPassed, detail)
+
+	return nil
+}
+
+// create creates the clone block storage and waits for it to be active
+func (c *cloneRun) create() error {
+	req := &govultr.BlockStorageCreate{
+		Region:    c.source.Region,
+		SizeGB:    max(c.opts.Size, c.source.SizeGB),
+		Label:     c.opts.Label,
+		BlockType: c.opts.BlockType,
+	}
+	if req.Label == "" {
+		req.Label = strings.TrimSpace(c.source.Label + " clone")
+	}
+	if req.BlockType == "" {
+		req.BlockType = c.source.BlockType
+	}
+
+	clone, _, err := c.o.Base.Client.BlockStorage.Create(c.o.Base.Context, req)
+	if err != nil {
+		return c.fail("create", fmt.Errorf("error creating block storage : %v", err))
+	}
+	c.clone = clone
+
+	if c.clone, err = c.o.waitForBlockStorage(clone.ID, func(bs *govultr.BlockStorage) bool {
+		return strings.EqualFold(bs.Status, statusActive)
+	}); err != nil {
+		c.clone = clone
+		return c.fail("create", err)
+	}
+
+	c.record("create", stepPassed, fmt.Sprintf("created %s (%d GB)", clone.ID, req.SizeGB))
+
+	return nil
+}
+
+// attach attaches the source, unless it already is, and the clone to the
+// helper instance without restarting it
+func (c *cloneRun) attach() error {
+	ids := []string{c.clone.ID}
+	if c.source.AttachedToInstance == "" {
+		ids = append([]string{c.source.ID}, ids...)
+	}
+
+	for _, id := range ids {
+		err := c.o.Base.Client.BlockStorage.Attach(c.o.Base.Context, id, &govultr.BlockStorageAttach{
+			InstanceID: c.instance.ID,
+			Live:       govultr.BoolToBoolPtr(true),
+		})
+		if err != nil {
+			return c.fail("attach", fmt.Errorf("error attaching %s : %v", id, err))
+		}
+		c.attached = append(c.attached, id)
+
+		bs, err := c.o.waitForBlockStorage(id, func(bs *govultr.BlockStorage) bool {
+			return bs.AttachedToInstance == c.instance.ID && bs.MountID != ""
+		})
+		if err != nil {
+			return c.fail("attach", err)
+		}
+
+		if id == c.source.ID {
+			c.source = bs
+		} else {
+			c.clone = bs
+		}
+	}
+
+	c.record("attach", stepPassed, fmt.Sprintf("attached to %s", c.instance.ID))
+
+	return nil
+}
+
+// copyData copies the source disk to the clone disk on the helper instance
+// over ssh
+func (c *cloneRun) copyData() error {
+	if c.opts.NoCopy {
+		c.record("copy", stepSkipped, "--no-copy was given")
+		return nil
+	}
+
+	source, clone := devicePath(c.source.MountID), devicePath(c.clone.MountID)
+	cmd := exec.CommandContext( //nolint:gosec
+		c.o.Base.Context,
+		"ssh",
+		sshArgs(c.opts, c.instance.MainIP, copyScript(source, clone))...,
+	)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return c.fail("copy", fmt.Errorf("error copying %s to %s : %v", source, clone, err))
+	}
+
+	c.record("copy", stepPassed, fmt.Sprintf("copied %s to %s", source, clone))
+
+	return nil
+}
+
+// detach detaches the volumes the clone attached, unless they should stay
+// attached
+func (c *cloneRun) detach() error {
+	if len(c.attached) == 0 {
+		return nil
+	}
+
+	switch {
+	case c.opts.KeepAttached:
+		c.record("detach", stepSkipped, "--keep-attached was given")
+		return nil
+	case c.opts.NoCopy:
+		c.record("detach", stepSkipped, "--no-copy was given, the volumes stay attached to copy the data")
+		return nil
+	}
+
+	var errs []error
+	for _, id := range c.attached {
+		err := c.o.Base.Client.BlockStorage.Detach(c.o.Base.Context, id, &govultr.BlockStorageDetach{
+			Live: govultr.BoolToBoolPtr(true),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error detaching %s : %v", id, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return c.fail("detach", err)
+	}
+
+	c.record("detach", stepPassed, fmt.Sprintf("detached %s", strings.Join(c.attached, ", ")))
+
+	return nil
+}
+
+// waitForBlockStorage polls a block storage until the condition holds or the
+// timeout passes
+func (o *options) waitForBlockStorage(
+	id string, done func(*govultr.BlockStorage) bool,
+) (*govultr.BlockStorage, error) {
+	deadline := time.Now().Add(cloneWaitTimeout)
+	for {
+		bs, _, err := o.Base.Client.BlockStorage.Get(o.Base.Context, id)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block storage : %v", err)
+		}
+
+		if done(bs) {
+			return bs, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for block storage %s, status is %s", id, bs.Status)
+		}
+
+		select {
+		case <-o.Base.Context.Done():
+			return nil, o.Base.Context.Err()
+		case <-time.After(cloneWaitInterval):
+		}
+	}
+}
+
+// cloneFlags reads the flags of block-storage clone
+func cloneFlags(cmd *cobra.Command) (*cloneOptions, error) {
+	instance, errIn := cmd.Flags().GetString("instance")
+	if errIn != nil {
+		return nil, fmt.Errorf("error parsing 'instance' flag for block storage clone : %v", errIn)
+	}
+
+	label, errLa := cmd.Flags().GetString("label")
+	if errLa != nil {
+		return nil, fmt.Errorf("error parsing 'label' flag for block storage clone : %v", errLa)
+	}
+
+	size, errSz := cmd.Flags().GetInt("size")
+	if errSz != nil {
+		return nil, fmt.Errorf("error parsing 'size' flag for block storage clone : %v", errSz)
+	}
+
+	blockType, errBt := cmd.Flags().GetString("block-type")
+	if errBt != nil {
+		return nil, fmt.Errorf("error parsing 'block-type' flag for block storage clone : %v", errBt)
+	}
+
+	sshUser, errSu := cmd.Flags().GetString("ssh-user")
+	if errSu != nil {
+		return nil, fmt.Errorf("error parsing 'ssh-user' flag for block storage clone : %v", errSu)
+	}
+
+	sshKey, errSk := cmd.Flags().GetString("ssh-key")
+	if errSk != nil {
+		return nil, fmt.Errorf("error parsing 'ssh-key' flag for block storage clone : %v", errSk)
+	}
+
+	sshPort, errSp := cmd.Flags().GetInt("ssh-port")
+	if errSp != nil {
+		return nil, fmt.Errorf("error parsing 'ssh-port' flag for block storage clone : %v", errSp)
+	}
+
+	noCopy, errNc := cmd.Flags().GetBool("no-copy")
+	if errNc != nil {
+		return nil, fmt.Errorf("error parsing 'no-copy' flag for block storage clone : %v", errNc)
+	}
+
+	keepAttached, errKa := cmd.Flags().GetBool("keep-attached")
+	if errKa != nil {
+		return nil, fmt.Errorf("error parsing 'keep-attached' flag for block storage clone : %v", errKa)
+	}
+
+	force, errFo := cmd.Flags().GetBool("force")
+	if errFo != nil {
+		return nil, fmt.Errorf("error parsing 'force' flag for block storage clone : %v", errFo)
+	}
+
+	return &cloneOptions{
+		InstanceID:   instance,
+		Label:        label,
+		Size:         size,
+		BlockType:    blockType,
+		SSHUser:      sshUser,
+		SSHKey:       sshKey,
+		SSHPort:      sshPort,
+		NoCopy:       noCopy,
+		KeepAttached: keepAttached,
+		Force:        force,
+	}, nil
+}
@@ -118,3 +118,129 @@ func (b *BlockStoragePrinter) Data() [][]string {
 func (b *BlockStoragePrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// CloneStepsPrinter ...
+type CloneStepsPrinter struct {
+	Steps  []CloneStep `json:"steps"`
+	Header bool        `json:"-"`
+}
+
+// JSON ...
+func (c *CloneStepsPrinter) JSON() []byte {
+	return printer.MarshalObject(c, "json")
+}
+
+// YAML ...
+func (c *CloneStepsPrinter) YAML() []byte {
+	return printer.MarshalObject(c, "yaml")
+}
+
+// Columns ...
+func (c *CloneStepsPrinter) Columns() [][]string {
+	if !c.Header {
+		return nil
+	}
+
+	return [][]string{0: {
+		"TIME",
+		"STEP",
+		"RESULT",
+		"DETAIL",
+	}}
+}
+
+// Data ...
+func (c *CloneStepsPrinter) Data() [][]string {
+	var data [][]string
+	for i := range c.Steps {
+		data = append(data, []string{
+			c.Steps[i].Time,
+			c.Steps[i].Step,
+			c.Steps[i].Result,
+			c.Steps[i].Detail,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (c *CloneStepsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// ReportPrinter ...
+type ReportPrinter struct {
+	Volumes []ReportEntry `json:"volumes"`
+}
+
+// JSON ...
+func (r *ReportPrinter) JSON() []byte {
+	return printer.MarshalObject(r, "json")
+}
+
+// YAML ...
+func (r *ReportPrinter) YAML() []byte {
+	return printer.MarshalObject(r, "yaml")
+}
+
+// Columns ...
+func (r *ReportPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ID",
+		"LABEL",
+		"REGION",
+		"BLOCK TYPE",
+		"SIZE GB",
+		"INSTANCE ID",
+		"INSTANCE LABEL",
+		"MOUNT STATUS",
+		"COST PER GB",
+		"MONTHLY COST",
+	}}
+}
+
+// Data ...
+func (r *ReportPrinter) Data() [][]string {
+	if len(r.Volumes) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range r.Volumes {
+		data = append(data, []string{
+			r.Volumes[i].ID,
+			r.Volumes[i].Label,
+			r.Volumes[i].Region,
+			r.Volumes[i].BlockType,
+			strconv.Itoa(r.Volumes[i].SizeGB),
+			r.Volumes[i].InstanceID,
+			r.Volumes[i].InstanceLabel,
+			r.Volumes[i].MountStatus,
+			fmt.Sprintf("$%.4f", r.Volumes[i].CostPerGB),
+			fmt.Sprintf("$%.2f", r.Volumes[i].Cost),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (r *ReportPrinter) Paging() [][]string {
+	var size int
+	var cost float32
+	for i := range r.Volumes {
+		size += r.Volumes[i].SizeGB
+		cost += r.Volumes[i].Cost
+	}
+
+	return [][]string{
+		0: {"======================================"},
+		1: {"VOLUMES", "TOTAL GB", "MONTHLY COST"},
+		2: {strconv.Itoa(len(r.Volumes)), strconv.Itoa(size), fmt.Sprintf("$%.2f", cost)},
+	}
+}
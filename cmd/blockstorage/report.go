@@ -0,0 +1,129 @@
+package blockstorage
+
+import (
+	"fmt"
+
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/utils"
+)
+
+// ReportEntry summarizes the attachment and cost of a single block storage
+type ReportEntry struct {
+	ID            string  `json:"id"`
+	Label         string  `json:"label"`
+	Region        string  `json:"region"`
+	BlockType     string  `json:"block_type"`
+	SizeGB        int     `json:"size_gb"`
+	InstanceID    string  `json:"instance_id"`
+	InstanceLabel string  `json:"instance_label"`
+	MountID       string  `json:"mount_id"`
+	MountStatus   string  `json:"mount_status"`
+	CostPerGB     float32 `json:"cost_per_gb"`
+	Cost          float32 `json:"cost"`
+}
+
+// mountStatus describes whether a block storage is attached and mounted
+func mountStatus(bs *govultr.BlockStorage) string {
+	switch {
+	case bs.AttachedToInstance == "":
+		return "detached"
+	case bs.MountID == "":
+		return "attached"
+	default:
+		return fmt.Sprintf("attached (%s)", bs.MountID)
+	}
+}
+
+// rateKey returns the key of the per GB rate of a block storage, which
+// depends on its block type and region
+func rateKey(bs *govultr.BlockStorage) string {
+	return bs.BlockType + "/" + bs.Region
+}
+
+// monthlyRates returns the monthly cost per GB of each block type and region,
+// derived from the cost billed by the API for the block storages of each. The
+// API has no plans endpoint for block storage to take the rates from
+func monthlyRates(bss []govultr.BlockStorage) map[string]float32 {
+	costs, sizes := map[string]float32{}, map[string]int{}
+	for i := range bss {
+		costs[rateKey(&bss[i])] += bss[i].Cost
+		sizes[rateKey(&bss[i])] += bss[i].SizeGB
+	}
+
+	rates := make(map[string]float32, len(costs))
+	for key, cost := range costs {
+		if sizes[key] > 0 {
+			rates[key] = cost / float32(sizes[key])
+		}
+	}
+
+	return rates
+}
+
+// ======================================
+
+// listAll retrieves every block storage, following the paging cursors
+func (o *options) listAll() ([]govultr.BlockStorage, error) {
+	var all []govultr.BlockStorage
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		bss, meta, _, err := o.Base.Client.BlockStorage.List(o.Base.Context, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, bss...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// report builds the report entry of every block storage, with the cost
+// computed from its size and the rate of its block type. The instance label
+// is looked up when the API does not include it
+func (o *options) report() ([]ReportEntry, error) {
+	bss, err := o.listAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := monthlyRates(bss)
+	labels := map[string]string{}
+	entries := []ReportEntry{}
+	for i := range bss {
+		bs := &bss[i]
+		entry := ReportEntry{
+			ID:            bs.ID,
+			Label:         bs.Label,
+			Region:        bs.Region,
+			BlockType:     bs.BlockType,
+			SizeGB:        bs.SizeGB,
+			InstanceID:    bs.AttachedToInstance,
+			InstanceLabel: bs.AttachedToInstanceLabel,
+			MountID:       bs.MountID,
+			MountStatus:   mountStatus(bs),
+			CostPerGB:     rates[rateKey(bs)],
+		}
+		entry.Cost = entry.CostPerGB * float32(entry.SizeGB)
+
+		if entry.InstanceID != "" && entry.InstanceLabel == "" {
+			label, ok := labels[entry.InstanceID]
+			if !ok {
+				instance, _, err := o.Base.Client.Instance.Get(o.Base.Context, entry.InstanceID)
+				if err != nil {
+					return nil, fmt.Errorf("error retrieving instance %s : %v", entry.InstanceID, err)
+				}
+				label = instance.Label
+				labels[entry.InstanceID] = label
+			}
+			entry.InstanceLabel = label
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
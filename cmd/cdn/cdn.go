@@ -4,7 +4,10 @@ package cdn
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -19,6 +22,63 @@ var (
 
 	pushLong    = ``
 	pushExample = ``
+
+	pullPurgeLong = `Purge the entire cache of a CDN pull zone.
+
+The API only supports purging an entire zone. The --path, --prefix and --file
+flags are advisory : the paths they give are listed in a warning, but the entire
+zone is purged regardless. A --file of - is not read from stdin.
+`
+	pullPurgeExample = `
+	# Purge the entire zone
+	vultr-cli cdn pull purge 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3
+
+	# Purge the entire zone, noting the paths changed by a deploy in the warning
+	vultr-cli cdn pull purge 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 --path /index.html --prefix /assets/
+	`
+
+	pullVerifyLong = `Fetch paths through the CDN hostname of a pull zone and from its origin and
+compare the status codes, ETags and headers of the responses. The command exits
+non-zero when any path does not match or cannot be fetched.
+
+Paths are read from --path and --file. Without either, the paths configured for
+the zone in the config file are used, or the root path when none are.
+
+	cdn-verify:
+	  6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3:
+	    - /
+	    - /assets/app.js
+`
+	pullVerifyExample = `
+	# Verify the paths configured for the zone
+	vultr-cli cdn pull verify 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3
+
+	# Purge after a deploy, then verify some paths and compare Cache-Control
+	vultr-cli cdn pull purge 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 && \
+		vultr-cli cdn pull verify 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 -p / -p /index.html --header Cache-Control
+	`
+
+	pushUploadLong = `Upload local files to a CDN push zone.
+
+An upload endpoint is requested for each file, which is then sent to it in a
+signed form POST. Failed uploads are retried with a new endpoint. Files are
+named after their base name, and files within directories, which requires
+--recursive, after their path relative to the directory.
+
+With --sync, remote files which are not being uploaded are deleted once every
+upload has succeeded. A result is reported for each file and the command fails
+if any upload or delete failed.
+`
+	pushUploadExample = `
+	# Upload a single file
+	vultr-cli cdn push upload 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 ./logo.png
+
+	# Upload the contents of a directory and remove remote files missing from it
+	vultr-cli cdn push upload 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 ./public --recursive --sync
+
+	# Show what would be uploaded and deleted
+	vultr-cli cdn push upload 6c4ab2d4-3ef9-4f05-9d1f-9e7cc6e7f8a3 ./public -r --sync --dry-run
+	`
 )
 
 // NewCmdCDN provides the CLI command for CDN functions
@@ -253,9 +313,10 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 
 	// Pull Purge
 	pullPurge := &cobra.Command{
-		Use:   "purge <ZONE ID>",
-		Short: "Purge a CDN pull zone cache",
-		Long:  ``,
+		Use:     "purge <ZONE ID>",
+		Short:   "Purge the entire cache of a CDN pull zone, path flags are advisory",
+		Long:    pullPurgeLong,
+		Example: pullPurgeExample,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return errors.New("please provide a zone ID")
@@ -263,6 +324,26 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, stdin, err := pullPurgeFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// the API only purges entire zones
+			if len(targets) > 0 {
+				fmt.Fprintf(
+					os.Stderr,
+					"warning: purging by path is not supported by the API, purging the entire zone for %s\n",
+					strings.Join(targets, ", "),
+				)
+			}
+			if stdin {
+				fmt.Fprintln(
+					os.Stderr,
+					"warning: purging by path is not supported by the API, purging the entire zone for the paths on stdin",
+				)
+			}
+
 			if err := o.pullPurge(); err != nil {
 				return fmt.Errorf("error purging cdn pull zone : %v", err)
 			}
@@ -272,6 +353,61 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		},
 	}
 
+	pullPurge.Flags().StringSliceP(
+		"path",
+		"p",
+		nil,
+		"(optional) advisory path to purge, may be repeated. The entire zone is purged",
+	)
+	pullPurge.Flags().StringSlice(
+		"prefix",
+		nil,
+		"(optional) advisory path prefix to purge, may be repeated. The entire zone is purged",
+	)
+	pullPurge.Flags().StringP(
+		"file",
+		"f",
+		"",
+		"(optional) advisory file listing paths to purge one per line. The entire zone is purged and - is not read",
+	)
+
+	// Pull Verify
+	pullVerify := &cobra.Command{
+		Use:     "verify <ZONE ID>",
+		Short:   "Compare responses through a CDN pull zone with its origin",
+		Long:    pullVerifyLong,
+		Example: pullVerifyExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a zone ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := o.pullVerifyFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			results, err := o.pullVerify(opts)
+			if results == nil && err != nil {
+				return fmt.Errorf("error verifying cdn pull zone : %v", err)
+			}
+
+			data := &VerifyResultsPrinter{Results: results}
+			return o.Base.Printer.DisplayWithError(data, err)
+		},
+	}
+
+	pullVerify.Flags().StringSliceP("path", "p", nil, "(optional) path to verify, may be repeated")
+	pullVerify.Flags().StringP("file", "f", "", "(optional) file listing paths to verify one per line, or - for stdin")
+	pullVerify.Flags().StringSlice(
+		"header",
+		nil,
+		"(optional) response header to compare in addition to the status, ETag and Content-Type, may be repeated",
+	)
+	pullVerify.Flags().Duration("timeout", verifyDefaultTimeout, "(optional) timeout of each request")
+
 	// Pull Delete
 	pullDel := &cobra.Command{
 		Use:     "delete <ZONE ID>",
@@ -300,6 +436,7 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		pullCreate,
 		pullUpdate,
 		pullPurge,
+		pullVerify,
 		pullDel,
 	)
 
@@ -644,6 +781,40 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		os.Exit(1)
 	}
 
+	// Push Upload
+	pushUpload := &cobra.Command{
+		Use:     "upload <ZONE ID> <PATH...>",
+		Short:   "Upload files to a CDN push zone",
+		Long:    pushUploadLong,
+		Example: pushUploadExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("please provide a zone ID and at least one path")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := pushUploadFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			files, err := o.pushUpload(args[1:], opts)
+			if files == nil && err != nil {
+				return fmt.Errorf("error uploading cdn push zone files : %v", err)
+			}
+
+			data := &PushUploadsPrinter{Files: files}
+			return o.Base.Printer.DisplayWithError(data, err)
+		},
+	}
+
+	pushUpload.Flags().BoolP("recursive", "r", false, "(optional) upload the files within directories")
+	pushUpload.Flags().Bool("sync", false, "(optional) delete remote files which are not being uploaded")
+	pushUpload.Flags().Bool("dry-run", false, "(optional) show the files which would be uploaded and deleted")
+	pushUpload.Flags().IntP("concurrency", "c", uploadDefaultConcurrency, "(optional) number of files uploaded at once")
+	pushUpload.Flags().Int("retries", uploadDefaultRetries, "(optional) number of times a failed upload is retried")
+
 	push.AddCommand(
 		pushList,
 		pushGet,
@@ -654,6 +825,7 @@ func NewCmdCDN(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		pushFileGet,
 		pushFileDel,
 		pushEndpointCreate,
+		pushUpload,
 	)
 
 	cmd.AddCommand(
@@ -733,7 +905,30 @@ func (o *options) pushFileGet() (*govultr.CDNZoneFile, error) {
 }
 
 func (o *options) pushFileDelete() error {
-	return o.Base.Client.CDN.DeletePushZoneFile(o.Base.Context, o.Base.Args[0], o.Base.Args[1])
+	return o.deletePushZoneFile(o.Base.Args[1])
+}
+
+// deletePushZoneFile deletes a push zone file. govultr's DeletePushZoneFile
+// builds the request without sending it, so it is sent here instead. Each
+// segment of the name is escaped, keeping the separators of nested files
+func (o *options) deletePushZoneFile(name string) error {
+	segments := strings.Split(name, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+
+	req, err := o.Base.Client.NewRequest(
+		o.Base.Context,
+		http.MethodDelete,
+		fmt.Sprintf("/v2/cdns/push-zones/%s/files/%s", o.Base.Args[0], strings.Join(segments, "/")),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.Base.Client.DoWithContext(o.Base.Context, req, nil)
+	return err
 }
 
 func (o *options) pushFileEndpointCreate() (*govultr.CDNZoneEndpoint, error) {
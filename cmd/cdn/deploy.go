@@ -0,0 +1,315 @@
+package cdn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	verifyDefaultTimeout = 10 * time.Second
+	verifyConfigKey      = "cdn-verify"
+
+	verifyResultMatch    = "match"
+	verifyResultMismatch = "mismatch"
+	verifyResultError    = "error"
+)
+
+// verifyDefaultHeaders are the headers compared by cdn pull verify in
+// addition to the status code and ETag
+var verifyDefaultHeaders = []string{"Content-Type"}
+
+// VerifyResult is the comparison of a path fetched through the CDN and from
+// the origin
+type VerifyResult struct {
+	Path         string   `json:"path"`
+	CDNURL       string   `json:"cdn_url"`
+	OriginURL    string   `json:"origin_url"`
+	CDNStatus    int      `json:"cdn_status"`
+	OriginStatus int      `json:"origin_status"`
+	Mismatches   []string `json:"mismatches"`
+	Result       string   `json:"result"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// pullVerifyOptions holds the flags of cdn pull verify
+type pullVerifyOptions struct {
+	Paths   []string
+	Headers []string
+	Timeout time.Duration
+}
+
+// readPathList reads the paths of a file, or of stdin when the file is a
+// dash. Blank lines and lines starting with # are ignored
+func readPathList(file string) ([]string, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+
+	return paths, scanner.Err()
+}
+
+// normalizePaths returns the paths with a leading slash, without duplicates
+func normalizePaths(paths []string) []string {
+	var normalized []string
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		if !slices.Contains(normalized, p) {
+			normalized = append(normalized, p)
+		}
+	}
+
+	return normalized
+}
+
+// purgeTargets returns the paths and prefixes requested for a purge, with
+// prefixes marked by a trailing *. They are only reported since the entire
+// zone is purged, so a file of - is skipped and stdin is left unread
+func purgeTargets(paths, prefixes []string, file string) ([]string, error) {
+	if file != "" && file != "-" {
+		listed, err := readPathList(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading purge paths : %v", err)
+		}
+		paths = append(paths, listed...)
+	}
+
+	targets := normalizePaths(paths)
+	for _, p := range normalizePaths(prefixes) {
+		targets = append(targets, strings.TrimSuffix(p, "*")+"*")
+	}
+
+	return targets, nil
+}
+
+// zoneURLs returns the URLs of a path through the CDN and at the origin of a
+// pull zone
+func zoneURLs(zone *govultr.CDNZone, p string) (cdnURL, originURL string) {
+	host := zone.CDNURL
+	if zone.VanityDomain != "" {
+		host = zone.VanityDomain
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://"), "/")
+
+	scheme := zone.OriginScheme
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	return "https://" + host + p, scheme + "://" + strings.TrimSuffix(zone.OriginDomain, "/") + p
+}
+
+// fetchResponse fetches the URL and returns its response with the body discarded
+func fetchResponse(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp, err
+}
+
+// compareResponses returns the differences between the CDN and origin
+// responses. ETags are compared without their weak prefix, as the CDN may
+// weaken the ETag of compressed responses, and only when the origin sets one
+func compareResponses(cdn, origin *http.Response, headers []string) []string {
+	mismatches := []string{}
+
+	if cdn.StatusCode != origin.StatusCode {
+		mismatches = append(mismatches, "status")
+	}
+
+	if originTag := origin.Header.Get("ETag"); originTag != "" {
+		if strings.TrimPrefix(cdn.Header.Get("ETag"), "W/") != strings.TrimPrefix(originTag, "W/") {
+			mismatches = append(mismatches, "etag")
+		}
+	}
+
+	for _, h := range headers {
+		if cdn.Header.Get(h) != origin.Header.Get(h) {
+			mismatches = append(mismatches, strings.ToLower(h))
+		}
+	}
+
+	return mismatches
+}
+
+// verifyPath fetches the path through the CDN and from the origin and
+// compares the responses
+func verifyPath(
+	ctx context.Context, client *http.Client, zone *govultr.CDNZone, p string, headers []string,
+) VerifyResult {
+	result := VerifyResult{Path: p, Mismatches: []string{}}
+	result.CDNURL, result.OriginURL = zoneURLs(zone, p)
+
+	cdn, errCDN := fetchResponse(ctx, client, result.CDNURL)
+	origin, errOrigin := fetchResponse(ctx, client, result.OriginURL)
+	if err := errors.Join(errCDN, errOrigin); err != nil {
+		result.Result, result.Error = verifyResultError, err.Error()
+		return result
+	}
+
+	result.CDNStatus, result.OriginStatus = cdn.StatusCode, origin.StatusCode
+	result.Mismatches = compareResponses(cdn, origin, headers)
+
+	result.Result = verifyResultMatch
+	if len(result.Mismatches) > 0 {
+		result.Result = verifyResultMismatch
+	}
+
+	return result
+}
+
+// ======================================
+
+// verifyPaths returns the paths to verify from the flags, or from the
+// cdn-verify section of the config file keyed by zone ID, or the root
+func (o *options) verifyPaths(paths []string, file string) ([]string, error) {
+	if file != "" {
+		listed, err := readPathList(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading verify paths : %v", err)
+		}
+		paths = append(paths, listed...)
+	}
+
+	if len(paths) == 0 {
+		paths = viper.GetStringSlice(fmt.Sprintf("%s.%s", verifyConfigKey, o.Base.Args[0]))
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	return normalizePaths(paths), nil
+}
+
+// pullVerify compares the responses of the paths through the CDN and from
+// the origin of the pull zone
+func (o *options) pullVerify(opts *pullVerifyOptions) ([]VerifyResult, error) {
+	zone, err := o.pullGet()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving cdn pull zone : %v", err)
+	}
+
+	if zone.OriginDomain == "" || (zone.CDNURL == "" && zone.VanityDomain == "") {
+		return nil, errors.New("the pull zone has no origin domain or CDN hostname")
+	}
+
+	client := &http.Client{Timeout: opts.Timeout}
+
+	var failed int
+	results := make([]VerifyResult, 0, len(opts.Paths))
+	for _, p := range opts.Paths {
+		result := verifyPath(o.Base.Context, client, zone, p, opts.Headers)
+		if result.Result != verifyResultMatch {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d paths did not match the origin", failed, len(results))
+	}
+
+	return results, nil
+}
+
+// pullPurgeFlags reads the purge target flags of cdn pull purge and whether
+// the paths were given on stdin
+func pullPurgeFlags(cmd *cobra.Command) ([]string, bool, error) {
+	paths, errPa := cmd.Flags().GetStringSlice("path")
+	if errPa != nil {
+		return nil, false, fmt.Errorf("error parsing flag 'path' for cdn pull purge : %v", errPa)
+	}
+
+	prefixes, errPr := cmd.Flags().GetStringSlice("prefix")
+	if errPr != nil {
+		return nil, false, fmt.Errorf("error parsing flag 'prefix' for cdn pull purge : %v", errPr)
+	}
+
+	file, errFi := cmd.Flags().GetString("file")
+	if errFi != nil {
+		return nil, false, fmt.Errorf("error parsing flag 'file' for cdn pull purge : %v", errFi)
+	}
+
+	targets, err := purgeTargets(paths, prefixes, file)
+	return targets, file == "-", err
+}
+
+// pullVerifyFlags reads the flags of cdn pull verify
+func (o *options) pullVerifyFlags(cmd *cobra.Command) (*pullVerifyOptions, error) {
+	paths, errPa := cmd.Flags().GetStringSlice("path")
+	if errPa != nil {
+		return nil, fmt.Errorf("error parsing flag 'path' for cdn pull verify : %v", errPa)
+	}
+
+	file, errFi := cmd.Flags().GetString("file")
+	if errFi != nil {
+		return nil, fmt.Errorf("error parsing flag 'file' for cdn pull verify : %v", errFi)
+	}
+
+	headers, errHe := cmd.Flags().GetStringSlice("header")
+	if errHe != nil {
+		return nil, fmt.Errorf("error parsing flag 'header' for cdn pull verify : %v", errHe)
+	}
+
+	timeout, errTi := cmd.Flags().GetDuration("timeout")
+	if errTi != nil {
+		return nil, fmt.Errorf("error parsing flag 'timeout' for cdn pull verify : %v", errTi)
+	}
+
+	verifyPaths, err := o.verifyPaths(paths, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pullVerifyOptions{
+		Paths:   verifyPaths,
+		Headers: append(slices.Clone(verifyDefaultHeaders), headers...),
+		Timeout: timeout,
+	}, nil
+}
@@ -397,3 +397,113 @@ func (p *PushZoneEndpointPrinter) Paging() [][]string {
 }
 
 // ======================================
+
+// PushUploadsPrinter ...
+type PushUploadsPrinter struct {
+	Files []PushUploadResult `json:"files"`
+}
+
+// JSON ...
+func (p *PushUploadsPrinter) JSON() []byte {
+	return printer.MarshalObject(p, "json")
+}
+
+// YAML ...
+func (p *PushUploadsPrinter) YAML() []byte {
+	return printer.MarshalObject(p, "yaml")
+}
+
+// Columns ...
+func (p *PushUploadsPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"FILE",
+		"ACTION",
+		"SIZE",
+		"ATTEMPTS",
+		"RESULT",
+		"ERROR",
+	}}
+}
+
+// Data ...
+func (p *PushUploadsPrinter) Data() [][]string {
+	if len(p.Files) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range p.Files {
+		data = append(data, []string{
+			p.Files[i].Name,
+			p.Files[i].Action,
+			strconv.FormatInt(p.Files[i].Size, 10),
+			strconv.Itoa(p.Files[i].Attempts),
+			p.Files[i].Result,
+			p.Files[i].Error,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (p *PushUploadsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// VerifyResultsPrinter ...
+type VerifyResultsPrinter struct {
+	Results []VerifyResult `json:"results"`
+}
+
+// JSON ...
+func (v *VerifyResultsPrinter) JSON() []byte {
+	return printer.MarshalObject(v, "json")
+}
+
+// YAML ...
+func (v *VerifyResultsPrinter) YAML() []byte {
+	return printer.MarshalObject(v, "yaml")
+}
+
+// Columns ...
+func (v *VerifyResultsPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"PATH",
+		"CDN STATUS",
+		"ORIGIN STATUS",
+		"MISMATCHES",
+		"RESULT",
+		"ERROR",
+	}}
+}
+
+// Data ...
+func (v *VerifyResultsPrinter) Data() [][]string {
+	if len(v.Results) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range v.Results {
+		data = append(data, []string{
+			v.Results[i].Path,
+			strconv.Itoa(v.Results[i].CDNStatus),
+			strconv.Itoa(v.Results[i].OriginStatus),
+			printer.ArrayOfStringsToString(v.Results[i].Mismatches),
+			v.Results[i].Result,
+			v.Results[i].Error,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (v *VerifyResultsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
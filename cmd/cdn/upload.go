@@ -0,0 +1,394 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	uploadDefaultConcurrency = 4
+	uploadDefaultRetries     = 3
+	uploadRetryDelay         = time.Second
+	uploadErrorBodyLimit     = 512
+
+	uploadActionUpload = "upload"
+	uploadActionDelete = "delete"
+
+	uploadResultOK      = "ok"
+	uploadResultFailed  = "failed"
+	uploadResultPlanned = "planned"
+	uploadResultSkipped = "skipped"
+)
+
+// PushUploadResult records the outcome of uploading or deleting a single
+// push zone file
+type PushUploadResult struct {
+	Name     string `json:"name"`
+	Action   string `json:"action"`
+	Size     int64  `json:"size"`
+	Attempts int    `json:"attempts"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+
+	path string
+}
+
+// pushUploadOptions holds the flags of cdn push upload
+type pushUploadOptions struct {
+	Recursive   bool
+	Sync        bool
+	DryRun      bool
+	Concurrency int
+	Retries     int
+}
+
+// uploadError is an upload failure and whether trying again may succeed
+type uploadError struct {
+	err       error
+	retryable bool
+}
+
+func (e *uploadError) Error() string {
+	return e.err.Error()
+}
+
+// collectUploads returns the files to upload for the paths. Files are named
+// after their base name and files found in directories after their path
+// relative to the directory, which requires recursion
+func collectUploads(paths []string, recursive bool) ([]PushUploadResult, error) {
+	var files []PushUploadResult
+	names := map[string]string{}
+
+	add := func(name, p string, size int64) error {
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("%s and %s would both be uploaded as %s", other, p, name)
+		}
+		names[name] = p
+		files = append(files, PushUploadResult{Name: name, Action: uploadActionUpload, Size: size, path: p})
+		return nil
+	}
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if err := add(filepath.Base(root), root, info.Size()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !recursive {
+			return nil, fmt.Errorf("%s is a directory, use --recursive to upload it", root)
+		}
+
+		err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+
+			return add(filepath.ToSlash(rel), p, fi.Size())
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	return files, nil
+}
+
+// planPushDeletes returns the deletes of the remote files which are not
+// being uploaded
+func planPushDeletes(remote []govultr.CDNZoneFile, uploads []PushUploadResult) []PushUploadResult {
+	local := make([]string, 0, len(uploads))
+	for i := range uploads {
+		local = append(local, uploads[i].Name)
+	}
+
+	deletes := []PushUploadResult{}
+	for i := range remote {
+		if !slices.Contains(local, remote[i].Name) {
+			deletes = append(deletes, PushUploadResult{
+				Name:   remote[i].Name,
+				Action: uploadActionDelete,
+				Size:   int64(remote[i].Size),
+			})
+		}
+	}
+
+	return deletes
+}
+
+// uploadForm returns the body of the signed form POST of a file and its
+// length. The endpoint inputs are sent as form fields before the file, which
+// is streamed rather than held in memory
+func uploadForm(
+	endpoint *govultr.CDNZoneEndpoint, file io.Reader, name string, size int64,
+) (io.Reader, int64, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fields := [][2]string{
+		{"acl", endpoint.Inputs.ACL},
+		{"key", endpoint.Inputs.Key},
+		{"policy", endpoint.Inputs.Policy},
+		{"x-amz-credential", endpoint.Inputs.Credential},
+		{"x-amz-algorithm", endpoint.Inputs.Algorithm},
+		{"x-amz-signature", endpoint.Inputs.Signature},
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return nil, 0, "", err
+		}
+	}
+
+	if _, err := w.CreateFormFile("file", path.Base(name)); err != nil {
+		return nil, 0, "", err
+	}
+
+	head := bytes.Clone(buf.Bytes())
+	buf.Reset()
+	if err := w.Close(); err != nil {
+		return nil, 0, "", err
+	}
+	tail := bytes.Clone(buf.Bytes())
+
+	body := io.MultiReader(bytes.NewReader(head), io.LimitReader(file, size), bytes.NewReader(tail))
+	return body, int64(len(head)) + size + int64(len(tail)), w.FormDataContentType(), nil
+}
+
+// postFile sends the file to the upload endpoint. Network errors, throttling
+// and server errors may be retried
+func postFile(ctx context.Context, endpoint *govultr.CDNZoneEndpoint, file *PushUploadResult) error {
+	f, err := os.Open(filepath.Clean(file.path))
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	body, length, contentType, err := uploadForm(endpoint, f, file.Name, file.Size)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &uploadError{err: err, retryable: ctx.Err() == nil}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, uploadErrorBodyLimit))
+	return &uploadError{
+		err:       fmt.Errorf("upload endpoint returned %s : %s", resp.Status, bytes.TrimSpace(msg)),
+		retryable: retryableStatus(resp.StatusCode),
+	}
+}
+
+// retryableStatus reports whether a response status is throttling or a
+// server error, which may succeed when tried again
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// ======================================
+
+// uploadFile requests an endpoint for the file and uploads it, trying again
+// with a new endpoint and an increasing delay when either step fails in a
+// retryable way
+func (o *options) uploadFile(file *PushUploadResult, retries int) error {
+	delay := uploadRetryDelay
+	for {
+		file.Attempts++
+
+		endpoint, resp, err := o.Base.Client.CDN.CreatePushZoneFileEndpoint(
+			o.Base.Context,
+			o.Base.Args[0],
+			&govultr.CDNZoneEndpointReq{Name: file.Name, Size: int(file.Size)},
+		)
+		if err != nil {
+			// requests without a response failed on the network
+			err = &uploadError{
+				err:       fmt.Errorf("error creating upload endpoint : %v", err),
+				retryable: o.Base.Context.Err() == nil && (resp == nil || retryableStatus(resp.StatusCode)),
+			}
+		} else {
+			err = postFile(o.Base.Context, endpoint, file)
+		}
+
+		var ue *uploadError
+		if err == nil || !errors.As(err, &ue) || !ue.retryable || file.Attempts > retries {
+			return err
+		}
+
+		select {
+		case <-o.Base.Context.Done():
+			return o.Base.Context.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// pushUpload uploads the files of the paths with up to the concurrency in
+// flight. With sync, remote files which are not being uploaded are deleted
+// once every upload has succeeded
+func (o *options) pushUpload(paths []string, opts *pushUploadOptions) ([]PushUploadResult, error) {
+	results, err := collectUploads(paths, opts.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletes []PushUploadResult
+	if opts.Sync {
+		remote, errLi := o.pushFileList()
+		if errLi != nil {
+			return nil, fmt.Errorf("error listing cdn push zone files : %v", errLi)
+		}
+		deletes = planPushDeletes(remote.Files, results)
+	}
+
+	if opts.DryRun {
+		results = append(results, deletes...)
+		for i := range results {
+			results[i].Result = uploadResultPlanned
+		}
+		return results, nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		sem    = make(chan struct{}, max(opts.Concurrency, 1))
+	)
+
+	for i := range results {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(r *PushUploadResult) {
+			defer func() { <-sem; wg.Done() }()
+
+			if err := o.uploadFile(r, opts.Retries); err != nil {
+				r.Result, r.Error = uploadResultFailed, err.Error()
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+			r.Result = uploadResultOK
+		}(&results[i])
+	}
+	wg.Wait()
+
+	for i := range deletes {
+		d := &deletes[i]
+		switch {
+		case failed > 0:
+			d.Result, d.Error = uploadResultSkipped, "not deleted because uploads failed"
+		default:
+			d.Attempts = 1
+			if err := o.deletePushZoneFile(d.Name); err != nil {
+				d.Result, d.Error = uploadResultFailed, err.Error()
+				failed++
+			} else {
+				d.Result = uploadResultOK
+			}
+		}
+	}
+	results = append(results, deletes...)
+
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d files failed", failed, len(results))
+	}
+
+	return results, nil
+}
+
+// pushUploadFlags reads the flags of cdn push upload
+func pushUploadFlags(cmd *cobra.Command) (*pushUploadOptions, error) {
+	recursive, errRe := cmd.Flags().GetBool("recursive")
+	if errRe != nil {
+		return nil, fmt.Errorf("error parsing flag 'recursive' for cdn push upload : %v", errRe)
+	}
+
+	syncFiles, errSy := cmd.Flags().GetBool("sync")
+	if errSy != nil {
+		return nil, fmt.Errorf("error parsing flag 'sync' for cdn push upload : %v", errSy)
+	}
+
+	dryRun, errDr := cmd.Flags().GetBool("dry-run")
+	if errDr != nil {
+		return nil, fmt.Errorf("error parsing flag 'dry-run' for cdn push upload : %v", errDr)
+	}
+
+	concurrency, errCo := cmd.Flags().GetInt("concurrency")
+	if errCo != nil {
+		return nil, fmt.Errorf("error parsing flag 'concurrency' for cdn push upload : %v", errCo)
+	}
+
+	retries, errRt := cmd.Flags().GetInt("retries")
+	if errRt != nil {
+		return nil, fmt.Errorf("error parsing flag 'retries' for cdn push upload : %v", errRt)
+	}
+
+	if concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+
+	if retries < 0 {
+		return nil, errors.New("retries must not be negative")
+	}
+
+	return &pushUploadOptions{
+		Recursive:   recursive,
+		Sync:        syncFiles,
+		DryRun:      dryRun,
+		Concurrency: concurrency,
+		Retries:     retries,
+	}, nil
+}
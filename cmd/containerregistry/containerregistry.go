@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -82,6 +83,50 @@ var (
 	vultr-cli container-registry credentials docker d24cfdcc-0534-4700-bf88-8ee48f20064e 
 	`
 
+	loginLong = `Generate credentials for a container registry and merge them into the auth
+file of docker or podman, keeping the other entries of the file.
+
+With --credential-helper, the registry is instead pointed at the vultr
+credential helper, which generates short-lived credentials each time the
+engine needs them. The helper runs when the CLI is invoked as
+docker-credential-vultr, so a link with that name must be on the PATH.
+`
+	loginExample = `
+	# Log docker in to a registry with read only credentials
+	vultr-cli container-registry login 4dcdc52e-9c63-401e-8c5f-1582490fe09c
+
+	# Log podman in with credentials which can push and expire in a day
+	vultr-cli cr login 4dcdc52e-9c63-401e-8c5f-1582490fe09c --engine podman -w -e 86400
+
+	# Use the credential helper for the registry
+	ln -s "$(command -v vultr-cli)" /usr/local/bin/docker-credential-vultr
+	vultr-cli container-registry login 4dcdc52e-9c63-401e-8c5f-1582490fe09c --credential-helper -w
+	`
+
+	credentialsHelperLong = `Run a docker credential helper action. The server URL is read from stdin and
+get writes fresh credentials for the registry registered by
+container-registry login --credential-helper. Credentials are never stored, so
+store and erase do nothing.
+
+Docker and podman run the helper as docker-credential-vultr, which runs this
+command when it is a link to the CLI.
+`
+	//nolint:gosec
+	credentialsHelperExample = `
+	# Print credentials for a registry host
+	echo sjc.vultrcr.com | vultr-cli container-registry credentials helper get
+	`
+
+	credentialsKubernetesLong = `Generate a kubernetes.io/dockerconfigjson Secret manifest holding new
+credentials for a container registry, to use as an image pull secret.
+`
+	//nolint:gosec
+	credentialsKubernetesExample = `
+	# Apply a pull secret for a registry to a namespace
+	vultr-cli container-registry credentials kubernetes 4dcdc52e-9c63-401e-8c5f-1582490fe09c \
+		--name vultr-registry --namespace apps | kubectl apply -f -
+	`
+
 	repoLong    = `Access commands for individual repositories on a container registry`
 	repoExample = `
 	# Full example
@@ -122,6 +167,29 @@ var (
 	vultr-cli cr r d 4dcdc52e-9c63-401e-8c5f-1582490fe09c -i="my-thing"
 	`
 
+	repoPruneLong = `Delete the artifacts of a repository which are not retained by a policy.
+
+Tagged artifacts are only deleted when --keep-last or --older-than is given.
+They are kept when any tag matches --keep-tag, when they are among the
+--keep-last most recently pushed tagged artifacts, or when they were pushed
+within --older-than. Artifacts with an immutable tag are always kept.
+
+Untagged artifacts are deleted with --delete-untagged, unless they were pushed
+within --older-than.
+
+Use --all to apply the policy to every repository in the registry and
+--dry-run to report the artifacts which would be deleted and the space they
+would reclaim.
+`
+	repoPruneExample = `
+	# Keep the last 10 tagged images and release tags, and delete untagged images
+	vultr-cli container-registry repository prune 4dcdc52e-9c63-401e-8c5f-1582490fe09c my-app \
+		--keep-last 10 --keep-tag '^v[0-9]+\.[0-9]+\.[0-9]+$' --delete-untagged
+
+	# Report what deleting images older than 90 days in every repository would reclaim
+	vultr-cli cr r prune 4dcdc52e-9c63-401e-8c5f-1582490fe09c --all --older-than 90d --dry-run
+	`
+
 	artifactLong    = `Access commands for container registry repository artifacts`
 	artifactExample = `
 	# Full example
@@ -603,6 +671,75 @@ func NewCmdContainerRegistry(base *cli.Base) *cobra.Command { //nolint:funlen,go
 		os.Exit(1)
 	}
 
+	// Repository Prune
+	repoPrune := &cobra.Command{
+		Use:     "prune <Registry ID> [Image ID]",
+		Short:   "Delete repository artifacts not retained by a policy",
+		Long:    repoPruneLong,
+		Example: repoPruneExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a container registry ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, errAl := cmd.Flags().GetBool("all")
+			if errAl != nil {
+				return fmt.Errorf("error parsing 'all' flag for container registry repository prune : %v", errAl)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing 'dry-run' flag for container registry repository prune : %v", errDr)
+			}
+
+			policy, err := pruneFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			var repositories []string
+			switch {
+			case all && len(args) > 1:
+				return errors.New("please provide either an image ID or --all")
+			case all:
+				if repositories, err = o.listRepositoriesAll(); err != nil {
+					return fmt.Errorf("error retrieving repositories for container registry : %v", err)
+				}
+			case len(args) < 2:
+				return errors.New("please provide an image ID or --all")
+			default:
+				repositories = []string{args[1]}
+			}
+
+			artifacts, err := o.repositoryPrune(repositories, policy, dryRun)
+			if artifacts == nil && err != nil {
+				return fmt.Errorf("error pruning container registry repository : %v", err)
+			}
+
+			data := &PrunePrinter{Artifacts: artifacts, DryRun: dryRun}
+			for i := range artifacts {
+				if artifacts[i].Result == pruneResultDeleted || artifacts[i].Result == pruneResultPlanned {
+					data.ReclaimedBytes += artifacts[i].Size
+				}
+			}
+
+			return o.Base.Printer.DisplayWithError(data, err)
+		},
+	}
+
+	repoPrune.Flags().Int("keep-last", 0, "(optional) number of most recently pushed tagged artifacts to keep")
+	repoPrune.Flags().String("keep-tag", "", "(optional) regular expression of tags to keep")
+	repoPrune.Flags().Bool("delete-untagged", false, "(optional) delete untagged artifacts")
+	repoPrune.Flags().String(
+		"older-than",
+		"",
+		"(optional) only delete artifacts pushed longer ago than this age, such as 720h, 30d or 4w",
+	)
+	repoPrune.Flags().Bool("all", false, "(optional) apply the policy to every repository in the registry")
+	repoPrune.Flags().Bool("dry-run", false, "(optional) report the artifacts which would be deleted")
+
 	// Artifact
 	artifact := &cobra.Command{
 		Use:     "artifact",
@@ -701,6 +838,7 @@ func NewCmdContainerRegistry(base *cli.Base) *cobra.Command { //nolint:funlen,go
 		repoList,
 		repoUpdate,
 		repoDelete,
+		repoPrune,
 		artifact,
 	)
 
@@ -771,8 +909,145 @@ func NewCmdContainerRegistry(base *cli.Base) *cobra.Command { //nolint:funlen,go
 		"(optional) Whether or not these credentials have write access.  Should be true or false.  Default is false",
 	)
 
+	// Credentials Helper
+	credentialsHelper := &cobra.Command{
+		Use:       "helper <get|store|erase|list>",
+		Short:     "Run a docker credential helper action",
+		Long:      credentialsHelperLong,
+		Example:   credentialsHelperExample,
+		ValidArgs: []string{"get", "store", "erase", "list"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.credentialHelper(args[0], os.Stdin, os.Stdout)
+		},
+	}
+
+	// Credentials Kubernetes
+	credentialsKubernetes := &cobra.Command{
+		Use:     "kubernetes <Registry ID>",
+		Short:   "Create a Kubernetes image pull secret for a container registry",
+		Aliases: []string{"k"},
+		Long:    credentialsKubernetesLong,
+		Example: credentialsKubernetesExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a container registry ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expiry, readWrite, err := credentialFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			name, errNa := cmd.Flags().GetString("name")
+			if errNa != nil {
+				return fmt.Errorf("error parsing 'name' flag for container registry kubernetes creds : %v", errNa)
+			}
+
+			namespace, errNs := cmd.Flags().GetString("namespace")
+			if errNs != nil {
+				return fmt.Errorf("error parsing 'namespace' flag for container registry kubernetes creds : %v", errNs)
+			}
+
+			cred, err := o.mintCredentials(args[0], expiry, readWrite)
+			if err != nil {
+				return fmt.Errorf("error generating container registry kubernetes credentials : %v", err)
+			}
+
+			manifest, err := pullSecretManifest(cred, name, namespace)
+			if err != nil {
+				return fmt.Errorf("error rendering image pull secret : %v", err)
+			}
+
+			fmt.Print(string(manifest))
+
+			return nil
+		},
+	}
+
+	addCredentialFlags(credentialsKubernetes)
+	credentialsKubernetes.Flags().StringP("name", "n", "vultr-registry", "(optional) name of the secret")
+	credentialsKubernetes.Flags().String("namespace", "", "(optional) namespace of the secret")
+
 	credentials.AddCommand(
 		credentialsDocker,
+		credentialsHelper,
+		credentialsKubernetes,
+	)
+
+	// Login
+	login := &cobra.Command{
+		Use:     "login <Registry ID>",
+		Short:   "Log docker or podman in to a container registry",
+		Long:    loginLong,
+		Example: loginExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a container registry ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expiry, readWrite, err := credentialFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			engine, errEn := cmd.Flags().GetString("engine")
+			if errEn != nil {
+				return fmt.Errorf("error parsing 'engine' flag for container registry login : %v", errEn)
+			}
+
+			path, errAf := cmd.Flags().GetString("auth-file")
+			if errAf != nil {
+				return fmt.Errorf("error parsing 'auth-file' flag for container registry login : %v", errAf)
+			}
+
+			helper, errCh := cmd.Flags().GetBool("credential-helper")
+			if errCh != nil {
+				return fmt.Errorf("error parsing 'credential-helper' flag for container registry login : %v", errCh)
+			}
+
+			if path == "" {
+				if path, err = authFilePath(engine); err != nil {
+					return err
+				}
+			}
+
+			hosts, store, err := o.login(path, expiry, readWrite, helper)
+			if err != nil {
+				return fmt.Errorf("error logging in to container registry : %v", err)
+			}
+
+			if store != "" && !helper {
+				fmt.Fprintf(
+					os.Stderr,
+					"warning: %s uses the %s credential store, which may take precedence over the auth written\n",
+					path,
+					store,
+				)
+			}
+
+			registries := strings.Join(hosts, ", ")
+			msg := fmt.Sprintf("credentials for %s have been written to %s", registries, path)
+			if helper {
+				msg = fmt.Sprintf("%s now uses the %s credential helper in %s", registries, CredentialHelperName, path)
+			}
+			o.Base.Printer.Display(printer.Info(msg), nil)
+
+			return nil
+		},
+	}
+
+	addCredentialFlags(login)
+	login.Flags().String("engine", engineDocker, "(optional) container engine to log in, docker or podman")
+	login.Flags().String("auth-file", "", "(optional) auth file to update instead of the engine default")
+	login.Flags().Bool(
+		"credential-helper",
+		false,
+		"(optional) use the credential helper for the registry instead of storing credentials",
 	)
 
 	cmd.AddCommand(
@@ -785,6 +1060,7 @@ func NewCmdContainerRegistry(base *cli.Base) *cobra.Command { //nolint:funlen,go
 		regions,
 		repository,
 		credentials,
+		login,
 	)
 
 	return cmd
@@ -873,10 +1149,9 @@ func (o *options) artifactDelete() error {
 }
 
 func (o *options) credentialsDocker() (*govultr.ContainerRegistryDockerCredentials, error) {
-	cred, _, err := o.Base.Client.ContainerRegistry.CreateDockerCredentials(
-		o.Base.Context,
+	return o.mintCredentials(
 		o.Base.Args[0],
-		o.CredentialsDockerReq,
+		*o.CredentialsDockerReq.ExpirySeconds,
+		*o.CredentialsDockerReq.WriteAccess,
 	)
-	return cred, err
 }
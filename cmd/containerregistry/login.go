@@ -0,0 +1,506 @@
+package containerregistry
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// CredentialHelperName is the executable name docker and podman run for
+	// the credential helper configured as "vultr". A link with this name to
+	// the CLI runs the helper
+	CredentialHelperName = "docker-credential-vultr"
+
+	credentialHelperSuffix = "vultr"
+	credentialNotFound     = "credentials not found in native keychain"
+	helperDefaultExpiry    = 3600
+
+	engineDocker = "docker"
+	enginePodman = "podman"
+
+	authFileMode os.FileMode = 0o600
+	authDirMode  os.FileMode = 0o700
+)
+
+// dockerAuth is a registry entry of a docker config file
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+// helperRegistration holds how the credential helper mints credentials for a
+// registry host
+type helperRegistration struct {
+	RegistryID    string `json:"registry_id"`
+	ExpirySeconds int    `json:"expiry_seconds"`
+	ReadWrite     bool   `json:"read_write"`
+}
+
+// helperCredential is the credential helper response to a get
+type helperCredential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// credentialAuths returns the registry entries of generated docker
+// credentials
+func credentialAuths(cred *govultr.ContainerRegistryDockerCredentials) (map[string]json.RawMessage, error) {
+	var config struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+	if err := json.Unmarshal(*cred, &config); err != nil {
+		return nil, fmt.Errorf("error decoding docker credentials : %v", err)
+	}
+
+	if len(config.Auths) == 0 {
+		return nil, errors.New("the docker credentials do not contain any registry")
+	}
+
+	return config.Auths, nil
+}
+
+// decodeAuth returns the username and password of a registry entry
+func decodeAuth(entry json.RawMessage) (username, password string, err error) {
+	var auth dockerAuth
+	if err := json.Unmarshal(entry, &auth); err != nil {
+		return "", "", err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", errors.New("the registry auth is not a username and password")
+	}
+
+	return username, password, nil
+}
+
+// registryHost returns the host of a registry server URL
+func registryHost(serverURL string) string {
+	host := serverURL
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+
+	return strings.ToLower(host)
+}
+
+// authFilePath returns the auth file of the container engine, honoring the
+// locations each engine reads from the environment
+func authFilePath(engine string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch engine {
+	case engineDocker:
+		if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+			return filepath.Join(dir, "config.json"), nil
+		}
+		return filepath.Join(home, ".docker", "config.json"), nil
+	case enginePodman:
+		if file := os.Getenv("REGISTRY_AUTH_FILE"); file != "" {
+			return file, nil
+		}
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && runtime.GOOS == "linux" {
+			return filepath.Join(dir, "containers", "auth.json"), nil
+		}
+		return filepath.Join(home, ".config", "containers", "auth.json"), nil
+	default:
+		return "", fmt.Errorf("unknown container engine %q, use %s or %s", engine, engineDocker, enginePodman)
+	}
+}
+
+// readJSONFile reads a JSON object file, returning an empty object when it
+// does not exist
+func readJSONFile(path string) (map[string]json.RawMessage, error) {
+	config := map[string]json.RawMessage{}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(strings.TrimSpace(string(data))) == 0) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error decoding %s : %v", path, err)
+	}
+
+	return config, nil
+}
+
+// writeJSONFile writes a JSON object file readable only by the user
+func writeJSONFile(path string, config map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), authDirMode); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), authFileMode)
+}
+
+// setConfigEntry sets or, with a nil value, removes the entry of a JSON
+// object held under key in the config
+func setConfigEntry(config map[string]json.RawMessage, key, entry string, value json.RawMessage) error {
+	section := map[string]json.RawMessage{}
+	if raw, ok := config[key]; ok {
+		if err := json.Unmarshal(raw, &section); err != nil {
+			return fmt.Errorf("error decoding %s : %v", key, err)
+		}
+	}
+
+	if value == nil {
+		delete(section, entry)
+	} else {
+		section[entry] = value
+	}
+
+	if len(section) == 0 {
+		delete(config, key)
+		return nil
+	}
+
+	raw, err := json.Marshal(section)
+	if err != nil {
+		return err
+	}
+	config[key] = raw
+
+	return nil
+}
+
+// mergeAuths adds the registry entries to the auth file config. With helper
+// the hosts are pointed at the credential helper instead and their stored
+// auths are removed
+func mergeAuths(config, auths map[string]json.RawMessage, helper bool) error {
+	for host, entry := range auths {
+		if helper {
+			if err := setConfigEntry(config, "auths", host, nil); err != nil {
+				return err
+			}
+			entry, _ = json.Marshal(credentialHelperSuffix)
+			if err := setConfigEntry(config, "credHelpers", host, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setConfigEntry(config, "auths", host, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// credsStore returns the credential store the auth file config uses for
+// hosts without a credential helper
+func credsStore(config map[string]json.RawMessage) string {
+	var store string
+	if raw, ok := config["credsStore"]; ok {
+		_ = json.Unmarshal(raw, &store)
+	}
+
+	return store
+}
+
+// helperRegistrationsPath returns the file holding the registries the
+// credential helper mints credentials for
+func helperRegistrationsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vultr-cli", "credential-helper.json"), nil
+}
+
+// readHelperRegistrations reads the credential helper registrations keyed
+// by registry host
+func readHelperRegistrations() (map[string]helperRegistration, error) {
+	path, err := helperRegistrationsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := readJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := map[string]helperRegistration{}
+	for host, raw := range config {
+		var r helperRegistration
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("error decoding %s : %v", path, err)
+		}
+		registrations[host] = r
+	}
+
+	return registrations, nil
+}
+
+// registerHelper records the registry the credential helper mints
+// credentials for on each host
+func registerHelper(hosts []string, registration helperRegistration) error {
+	path, err := helperRegistrationsPath()
+	if err != nil {
+		return err
+	}
+
+	config, err := readJSONFile(path)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(registration)
+	if err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		config[strings.ToLower(host)] = raw
+	}
+
+	return writeJSONFile(path, config)
+}
+
+// readHelperInput reads the server URL a credential helper action is given on
+// stdin
+func readHelperInput(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// pullSecretManifest renders a Kubernetes image pull secret holding the
+// docker credentials
+func pullSecretManifest(cred *govultr.ContainerRegistryDockerCredentials, name, namespace string) ([]byte, error) {
+	metadata := map[string]string{"name": name}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+
+	return yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/dockerconfigjson",
+		"metadata":   metadata,
+		"data": map[string]string{
+			".dockerconfigjson": base64.StdEncoding.EncodeToString(*cred),
+		},
+	})
+}
+
+// sortedHosts returns the hosts of the registry entries in order
+func sortedHosts(auths map[string]json.RawMessage) []string {
+	hosts := make([]string, 0, len(auths))
+	for host := range auths {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	return hosts
+}
+
+// ======================================
+
+// mintCredentials creates docker credentials for the registry. govultr's
+// CreateDockerCredentials sends the address of the expiry rather than its
+// value, so the request is built here instead
+func (o *options) mintCredentials(
+	registryID string, expiry int, readWrite bool,
+) (*govultr.ContainerRegistryDockerCredentials, error) {
+	req, err := o.Base.Client.NewRequest(
+		o.Base.Context,
+		http.MethodOptions,
+		fmt.Sprintf("/v2/registry/%s/docker-credentials", registryID),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("expiry_seconds", strconv.Itoa(expiry))
+	query.Set("read_write", strconv.FormatBool(readWrite))
+	req.URL.RawQuery = query.Encode()
+
+	cred := new(govultr.ContainerRegistryDockerCredentials)
+	if _, err := o.Base.Client.DoWithContext(o.Base.Context, req, cred); err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// login merges credentials for the registry into the auth file, or points the
+// registry hosts at the credential helper, and returns the hosts
+func (o *options) login(path string, expiry int, readWrite, helper bool) ([]string, string, error) {
+	if helper && expiry == 0 {
+		expiry = helperDefaultExpiry
+	}
+
+	cred, err := o.mintCredentials(o.Base.Args[0], expiry, readWrite)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auths, err := credentialAuths(cred)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config, err := readJSONFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := mergeAuths(config, auths, helper); err != nil {
+		return nil, "", err
+	}
+
+	hosts := sortedHosts(auths)
+	if helper {
+		registration := helperRegistration{RegistryID: o.Base.Args[0], ExpirySeconds: expiry, ReadWrite: readWrite}
+		if err := registerHelper(hosts, registration); err != nil {
+			return nil, "", fmt.Errorf("error registering credential helper : %v", err)
+		}
+	}
+
+	if err := writeJSONFile(path, config); err != nil {
+		return nil, "", err
+	}
+
+	return hosts, credsStore(config), nil
+}
+
+// credentialHelper runs a docker credential helper action. Credentials are
+// minted on each get and never stored, so store and erase only consume their
+// input
+func (o *options) credentialHelper(action string, in io.Reader, out io.Writer) error {
+	switch action {
+	case "get":
+		serverURL, err := readHelperInput(in)
+		if err != nil {
+			return err
+		}
+
+		registrations, err := readHelperRegistrations()
+		if err != nil {
+			return err
+		}
+
+		host := registryHost(serverURL)
+		registration, ok := registrations[host]
+		if !ok {
+			fmt.Fprintln(out, credentialNotFound)
+			return errors.New(credentialNotFound)
+		}
+
+		cred, err := o.mintCredentials(registration.RegistryID, registration.ExpirySeconds, registration.ReadWrite)
+		if err != nil {
+			return err
+		}
+
+		auths, err := credentialAuths(cred)
+		if err != nil {
+			return err
+		}
+
+		entry, ok := auths[host]
+		if !ok && len(auths) == 1 {
+			entry = auths[sortedHosts(auths)[0]]
+		}
+
+		username, password, err := decodeAuth(entry)
+		if err != nil {
+			return fmt.Errorf("error decoding registry auth : %v", err)
+		}
+
+		return json.NewEncoder(out).Encode(&helperCredential{ServerURL: serverURL, Username: username, Secret: password})
+	case "store", "erase":
+		_, err := io.Copy(io.Discard, in)
+		return err
+	case "list":
+		registrations, err := readHelperRegistrations()
+		if err != nil {
+			return err
+		}
+
+		list := map[string]string{}
+		for host := range registrations {
+			list[host] = ""
+		}
+
+		return json.NewEncoder(out).Encode(list)
+	default:
+		return fmt.Errorf("unknown credential helper action %q", action)
+	}
+}
+
+// addCredentialFlags adds the flags which set the expiry and access of
+// generated credentials
+func addCredentialFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP(
+		"expiry-seconds",
+		"e",
+		0,
+		"(optional) The seconds until these credentials expire.  Default is 0, never",
+	)
+	cmd.Flags().BoolP(
+		"read-write",
+		"w",
+		false,
+		"(optional) Whether or not these credentials have write access.  Should be true or false.  Default is false",
+	)
+}
+
+// credentialFlags reads the expiry and access flags of generated credentials
+func credentialFlags(cmd *cobra.Command) (int, bool, error) {
+	expiry, errEx := cmd.Flags().GetInt("expiry-seconds")
+	if errEx != nil {
+		return 0, false, fmt.Errorf("error parsing 'expiry-seconds' flag for %s : %v", cmd.CommandPath(), errEx)
+	}
+
+	readWrite, errRw := cmd.Flags().GetBool("read-write")
+	if errRw != nil {
+		return 0, false, fmt.Errorf("error parsing 'read-write' flag for %s : %v", cmd.CommandPath(), errRw)
+	}
+
+	if expiry < 0 {
+		return 0, false, errors.New("expiry-seconds must not be negative")
+	}
+
+	return expiry, readWrite, nil
+}
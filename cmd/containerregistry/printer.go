@@ -505,3 +505,85 @@ func (c *ContainerRegistryCredentialDockerPrinter) Data() [][]string {
 func (c *ContainerRegistryCredentialDockerPrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// PrunePrinter ...
+type PrunePrinter struct {
+	Artifacts      []PruneArtifact `json:"artifacts"`
+	ReclaimedBytes int             `json:"reclaimed_bytes"`
+	DryRun         bool            `json:"dry_run"`
+}
+
+// JSON ...
+func (p *PrunePrinter) JSON() []byte {
+	return printer.MarshalObject(p, "json")
+}
+
+// YAML ...
+func (p *PrunePrinter) YAML() []byte {
+	return printer.MarshalObject(p, "yaml")
+}
+
+// Columns ...
+func (p *PrunePrinter) Columns() [][]string {
+	return [][]string{0: {
+		"REPOSITORY",
+		"DIGEST",
+		"TAGS",
+		"SIZE",
+		"DATE PUSHED",
+		"ACTION",
+		"REASON",
+		"RESULT",
+	}}
+}
+
+// Data ...
+func (p *PrunePrinter) Data() [][]string {
+	if len(p.Artifacts) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range p.Artifacts {
+		result := p.Artifacts[i].Result
+		if p.Artifacts[i].Error != "" {
+			result = fmt.Sprintf("%s: %s", result, p.Artifacts[i].Error)
+		}
+
+		data = append(data, []string{
+			p.Artifacts[i].Repository,
+			p.Artifacts[i].Digest,
+			printer.ArrayOfStringsToString(p.Artifacts[i].Tags),
+			strconv.Itoa(p.Artifacts[i].Size),
+			p.Artifacts[i].DatePushed,
+			p.Artifacts[i].Action,
+			p.Artifacts[i].Reason,
+			result,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (p *PrunePrinter) Paging() [][]string {
+	var deleted int
+	for i := range p.Artifacts {
+		if p.Artifacts[i].Action == pruneActionDelete {
+			deleted++
+		}
+	}
+
+	reclaimed := "RECLAIMED BYTES"
+	if p.DryRun {
+		reclaimed = "RECLAIMABLE BYTES"
+	}
+
+	return [][]string{
+		{"======================================"},
+		{"ARTIFACTS", "DELETED", reclaimed},
+		{strconv.Itoa(len(p.Artifacts)), strconv.Itoa(deleted), strconv.Itoa(p.ReclaimedBytes)},
+	}
+}
@@ -0,0 +1,291 @@
+package containerregistry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/utils"
+)
+
+const (
+	pruneActionKeep   = "keep"
+	pruneActionDelete = "delete"
+
+	pruneResultPlanned = "planned"
+	pruneResultDeleted = "deleted"
+	pruneResultFailed  = "failed"
+)
+
+// PrunePolicy holds the retention rules of a repository prune. Tagged
+// artifacts are only deleted when KeepLast or OlderThan is set, and are kept
+// when a tag matches KeepTag, when among the KeepLast most recently pushed or
+// when pushed within OlderThan. Untagged artifacts are deleted with
+// DeleteUntagged unless pushed within OlderThan
+type PrunePolicy struct {
+	KeepLast       int
+	KeepTag        *regexp.Regexp
+	DeleteUntagged bool
+	OlderThan      time.Duration
+}
+
+// PruneArtifact is the decision taken for an artifact by a prune
+type PruneArtifact struct {
+	Repository string   `json:"repository"`
+	Digest     string   `json:"digest"`
+	Tags       []string `json:"tags"`
+	Size       int      `json:"size"`
+	DatePushed string   `json:"date_pushed"`
+	Action     string   `json:"action"`
+	Reason     string   `json:"reason"`
+	Result     string   `json:"result,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// artifactTags returns the tag names of an artifact
+func artifactTags(artifact *govultr.ContainerRegistryArtifact) []string {
+	tags := []string{}
+	for i := range artifact.Tags {
+		tags = append(tags, artifact.Tags[i].Name)
+	}
+
+	return tags
+}
+
+// artifactPushed returns the push time of an artifact and whether it could be
+// parsed
+func artifactPushed(artifact *govultr.ContainerRegistryArtifact) (time.Time, bool) {
+	pushed, err := time.Parse(time.RFC3339, artifact.DatePushed)
+	return pushed, err == nil
+}
+
+// keepReason returns why the policy keeps a tagged artifact, or an empty
+// string when it is deleted. rank is the position of the artifact among the
+// tagged artifacts from the most recently pushed
+func (p *PrunePolicy) keepReason(artifact *govultr.ContainerRegistryArtifact, rank int, cutoff time.Time) string {
+	for i := range artifact.Tags {
+		if artifact.Tags[i].Immutable {
+			return "immutable tag " + artifact.Tags[i].Name
+		}
+		if p.KeepTag != nil && p.KeepTag.MatchString(artifact.Tags[i].Name) {
+			return "tag " + artifact.Tags[i].Name + " matches keep pattern"
+		}
+	}
+
+	if p.KeepLast == 0 && p.OlderThan == 0 {
+		return "tagged"
+	}
+
+	if rank < p.KeepLast {
+		return fmt.Sprintf("among the last %d pushed", p.KeepLast)
+	}
+
+	if p.OlderThan > 0 {
+		if pushed, ok := artifactPushed(artifact); !ok || pushed.After(cutoff) {
+			return "pushed within " + p.OlderThan.String()
+		}
+	}
+
+	return ""
+}
+
+// Plan returns the decision taken for each artifact of a repository
+func (p *PrunePolicy) Plan(
+	repository string, artifacts []govultr.ContainerRegistryArtifact, now time.Time,
+) []PruneArtifact {
+	// most recently pushed first, so the rank of tagged artifacts follows
+	sorted := append([]govultr.ContainerRegistryArtifact(nil), artifacts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, _ := artifactPushed(&sorted[i])
+		tj, _ := artifactPushed(&sorted[j])
+		return ti.After(tj)
+	})
+
+	cutoff := now.Add(-p.OlderThan)
+	plan := []PruneArtifact{}
+	var rank int
+	for i := range sorted {
+		artifact := &sorted[i]
+		entry := PruneArtifact{
+			Repository: repository,
+			Digest:     artifact.Digest,
+			Tags:       artifactTags(artifact),
+			Size:       artifact.Size,
+			DatePushed: artifact.DatePushed,
+			Action:     pruneActionKeep,
+		}
+
+		switch {
+		case len(artifact.Tags) > 0:
+			entry.Reason = p.keepReason(artifact, rank, cutoff)
+			rank++
+			if entry.Reason == "" {
+				entry.Action, entry.Reason = pruneActionDelete, "not retained"
+			}
+		case !p.DeleteUntagged:
+			entry.Reason = "untagged"
+		default:
+			entry.Action, entry.Reason = pruneActionDelete, "untagged"
+			if pushed, ok := artifactPushed(artifact); p.OlderThan > 0 && (!ok || pushed.After(cutoff)) {
+				entry.Action, entry.Reason = pruneActionKeep, "pushed within "+p.OlderThan.String()
+			}
+		}
+
+		plan = append(plan, entry)
+	}
+
+	return plan
+}
+
+// ======================================
+
+// listArtifactsAll retrieves every artifact in the repository, following the
+// paging cursors
+func (o *options) listArtifactsAll(repository string) ([]govultr.ContainerRegistryArtifact, error) {
+	var all []govultr.ContainerRegistryArtifact
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		artifacts, meta, _, err := o.Base.Client.ContainerRegistry.ListArtifacts(
+			o.Base.Context,
+			o.Base.Args[0],
+			repository,
+			opts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, artifacts...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// listRepositoriesAll retrieves the names of every repository in the
+// registry, following the paging cursors
+func (o *options) listRepositoriesAll() ([]string, error) {
+	var names []string
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		repos, meta, _, err := o.Base.Client.ContainerRegistry.ListRepositories(o.Base.Context, o.Base.Args[0], opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range repos {
+			names = append(names, repos[i].Name)
+		}
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return names, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// repositoryPrune applies the policy to the repositories and deletes the
+// artifacts it does not retain, unless this is a dry run
+func (o *options) repositoryPrune(repositories []string, policy *PrunePolicy, dryRun bool) ([]PruneArtifact, error) {
+	var plan []PruneArtifact
+	now := time.Now().UTC()
+	for _, repository := range repositories {
+		artifacts, err := o.listArtifactsAll(repository)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving artifacts of repository %s : %v", repository, err)
+		}
+		plan = append(plan, policy.Plan(repository, artifacts, now)...)
+	}
+
+	var failed int
+	for i := range plan {
+		if plan[i].Action != pruneActionDelete {
+			continue
+		}
+
+		if dryRun {
+			plan[i].Result = pruneResultPlanned
+			continue
+		}
+
+		err := o.Base.Client.ContainerRegistry.DeleteArtifact(
+			o.Base.Context,
+			o.Base.Args[0],
+			plan[i].Repository,
+			plan[i].Digest,
+		)
+		if err != nil {
+			plan[i].Result, plan[i].Error = pruneResultFailed, err.Error()
+			failed++
+			continue
+		}
+		plan[i].Result = pruneResultDeleted
+	}
+
+	if failed > 0 {
+		return plan, fmt.Errorf("%d artifacts could not be deleted", failed)
+	}
+
+	return plan, nil
+}
+
+// pruneFlags reads the retention policy flags of container-registry
+// repository prune
+func pruneFlags(cmd *cobra.Command) (*PrunePolicy, error) {
+	keepLast, errKl := cmd.Flags().GetInt("keep-last")
+	if errKl != nil {
+		return nil, fmt.Errorf("error parsing 'keep-last' flag for container registry repository prune : %v", errKl)
+	}
+
+	keepTag, errKt := cmd.Flags().GetString("keep-tag")
+	if errKt != nil {
+		return nil, fmt.Errorf("error parsing 'keep-tag' flag for container registry repository prune : %v", errKt)
+	}
+
+	untagged, errUn := cmd.Flags().GetBool("delete-untagged")
+	if errUn != nil {
+		return nil, fmt.Errorf(
+			"error parsing 'delete-untagged' flag for container registry repository prune : %v",
+			errUn,
+		)
+	}
+
+	olderThan, errOl := cmd.Flags().GetString("older-than")
+	if errOl != nil {
+		return nil, fmt.Errorf("error parsing 'older-than' flag for container registry repository prune : %v", errOl)
+	}
+
+	policy := &PrunePolicy{KeepLast: keepLast, DeleteUntagged: untagged}
+
+	if keepLast < 0 {
+		return nil, errors.New("keep-last must not be negative")
+	}
+
+	if keepTag != "" {
+		re, err := regexp.Compile(keepTag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keep-tag pattern : %v", err)
+		}
+		policy.KeepTag = re
+	}
+
+	if olderThan != "" {
+		age, err := utils.ParseAge(olderThan)
+		if err != nil {
+			return nil, err
+		}
+		policy.OlderThan = age
+	}
+
+	if policy.KeepLast == 0 && policy.OlderThan == 0 && !policy.DeleteUntagged {
+		return nil, errors.New("please provide at least one of --keep-last, --older-than or --delete-untagged")
+	}
+
+	return policy, nil
+}
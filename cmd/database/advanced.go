@@ -0,0 +1,406 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	advancedOptionsDatabase       = "database"
+	advancedOptionsKafkaREST      = "kafka-rest"
+	advancedOptionsSchemaRegistry = "schema-registry"
+	advancedOptionsKafkaConnect   = "kafka-connect"
+
+	advancedOptionsFilePermission = 0644
+)
+
+// AdvancedOptionChange describes the change of a single advanced option
+type AdvancedOptionChange struct {
+	Name    string `json:"name"`
+	Current any    `json:"current"`
+	New     any    `json:"new"`
+}
+
+// loadAdvancedOptions reads a YAML or JSON file of option names and values
+func loadAdvancedOptions(path string) (map[string]any, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	if err := yaml.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("error parsing %s : %v", path, err)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no advanced options found in %s", path)
+	}
+
+	return values, nil
+}
+
+// optionValues returns the configured options of a govultr advanced options
+// struct keyed by their API names
+func optionValues(options any) (map[string]any, error) {
+	values := make(map[string]any)
+	if options == nil {
+		return values, nil
+	}
+
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// validateAdvancedOptions checks the values against the available options and
+// returns an error listing every invalid value
+func validateAdvancedOptions(values map[string]any, available []govultr.AvailableOption) error {
+	byName := make(map[string]*govultr.AvailableOption, len(available))
+	for i := range available {
+		byName[available[i].Name] = &available[i]
+	}
+
+	var problems []string
+	for _, name := range sortedOptionNames(values) {
+		opt, ok := byName[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s : unknown option", name))
+			continue
+		}
+
+		if err := validateAdvancedOption(values[name], opt); err != nil {
+			problems = append(problems, fmt.Sprintf("%s : %v", name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid advanced options\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+func validateAdvancedOption(value any, opt *govultr.AvailableOption) error {
+	switch opt.Type {
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %v", value)
+		}
+	case "int", "float":
+		n, ok := optionNumber(value)
+		if !ok {
+			return fmt.Errorf("expected a number, got %v", value)
+		}
+
+		if opt.Type == "int" && n != math.Trunc(n) {
+			return fmt.Errorf("expected an integer, got %v", value)
+		}
+
+		for _, alt := range opt.AltValues {
+			if n == float64(alt) {
+				return nil
+			}
+		}
+
+		if opt.MinValue != nil && n < float64(*opt.MinValue) {
+			return fmt.Errorf("%v is below the minimum of %v", value, *opt.MinValue)
+		}
+
+		if opt.MaxValue != nil && n > float64(*opt.MaxValue) {
+			return fmt.Errorf("%v is above the maximum of %v", value, *opt.MaxValue)
+		}
+	case "enum":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected one of %s, got %v", strings.Join(opt.Enumerals, ", "), value)
+		}
+
+		for _, e := range opt.Enumerals {
+			if s == e {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%q is not one of %s", s, strings.Join(opt.Enumerals, ", "))
+	default:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a string, got %v", value)
+		}
+	}
+
+	return nil
+}
+
+// diffAdvancedOptions returns the options whose desired value differs from
+// the configured value, sorted by name
+func diffAdvancedOptions(current, desired map[string]any) []AdvancedOptionChange {
+	var changes []AdvancedOptionChange
+	for _, name := range sortedOptionNames(desired) {
+		if cur, ok := current[name]; ok && sameOptionValue(cur, desired[name]) {
+			continue
+		}
+
+		changes = append(changes, AdvancedOptionChange{Name: name, Current: current[name], New: desired[name]})
+	}
+
+	return changes
+}
+
+// buildAdvancedOptionsReq converts the changed options into the govultr
+// request struct. The govultr structs omit zero values, so an error is
+// returned for any change which would be dropped from the request
+func buildAdvancedOptionsReq(changes []AdvancedOptionChange, req any) error {
+	values := make(map[string]any, len(changes))
+	for i := range changes {
+		values[changes[i].Name] = changes[i].New
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, req); err != nil {
+		return fmt.Errorf("error converting advanced options : %v", err)
+	}
+
+	sent, err := optionValues(req)
+	if err != nil {
+		return err
+	}
+
+	var dropped []string
+	for name := range values {
+		if _, ok := sent[name]; !ok {
+			dropped = append(dropped, name)
+		}
+	}
+
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		return fmt.Errorf("unable to set %s to an empty or zero value", strings.Join(dropped, ", "))
+	}
+
+	return nil
+}
+
+func sameOptionValue(a, b any) bool {
+	na, okA := optionNumber(a)
+	nb, okB := optionNumber(b)
+	if okA && okB {
+		return na == nb
+	}
+
+	return formatOptionValue(a) == formatOptionValue(b)
+}
+
+func optionNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// formatOptionValue renders an option value for display
+func formatOptionValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	}
+
+	if n, ok := optionNumber(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func sortedOptionNames(values map[string]any) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// marshalAdvancedOptions renders the options as JSON when the path has a
+// .json extension and as YAML otherwise
+func marshalAdvancedOptions(values map[string]any, path string) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		raw, err := json.MarshalIndent(values, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+		return append(raw, '\n'), nil
+	}
+
+	return yaml.Marshal(values)
+}
+
+// optionFlagsChanged reports whether any option flag of an update command was
+// set
+func optionFlagsChanged(cmd *cobra.Command) bool {
+	changed := false
+	cmd.LocalNonPersistentFlags().Visit(func(f *pflag.Flag) {
+		if f.Name != "from-file" && f.Name != "dry-run" {
+			changed = true
+		}
+	})
+
+	return changed
+}
+
+// ======================================
+
+// currentAdvancedOptions returns the configured and available advanced
+// options of the given kind
+func (o *options) currentAdvancedOptions(kind string) (map[string]any, []govultr.AvailableOption, error) {
+	var cur any
+	var avail []govultr.AvailableOption
+	var err error
+
+	switch kind {
+	case advancedOptionsKafkaREST:
+		cur, avail, err = o.listAdvancedOptionsKafkaREST()
+	case advancedOptionsSchemaRegistry:
+		cur, avail, err = o.listAdvancedOptionsSchemaRegistry()
+	case advancedOptionsKafkaConnect:
+		cur, avail, err = o.listAdvancedOptionsKafkaConnect()
+	default:
+		cur, avail, err = o.listAdvancedOptions()
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := optionValues(cur)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return values, avail, nil
+}
+
+// applyAdvancedOptions sends only the changed options of the given kind
+func (o *options) applyAdvancedOptions(kind string, changes []AdvancedOptionChange) error {
+	var err error
+
+	switch kind {
+	case advancedOptionsKafkaREST:
+		o.KafkaRESTAdvancedOptionsReq = &govultr.DatabaseKafkaRESTAdvancedOptions{}
+		if err = buildAdvancedOptionsReq(changes, o.KafkaRESTAdvancedOptionsReq); err == nil {
+			_, _, err = o.updateAdvancedOptionsKafkaREST()
+		}
+	case advancedOptionsSchemaRegistry:
+		o.SchemaRegistryAdvancedOptionsReq = &govultr.DatabaseSchemaRegistryAdvancedOptions{}
+		if err = buildAdvancedOptionsReq(changes, o.SchemaRegistryAdvancedOptionsReq); err == nil {
+			_, _, err = o.updateAdvancedOptionsSchemaRegistry()
+		}
+	case advancedOptionsKafkaConnect:
+		o.KafkaConnectAdvancedOptionsReq = &govultr.DatabaseKafkaConnectAdvancedOptions{}
+		if err = buildAdvancedOptionsReq(changes, o.KafkaConnectAdvancedOptionsReq); err == nil {
+			_, _, err = o.updateAdvancedOptionsKafkaConnect()
+		}
+	default:
+		o.AdvancedOptionsReq = &govultr.DatabaseAdvancedOptions{}
+		if err = buildAdvancedOptionsReq(changes, o.AdvancedOptionsReq); err == nil {
+			_, _, err = o.updateAdvancedOptions()
+		}
+	}
+
+	return err
+}
+
+// updateAdvancedOptionsFromFile validates the options in the file against the
+// available options, displays the changes from the configured values and,
+// unless dryRun is set, applies them
+func (o *options) updateAdvancedOptionsFromFile(kind, path string, dryRun bool) error {
+	desired, err := loadAdvancedOptions(path)
+	if err != nil {
+		return fmt.Errorf("error reading advanced options file : %v", err)
+	}
+
+	current, available, err := o.currentAdvancedOptions(kind)
+	if err != nil {
+		return fmt.Errorf("error retrieving database options : %v", err)
+	}
+
+	if errVa := validateAdvancedOptions(desired, available); errVa != nil {
+		return errVa
+	}
+
+	changes := diffAdvancedOptions(current, desired)
+	data := &AdvancedOptionChangesPrinter{Changes: changes, Applied: false}
+
+	if len(changes) > 0 && !dryRun {
+		if errAp := o.applyAdvancedOptions(kind, changes); errAp != nil {
+			return fmt.Errorf("error updating database options : %v", errAp)
+		}
+		data.Applied = true
+	}
+
+	o.Base.Printer.Display(data, nil)
+
+	return nil
+}
+
+// exportAdvancedOptions displays the configured options of the given kind in
+// the file format read by --from-file, or writes them to path
+func (o *options) exportAdvancedOptions(kind, path string) error {
+	current, _, err := o.currentAdvancedOptions(kind)
+	if err != nil {
+		return fmt.Errorf("error retrieving database options : %v", err)
+	}
+
+	if path != "" {
+		raw, errMa := marshalAdvancedOptions(current, path)
+		if errMa != nil {
+			return fmt.Errorf("error encoding advanced options : %v", errMa)
+		}
+
+		if errWr := os.WriteFile(path, raw, advancedOptionsFilePermission); errWr != nil {
+			return fmt.Errorf("error writing advanced options to %s : %v", path, errWr)
+		}
+
+		return nil
+	}
+
+	data := &AdvancedOptionsExportPrinter{Options: current}
+	o.Base.Printer.Display(data, nil)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	backupDateLayout = "2006-01-02"
+	backupTimeLayout = "15:04:05"
+
+	restoreTypePITR       = "pitr"
+	restoreTypeBaseBackup = "basebackup"
+
+	databaseStatusRunning = "running"
+
+	backupDefaultWaitInterval = 15
+	backupDefaultWaitTimeout  = 60
+)
+
+// restorePoint holds the restore type, date and time sent with a restore or
+// fork request
+type restorePoint struct {
+	Type string
+	Date string
+	Time string
+}
+
+// backupTime parses the date and time of a backup, which the API reports in
+// UTC
+func backupTime(b *govultr.DatabaseBackup) (time.Time, error) {
+	return time.Parse(backupDateLayout+" "+backupTimeLayout, b.Date+" "+b.Time)
+}
+
+// planRestore validates the requested time against the backup window of the
+// database and chooses the restore type. A time matching the latest backup
+// is restored from that backup, any other time within the window uses
+// point-in-time recovery, which is only available for MySQL and PostgreSQL
+func planRestore(at time.Time, backups *govultr.DatabaseBackups, engine string, now time.Time) (*restorePoint, error) {
+	if backups.LatestBackup.Date == "" || backups.OldestBackup.Date == "" {
+		return nil, errors.New("database does not have any backups")
+	}
+
+	oldest, err := backupTime(&backups.OldestBackup)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing oldest backup time : %v", err)
+	}
+
+	latest, err := backupTime(&backups.LatestBackup)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing latest backup time : %v", err)
+	}
+
+	at = at.UTC().Truncate(time.Second)
+	if at.Equal(latest) {
+		return &restorePoint{Type: restoreTypeBaseBackup}, nil
+	}
+
+	if engine != enginePG && engine != engineMySQL {
+		return nil, fmt.Errorf(
+			"point-in-time recovery is not supported for %s databases. The latest backup is at %s",
+			engine,
+			latest.Format(time.RFC3339),
+		)
+	}
+
+	if at.Before(oldest) || at.After(now) {
+		return nil, fmt.Errorf(
+			"%s is outside of the available backup window of %s to %s",
+			at.Format(time.RFC3339),
+			oldest.Format(time.RFC3339),
+			now.UTC().Truncate(time.Second).Format(time.RFC3339),
+		)
+	}
+
+	return &restorePoint{
+		Type: restoreTypePITR,
+		Date: at.Format(backupDateLayout),
+		Time: at.Format(backupTimeLayout),
+	}, nil
+}
+
+// ======================================
+
+// planRestoreAt parses the --at flag value and plans the restore against the
+// backups of the database
+func (o *options) planRestoreAt(at, engine string) (*restorePoint, error) {
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing restore time %q, expected RFC 3339 such as 2026-10-10T12:30:00Z", at)
+	}
+
+	backups, err := o.getBackup()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving database backups : %v", err)
+	}
+
+	return planRestore(t, backups, engine, time.Now())
+}
+
+// waitForDatabase polls a database until it is running or the timeout passes
+func (o *options) waitForDatabase(id string, interval, timeout time.Duration) (*govultr.Database, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		db, _, err := o.Base.Client.Database.Get(o.Base.Context, id)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving database : %v", err)
+		}
+
+		if strings.EqualFold(db.Status, databaseStatusRunning) {
+			return db, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for database %s to be running, status is %s", id, db.Status)
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,456 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	engineMySQL  = "mysql"
+	enginePG     = "pg"
+	engineValkey = "valkey"
+	engineKafka  = "kafka"
+
+	formatURI        = "uri"
+	formatEnv        = "env"
+	formatPGPass     = "pgpass"
+	formatMyCnf      = "mycnf"
+	formatProperties = "properties"
+
+	kafkaSASLMechanism = "SCRAM-SHA-256"
+
+	connectFilePermission = 0600
+	connectQueryTimeout   = 30 * time.Second
+)
+
+// connectFormats are the output formats supported by each engine
+var connectFormats = map[string][]string{
+	enginePG:     {formatURI, formatEnv, formatPGPass},
+	engineMySQL:  {formatURI, formatEnv, formatMyCnf},
+	engineValkey: {formatURI, formatEnv},
+	engineKafka:  {formatURI, formatEnv, formatProperties},
+}
+
+// connectClients are the local clients launched by connect --exec
+var connectClients = map[string]string{
+	enginePG:     "psql",
+	engineMySQL:  "mysql",
+	engineValkey: "valkey-cli",
+	engineKafka:  "kcat",
+}
+
+// connection holds the details needed to connect to a managed database as a
+// single user
+type connection struct {
+	Engine   string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+	CACert   string
+	// Plain allows connections without TLS, for sources outside of Vultr
+	Plain bool
+}
+
+// newConnection builds the connection details for a database. The default
+// user of the database is used unless user is provided, and the default
+// database unless dbName is provided
+func newConnection(db *govultr.Database, user *govultr.DatabaseUser, dbName string, public bool) (*connection, error) {
+	if _, ok := connectFormats[db.DatabaseEngine]; !ok {
+		return nil, fmt.Errorf("connecting to %s databases is not supported", db.DatabaseEngine)
+	}
+
+	c := &connection{
+		Engine:   db.DatabaseEngine,
+		Host:     db.Host,
+		Port:     db.Port,
+		User:     db.User,
+		Password: db.Password,
+		Database: db.DBName,
+		CACert:   db.CACertificate,
+	}
+
+	if public {
+		if db.PublicHost == "" {
+			return nil, errors.New("database does not have a public host")
+		}
+		c.Host = db.PublicHost
+	}
+
+	if user != nil {
+		c.User = user.Username
+		c.Password = user.Password
+	}
+
+	switch c.Engine {
+	case engineKafka:
+		if dbName != "" {
+			return nil, errors.New("kafka clusters do not have databases")
+		}
+		if db.SASLPort == "" {
+			return nil, errors.New("kafka cluster does not have a SASL port")
+		}
+		c.Port = db.SASLPort
+		c.Database = ""
+	case engineValkey:
+		c.Database = dbName
+	default:
+		if dbName != "" {
+			c.Database = dbName
+		}
+	}
+
+	return c, nil
+}
+
+// uri returns the connection URI for the engine. For kafka this is the
+// bootstrap server
+func (c *connection) uri(withPassword bool) string {
+	u := &url.URL{Host: net.JoinHostPort(c.Host, c.Port), User: url.User(c.User)}
+	if withPassword {
+		u.User = url.UserPassword(c.User, c.Password)
+	}
+
+	switch c.Engine {
+	case enginePG:
+		u.Scheme = "postgres"
+		u.Path = "/" + c.Database
+		u.RawQuery = "sslmode=require"
+		if c.Plain {
+			u.RawQuery = "sslmode=prefer"
+		}
+	case engineMySQL:
+		u.Scheme = "mysql"
+		u.Path = "/" + c.Database
+		u.RawQuery = "ssl-mode=REQUIRED"
+		if c.Plain {
+			u.RawQuery = "ssl-mode=PREFERRED"
+		}
+	case engineValkey:
+		u.Scheme = "rediss"
+		if c.Plain {
+			u.Scheme = "redis"
+		}
+		if c.Database != "" {
+			u.Path = "/" + c.Database
+		}
+	case engineKafka:
+		return u.Host
+	}
+
+	return u.String()
+}
+
+// format renders the connection details in one of the formats supported by
+// the engine
+func (c *connection) format(format string) (string, error) {
+	supported := false
+	for _, f := range connectFormats[c.Engine] {
+		supported = supported || f == format
+	}
+
+	if !supported {
+		return "", fmt.Errorf(
+			"format %q is not supported for %s databases. Supported formats : %s",
+			format,
+			c.Engine,
+			strings.Join(connectFormats[c.Engine], ", "),
+		)
+	}
+
+	switch format {
+	case formatEnv:
+		return c.env(), nil
+	case formatPGPass:
+		return strings.Join([]string{
+			pgpassEscape(c.Host),
+			pgpassEscape(c.Port),
+			pgpassEscape(c.Database),
+			pgpassEscape(c.User),
+			pgpassEscape(c.Password),
+		}, ":") + "\n", nil
+	case formatMyCnf:
+		return c.myCnf(""), nil
+	case formatProperties:
+		return c.kafkaProperties(""), nil
+	default:
+		return c.uri(true) + "\n", nil
+	}
+}
+
+// env renders the connection details as environment variables understood by
+// the engine's clients
+func (c *connection) env() string {
+	var vars map[string]string
+	switch c.Engine {
+	case enginePG:
+		vars = map[string]string{
+			"PGHOST":       c.Host,
+			"PGPORT":       c.Port,
+			"PGUSER":       c.User,
+			"PGPASSWORD":   c.Password,
+			"PGDATABASE":   c.Database,
+			"PGSSLMODE":    "require",
+			"DATABASE_URL": c.uri(true),
+		}
+	case engineMySQL:
+		vars = map[string]string{
+			"MYSQL_HOST":     c.Host,
+			"MYSQL_TCP_PORT": c.Port,
+			"MYSQL_USER":     c.User,
+			"MYSQL_PWD":      c.Password,
+			"MYSQL_DATABASE": c.Database,
+			"DATABASE_URL":   c.uri(true),
+		}
+	case engineValkey:
+		vars = map[string]string{
+			"REDISCLI_AUTH": c.Password,
+			"VALKEY_URL":    c.uri(true),
+			"REDIS_URL":     c.uri(true),
+		}
+	case engineKafka:
+		vars = map[string]string{
+			"KAFKA_BOOTSTRAP_SERVERS": c.uri(false),
+			"KAFKA_SECURITY_PROTOCOL": "SASL_SSL",
+			"KAFKA_SASL_MECHANISM":    kafkaSASLMechanism,
+			"KAFKA_SASL_USERNAME":     c.User,
+			"KAFKA_SASL_PASSWORD":     c.Password,
+		}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s=%s\n", k, shellQuote(vars[k]))
+	}
+
+	return sb.String()
+}
+
+// myCnf renders a MySQL option file. The CA certificate path is included when
+// provided
+func (c *connection) myCnf(caPath string) string {
+	var sb strings.Builder
+	sb.WriteString("[client]\n")
+	fmt.Fprintf(&sb, "host=%s\n", c.Host)
+	fmt.Fprintf(&sb, "port=%s\n", c.Port)
+	fmt.Fprintf(&sb, "user=%s\n", c.User)
+	fmt.Fprintf(&sb, "password=%s\n", optionFileQuote(c.Password))
+	if c.Database != "" {
+		fmt.Fprintf(&sb, "database=%s\n", c.Database)
+	}
+
+	switch {
+	case caPath != "":
+		sb.WriteString("ssl-mode=VERIFY_CA\n")
+		fmt.Fprintf(&sb, "ssl-ca=%s\n", caPath)
+	case c.Plain:
+		sb.WriteString("ssl-mode=PREFERRED\n")
+	default:
+		sb.WriteString("ssl-mode=REQUIRED\n")
+	}
+
+	return sb.String()
+}
+
+// kafkaProperties renders the client properties for a SASL_SSL connection.
+// The CA certificate path is included when provided
+func (c *connection) kafkaProperties(caPath string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "bootstrap.servers=%s\n", c.uri(false))
+	sb.WriteString("security.protocol=SASL_SSL\n")
+	fmt.Fprintf(&sb, "sasl.mechanisms=%s\n", kafkaSASLMechanism)
+	fmt.Fprintf(&sb, "sasl.username=%s\n", c.User)
+	fmt.Fprintf(&sb, "sasl.password=%s\n", c.Password)
+	if caPath != "" {
+		fmt.Fprintf(&sb, "ssl.ca.location=%s\n", caPath)
+	}
+
+	return sb.String()
+}
+
+// clientCommand returns the arguments and environment used to launch the
+// local client for the engine. Secrets are passed through the environment or
+// files in dir rather than on the command line
+func (c *connection) clientCommand(dir, caPath string, extra []string) ([]string, []string, error) {
+	switch c.Engine {
+	case enginePG:
+		u, _ := url.Parse(c.uri(false))
+		q := u.Query()
+		if caPath != "" {
+			q.Set("sslmode", "verify-ca")
+			q.Set("sslrootcert", caPath)
+		}
+		u.RawQuery = q.Encode()
+
+		return append([]string{u.String()}, extra...), []string{"PGPASSWORD=" + c.Password}, nil
+	case engineMySQL:
+		cnf := filepath.Join(dir, "my.cnf")
+		if err := os.WriteFile(cnf, []byte(c.myCnf(caPath)), connectFilePermission); err != nil {
+			return nil, nil, err
+		}
+
+		return append([]string{"--defaults-extra-file=" + cnf}, extra...), nil, nil
+	case engineValkey:
+		args := []string{"-h", c.Host, "-p", c.Port, "--user", c.User}
+		if !c.Plain {
+			args = append(args, "--tls")
+		}
+		if caPath != "" {
+			args = append(args, "--cacert", caPath)
+		}
+		if c.Database != "" {
+			args = append(args, "-n", c.Database)
+		}
+
+		return append(args, extra...), []string{"REDISCLI_AUTH=" + c.Password}, nil
+	case engineKafka:
+		props := filepath.Join(dir, "kafka.properties")
+		if err := os.WriteFile(props, []byte(c.kafkaProperties(caPath)), connectFilePermission); err != nil {
+			return nil, nil, err
+		}
+
+		if len(extra) == 0 {
+			extra = []string{"-L"}
+		}
+
+		return append([]string{"-F", props}, extra...), nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("connecting to %s databases is not supported", c.Engine)
+}
+
+// errClientNotFound is returned when the local client for the engine is not
+// installed
+var errClientNotFound = errors.New("client not found")
+
+// command builds the command which runs the local client for the engine. The
+// CA certificate and any option files are written to dir
+func (c *connection) command(ctx context.Context, client, dir string, extra []string) (*exec.Cmd, error) {
+	if client == "" {
+		client = connectClients[c.Engine]
+	}
+
+	path, err := exec.LookPath(client)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %s is not installed", errClientNotFound, client)
+	}
+
+	caPath := ""
+	if c.CACert != "" {
+		caPath = filepath.Join(dir, "ca.pem")
+		if errWr := os.WriteFile(caPath, decodeCACert(c.CACert), connectFilePermission); errWr != nil {
+			return nil, fmt.Errorf("error writing CA certificate : %v", errWr)
+		}
+	}
+
+	args, env, err := c.clientCommand(dir, caPath, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...) //nolint:gosec
+	cmd.Env = append(os.Environ(), env...)
+
+	return cmd, nil
+}
+
+// exec launches the local client for the engine, attached to the terminal.
+// The CA certificate and any option files are written to a temporary
+// directory which is removed when the client exits
+func (c *connection) exec(ctx context.Context, client string, extra []string) error {
+	dir, err := os.MkdirTemp("", "vultr-cli-db-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	cmd, err := c.command(ctx, client, dir, extra)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// query runs the local client for the engine non-interactively and returns
+// its output. The first line of the output is returned as the error when the
+// client fails
+func (c *connection) query(ctx context.Context, extra []string) (string, error) {
+	dir, err := os.MkdirTemp("", "vultr-cli-db-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	cctx, cancel := context.WithTimeout(ctx, connectQueryTimeout)
+	defer cancel()
+
+	cmd, err := c.command(cctx, "", dir, extra)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if line := firstLine(output); line != "" {
+			return output, errors.New(line)
+		}
+		return output, err
+	}
+
+	return output, nil
+}
+
+// decodeCACert returns the PEM encoded CA certificate, decoding it first when
+// the API returns it base64 encoded
+func decodeCACert(cert string) []byte {
+	if strings.HasPrefix(strings.TrimSpace(cert), "-----BEGIN") {
+		return []byte(cert)
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(cert); err == nil {
+		return decoded
+	}
+
+	return []byte(cert)
+}
+
+// pgpassEscape escapes the separators of a .pgpass field
+func pgpassEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ":", `\:`).Replace(s)
+}
+
+// optionFileQuote quotes a value for a MySQL option file, in which only the
+// backslash and the double quote need escaping
+func optionFileQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// shellQuote quotes a value for use in a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,273 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	connectorDefaultWatchInterval = 15
+	connectorDefaultMaxRestarts   = 3
+
+	connectorTaskFailed = "FAILED"
+)
+
+// loadConnectorConfig reads a YAML or JSON file of connector configuration
+// keys and values
+func loadConnectorConfig(path string) (map[string]interface{}, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]interface{})
+	if err := yaml.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("error parsing %s : %v", path, err)
+	}
+
+	return config, nil
+}
+
+// validateConnectorConfig checks the configuration against the schema of the
+// connector class. Unknown keys, values which do not match the type of their
+// key and required keys without a default are reported together. List values
+// given as YAML or JSON arrays are converted to the comma separated form
+// expected by the connector. When required is false, missing required keys
+// are not reported so that partial configuration can be used for updates
+func validateConnectorConfig(
+	config map[string]interface{},
+	schema []govultr.DatabaseConnectorConfigurationOption,
+	required bool,
+) error {
+	byName := make(map[string]*govultr.DatabaseConnectorConfigurationOption, len(schema))
+	for i := range schema {
+		byName[schema[i].Name] = &schema[i]
+	}
+
+	var problems []string
+	for key, value := range config {
+		opt, ok := byName[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s : unknown configuration key", key))
+			continue
+		}
+
+		converted, err := checkConnectorValue(value, opt.Type)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s : %v", key, err))
+			continue
+		}
+		config[key] = converted
+	}
+
+	if required {
+		for i := range schema {
+			if _, ok := config[schema[i].Name]; !ok && schema[i].Required && schema[i].DefaultValue == "" {
+				problems = append(problems, fmt.Sprintf("%s : required key is missing", schema[i].Name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid connector configuration\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// checkConnectorValue checks a value against a connector configuration type
+// and returns the value to send
+func checkConnectorValue(value interface{}, optType string) (interface{}, error) {
+	if list, ok := value.([]interface{}); ok {
+		if !strings.EqualFold(optType, "list") {
+			return nil, fmt.Errorf("expected a %s, got a list", strings.ToLower(optType))
+		}
+
+		items := make([]string, len(list))
+		for i := range list {
+			items[i] = fmt.Sprintf("%v", list[i])
+		}
+
+		return strings.Join(items, ","), nil
+	}
+
+	if _, ok := value.(map[string]interface{}); ok {
+		return nil, fmt.Errorf("expected a %s, got a map", strings.ToLower(optType))
+	}
+
+	s := fmt.Sprintf("%v", value)
+
+	var err error
+	switch strings.ToLower(optType) {
+	case "int", "short", "long":
+		_, err = strconv.ParseInt(s, 10, 64)
+	case "double":
+		_, err = strconv.ParseFloat(s, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(s)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("expected a %s, got %q", strings.ToLower(optType), s)
+	}
+
+	return value, nil
+}
+
+// ConnectorEvent describes a change in the state of a connector or one of its
+// tasks observed by connector watch
+type ConnectorEvent struct {
+	Time   string `json:"time"`
+	Task   string `json:"task"`
+	State  string `json:"state"`
+	Action string `json:"action,omitempty"`
+	Trace  string `json:"trace,omitempty"`
+}
+
+// connectorWatcher tracks the last seen states of a connector and its tasks
+// and the number of restarts of each task
+type connectorWatcher struct {
+	autoRestart bool
+	maxRestarts int
+	state       string
+	tasks       map[int]string
+	restarts    map[int]int
+}
+
+func newConnectorWatcher(autoRestart bool, maxRestarts int) *connectorWatcher {
+	return &connectorWatcher{
+		autoRestart: autoRestart,
+		maxRestarts: maxRestarts,
+		tasks:       make(map[int]string),
+		restarts:    make(map[int]int),
+	}
+}
+
+// observe records a status and returns the events for states which changed
+// since the last status, along with the IDs of failed tasks to restart
+func (w *connectorWatcher) observe(status *govultr.DatabaseConnectorStatus, now time.Time) ([]ConnectorEvent, []int) {
+	ts := now.Format(time.RFC3339)
+
+	var events []ConnectorEvent
+	if status.State != w.state {
+		events = append(events, ConnectorEvent{Time: ts, Task: "connector", State: status.State})
+		w.state = status.State
+	}
+
+	var restart []int
+	for i := range status.Tasks {
+		task := &status.Tasks[i]
+		if task.State != w.tasks[task.ID] {
+			events = append(events, ConnectorEvent{
+				Time:  ts,
+				Task:  strconv.Itoa(task.ID),
+				State: task.State,
+				Trace: firstLine(task.Trace),
+			})
+			w.tasks[task.ID] = task.State
+		}
+
+		if task.State == connectorTaskFailed && w.autoRestart {
+			if w.restarts[task.ID] >= w.maxRestarts {
+				if w.restarts[task.ID] == w.maxRestarts {
+					events = append(events, ConnectorEvent{
+						Time:   ts,
+						Task:   strconv.Itoa(task.ID),
+						State:  task.State,
+						Action: fmt.Sprintf("restart limit of %d reached", w.maxRestarts),
+					})
+					w.restarts[task.ID]++
+				}
+				continue
+			}
+
+			w.restarts[task.ID]++
+			restart = append(restart, task.ID)
+		}
+	}
+
+	return events, restart
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// ======================================
+
+// connectorSchema returns the configuration schema of a connector class
+func (o *options) connectorSchema(class string) ([]govultr.DatabaseConnectorConfigurationOption, error) {
+	schema, _, err := o.Base.Client.Database.GetConnectorConfigurationSchema(o.Base.Context, o.Base.Args[0], class)
+	return schema, err
+}
+
+// connectorConfigFromFile reads a connector configuration file and, unless
+// validation is skipped, validates it against the schema of the class
+func (o *options) connectorConfigFromFile(path, class string, required, validate bool) (map[string]interface{}, error) {
+	config, err := loadConnectorConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading connector configuration file : %v", err)
+	}
+
+	if !validate {
+		return config, nil
+	}
+
+	schema, err := o.connectorSchema(class)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving connector configuration schema : %v", err)
+	}
+
+	if err := validateConnectorConfig(config, schema, required); err != nil {
+		return nil, fmt.Errorf("%v\nuse --no-validate to skip validation", err)
+	}
+
+	return config, nil
+}
+
+// watchConnector polls the status of a connector until interrupted,
+// displaying state changes and restarting failed tasks when the watcher
+// allows it
+func (o *options) watchConnector(w *connectorWatcher, interval time.Duration) error {
+	header := true
+	for {
+		status, err := o.getConnectorStatus()
+		if err != nil {
+			return fmt.Errorf("error retrieving database connector status : %v", err)
+		}
+
+		events, restart := w.observe(status, time.Now())
+		for _, taskID := range restart {
+			event := ConnectorEvent{
+				Time:   time.Now().Format(time.RFC3339),
+				Task:   strconv.Itoa(taskID),
+				State:  connectorTaskFailed,
+				Action: fmt.Sprintf("restarted (%d of %d)", w.restarts[taskID], w.maxRestarts),
+			}
+
+			errRe := o.Base.Client.Database.RestartConnectorTask(o.Base.Context, o.Base.Args[0], o.Base.Args[1], taskID)
+			if errRe != nil {
+				event.Action = fmt.Sprintf("restart failed : %v", errRe)
+			}
+
+			events = append(events, event)
+		}
+
+		if len(events) > 0 {
+			o.Base.Printer.Display(&ConnectorEventsPrinter{Events: events, Header: header}, nil)
+			header = false
+		}
+
+		time.Sleep(interval)
+	}
+}
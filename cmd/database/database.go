@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -48,6 +49,159 @@ var (
 	# Full example with custom MySQL settings
 	vultr-cli database update --mysql-slow-query-log="true" --mysql-long-query-time="2"
 	`
+	advancedOptionExportLong = `Print the configured advanced options of a managed database as YAML, or write them to
+a file. The output can be edited and applied with the --from-file flag of the matching update command`
+	kafkaSyncLong = `Compares the topics, quotas and users of a kafka cluster with a YAML manifest and applies the
+creates, updates and deletes needed to match it. Topic and quota settings which are omitted from the
+manifest keep their current values. New topics require partitions and replication, and their retention
+defaults to 168 hours and unlimited bytes. Topics, quotas and users which are not in the manifest are only
+deleted with --prune, and the default user of the cluster is never deleted. New users are created
+with a generated password which can be retrieved with 'database user get'`
+	kafkaSyncExample = `
+	# kafka.yaml
+	topics:
+	  - name: orders
+	    partitions: 6
+	    replication: 3
+	    retention_hours: 168
+	    retention_bytes: -1
+	quotas:
+	  - client_id: billing
+	    user: billing
+	    consumer_byte_rate: 1048576
+	    producer_byte_rate: 1048576
+	    request_percentage: 25
+	users:
+	  - username: billing
+	    permission: readwrite
+
+	# Preview the changes
+	vultr-cli database kafka sync 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --file kafka.yaml --dry-run
+
+	# Apply the changes, deleting anything not in the manifest
+	vultr-cli database kafka sync 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b -f kafka.yaml --prune
+	`
+	connectorWatchLong = `Polls the status of a connector until interrupted and prints the connector and task states
+whenever they change. With --auto-restart, failed tasks are restarted up to --max-restarts times each.
+Only supported with text output`
+	connectorWatchExample = `
+	# Full example
+	vultr-cli database connector watch 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b my-connector --auto-restart --interval 30
+	`
+	connectLong = `Print the connection details of a managed database as a URI, environment variables or a client
+configuration file, or launch the local client for the database engine (psql, mysql, valkey-cli or kcat).
+When launching a client, the CA certificate is written to a temporary file which is removed when the client exits.
+Arguments following -- are passed to the client`
+	connectExample = `
+	# Full example
+	vultr-cli database connect 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --user="app" --db="appdb" --format="env"
+
+	# Append credentials to ~/.pgpass
+	vultr-cli database connect 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --format="pgpass" >> ~/.pgpass
+
+	# Launch the local client
+	vultr-cli database connect 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --exec
+
+	# Launch the local client with extra client arguments
+	vultr-cli database connect 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --exec -- -c "select version()"
+	`
+	migrationRunLong = `Checks that the migration source is reachable over TCP, negotiates TLS when --ssl is set and tests
+the credentials with the local client for the engine (psql, mysql or valkey-cli). The migration is then started and
+its status is followed until it completes or fails. Once complete, the migration can be detached to stop replicating
+from the source and the connection details of the database are printed. Progress is only printed with text output`
+	migrationRunExample = `
+	# Full example
+	vultr-cli database migration run 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --host="source.example.com" --port=5432 \
+		--username="postgres" --password="secret" --database="appdb"
+
+	# Detach without prompting once the migration completes
+	vultr-cli database migration run 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --host="source.example.com" --port=6379 \
+		--password="secret" --detach
+	`
+	backupRestoreLong = `Restore a database backup to a new managed database on the same plan. With --at, the time is
+checked against the available backup window and the restoration type is chosen automatically: the latest backup is
+used when the time matches it, otherwise point-in-time recovery (MySQL and PostgreSQL only). With --wait, the command
+returns once the new database is running, printing its connection details`
+	backupRestoreExample = `
+	# Restore to a point in time and wait for the new database
+	vultr-cli database backup restore 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --label="restored" \
+		--at="2026-10-10T12:30:00Z" --wait
+	`
+	backupForkLong = `Fork a database backup to a new managed database. The plan and region of the source database are
+used unless --plan or --region are provided. With --at, the time is checked against the available backup window and
+the restoration type is chosen automatically: the latest backup is used when the time matches it, otherwise
+point-in-time recovery (MySQL and PostgreSQL only). With --wait, the command returns once the new database is running,
+printing its connection details`
+	backupForkExample = `
+	# Fork to a point in time on a different plan and wait for the new database
+	vultr-cli database backup fork 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --label="forked" \
+		--plan="vultr-dbaas-startup-cc-1-55-2" --at="2026-10-10T12:30:00Z" --wait
+	`
+	failoverLong = `Promote a read replica of a database in a controlled sequence of steps. The replica is checked to be
+a running replica of the primary and its replication lag is measured with the local client for the engine (psql,
+mysql or valkey-cli), skipping the check when the client is not installed. The replica is then promoted and, once it
+is running, a DNS record and a configuration file can be pointed at the new primary. Each step is printed as it
+happens and appended to --audit-log as a JSON line. With --dry-run, only the checks are run and the remaining steps
+are shown as planned`
+	failoverExample = `
+	# Full example
+	vultr-cli database failover 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --replica="4b7c1e8f-2d3a-4f5b-9c6d-7e8f9a0b1c2d" \
+		--dns-domain="example.com" --dns-record="db" --config-file="pgbouncer.ini" --audit-log="failover.log"
+
+	# Check the replica and show the planned changes
+	vultr-cli database failover 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --replica="4b7c1e8f-2d3a-4f5b-9c6d-7e8f9a0b1c2d" \
+		--dns-domain="example.com" --dns-record="db" --dry-run
+	`
+	fleetReportLong = `Report the pending maintenance updates, available version upgrades, service alerts in
+--alert-period and disk usage of every managed database, optionally filtered by tag, region or engine. The command
+exits non-zero when any database breaches one of the thresholds, which are listed in the BREACHES column`
+	fleetReportExample = `
+	# Full example
+	vultr-cli database fleet report --engine="pg" --max-disk=80 --max-alerts=0 --fail-on-maintenance
+
+	# Weekly alerts for databases with a tag as JSON
+	vultr-cli database fleet report --tag="production" --alert-period="week" --output="json"
+	`
+	fleetMaintenanceLong = `Start maintenance on every managed database matching --tag or --engine which has pending
+maintenance updates. With --window, no maintenance is started unless the current UTC time is within the window and
+the remaining databases are skipped once the window closes, so the command can be run on a schedule`
+	fleetMaintenanceExample = `
+	# Full example
+	vultr-cli database fleet maintenance --tag="staging" --engine="mysql" --window="02:00-04:00"
+
+	# Show the databases which would be updated
+	vultr-cli database fleet maintenance --engine="valkey" --dry-run
+	`
+	connectionPoolPlanLong = `Propose a connection pool for each database:user pair given with --pool, or for every logical
+database with the default user, sized from the vCPUs of the plan within the connection limit of the database.
+Existing pools for other pairs are kept and count against the limit. The remaining connection budget is shown after
+the pools. With --apply, the pools are created or updated, unless they would exceed the connection limit`
+	connectionPoolPlanExample = `
+	# Propose pools for every logical database
+	vultr-cli database connection-pool plan 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b
+
+	# Apply session pools of 10 for two users, keeping 20 connections free
+	vultr-cli database connection-pool plan 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --pool="appdb:app,appdb:worker" \
+		--mode="session" --size=10 --reserved=20 --apply
+	`
+	userRotateLong = `Generate a new password locally for each user, update the user and write the new credentials to
+a sink. The json sink prints the credentials, the dotenv sink updates the keys of an environment file, the k8s-secret
+sink renders a Secret manifest per user and the exec sink runs --hook once per user with the credentials as JSON on
+stdin and as DB_ prefixed environment variables. The time of each rotation is included in the credentials. If the
+sink fails after the passwords were changed, the credentials are printed as JSON so they are not lost. --all-users
+leaves out the default admin user of the database, which is only rotated when named explicitly`
+	userRotateExample = `
+	# Rotate a user and update an environment file
+	vultr-cli database user rotate 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b app --sink="dotenv" --file=".env"
+
+	# Rotate every user and apply the secrets to a cluster
+	vultr-cli database user rotate 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b --all-users --sink="k8s-secret" \
+		--namespace="prod" | kubectl apply -f -
+
+	# Run a hook for each rotated user
+	vultr-cli database user rotate 9e8d7a12-5b2c-4f3e-8a1b-2c3d4e5f6a7b app --sink="exec" \
+		--hook='vault kv put secret/db/$DB_USERNAME password="$DB_PASSWORD"'
+	`
 )
 
 // NewCmdDatabase provides the CLI command for database functions
@@ -607,6 +761,123 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		},
 	}
 
+	// Connect
+	connect := &cobra.Command{
+		Use:     "connect <Database ID> [-- <client args>]",
+		Short:   "Connect to a database or print its connection details",
+		Long:    connectLong,
+		Example: connectExample,
+		Aliases: []string{"c"},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			username, errUs := cmd.Flags().GetString("user")
+			if errUs != nil {
+				return fmt.Errorf("error parsing flag 'user' for database connect : %v", errUs)
+			}
+
+			dbName, errDB := cmd.Flags().GetString("db")
+			if errDB != nil {
+				return fmt.Errorf("error parsing flag 'db' for database connect : %v", errDB)
+			}
+
+			format, errFo := cmd.Flags().GetString("format")
+			if errFo != nil {
+				return fmt.Errorf("error parsing flag 'format' for database connect : %v", errFo)
+			}
+
+			launch, errEx := cmd.Flags().GetBool("exec")
+			if errEx != nil {
+				return fmt.Errorf("error parsing flag 'exec' for database connect : %v", errEx)
+			}
+
+			client, errCl := cmd.Flags().GetString("client")
+			if errCl != nil {
+				return fmt.Errorf("error parsing flag 'client' for database connect : %v", errCl)
+			}
+
+			public, errPu := cmd.Flags().GetBool("public-host")
+			if errPu != nil {
+				return fmt.Errorf("error parsing flag 'public-host' for database connect : %v", errPu)
+			}
+
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'output-file' for database connect : %v", errPa)
+			}
+
+			var extra []string
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				extra = args[dash:]
+			}
+
+			if len(extra) > 0 && !launch {
+				return errors.New("client arguments can only be used with --exec")
+			}
+
+			db, err := o.get()
+			if err != nil {
+				return fmt.Errorf("error retrieving database : %v", err)
+			}
+
+			var us *govultr.DatabaseUser
+			if username != "" && username != db.User {
+				us, err = o.getConnectUser(username)
+				if err != nil {
+					return fmt.Errorf("error retrieving database user : %v", err)
+				}
+			}
+
+			conn, err := newConnection(db, us, dbName, public)
+			if err != nil {
+				return err
+			}
+
+			if launch {
+				return conn.exec(o.Base.Context, client, extra)
+			}
+
+			details, err := conn.format(format)
+			if err != nil {
+				return err
+			}
+
+			if path != "" {
+				if errWr := os.WriteFile(path, []byte(details), connectFilePermission); errWr != nil {
+					return fmt.Errorf("error writing connection details to %s : %v", path, errWr)
+				}
+				return nil
+			}
+
+			data := &ConnectionDetailsPrinter{Engine: conn.Engine, Format: format, Details: details}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	connect.Flags().StringP("user", "u", "", "(optional) the database user to connect as. Defaults to the default user")
+	connect.Flags().StringP(
+		"db",
+		"d",
+		"",
+		"(optional) the logical database to connect to. Defaults to the default database",
+	)
+	connect.Flags().StringP(
+		"format",
+		"f",
+		formatURI,
+		"(optional) the format of the connection details. Possible values: 'uri', 'env', 'pgpass', 'mycnf', 'properties'",
+	)
+	connect.Flags().BoolP("exec", "e", false, "(optional) launch the local client for the database engine")
+	connect.Flags().StringP("client", "", "", "(optional) the path of the client to launch with --exec")
+	connect.Flags().BoolP("public-host", "", false, "(optional) connect through the public host of a VPC database")
+	connect.Flags().StringP("output-file", "", "", "(optional) the file path to write the connection details to")
+
 	// Plan
 	plan := &cobra.Command{
 		Use:   "plan",
@@ -917,6 +1188,116 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		userACLUpdate,
 	)
 
+	// User Rotate
+	userRotate := &cobra.Command{
+		Use:     "rotate <Database ID> [<User Name>...]",
+		Short:   "Rotate the passwords of database users",
+		Long:    userRotateLong,
+		Example: userRotateExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			allUsers, errAl := cmd.Flags().GetBool("all-users")
+			if errAl != nil {
+				return fmt.Errorf("error parsing flag 'all-users' for database user rotate : %v", errAl)
+			}
+
+			switch {
+			case len(args) < 1:
+				return errors.New("please provide a database ID")
+			case allUsers && len(args) > 1:
+				return errors.New("please provide either user names or --all-users")
+			case !allUsers && len(args) < 2:
+				return errors.New("please provide a database ID and a user name, or --all-users")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allUsers, errAl := cmd.Flags().GetBool("all-users")
+			if errAl != nil {
+				return fmt.Errorf("error parsing flag 'all-users' for database user rotate : %v", errAl)
+			}
+
+			length, errLe := cmd.Flags().GetInt("length")
+			if errLe != nil {
+				return fmt.Errorf("error parsing flag 'length' for database user rotate : %v", errLe)
+			}
+
+			sinkKind, errSi := cmd.Flags().GetString("sink")
+			if errSi != nil {
+				return fmt.Errorf("error parsing flag 'sink' for database user rotate : %v", errSi)
+			}
+
+			file, errFi := cmd.Flags().GetString("file")
+			if errFi != nil {
+				return fmt.Errorf("error parsing flag 'file' for database user rotate : %v", errFi)
+			}
+
+			secretName, errSn := cmd.Flags().GetString("secret-name")
+			if errSn != nil {
+				return fmt.Errorf("error parsing flag 'secret-name' for database user rotate : %v", errSn)
+			}
+
+			namespace, errNs := cmd.Flags().GetString("namespace")
+			if errNs != nil {
+				return fmt.Errorf("error parsing flag 'namespace' for database user rotate : %v", errNs)
+			}
+
+			hook, errHo := cmd.Flags().GetString("hook")
+			if errHo != nil {
+				return fmt.Errorf("error parsing flag 'hook' for database user rotate : %v", errHo)
+			}
+
+			sink := &rotateSink{
+				Kind:       sinkKind,
+				File:       file,
+				SecretName: secretName,
+				Namespace:  namespace,
+				Hook:       hook,
+			}
+
+			creds, err := o.rotateUsers(args[1:], allUsers, length, sink)
+			if err != nil {
+				return err
+			}
+
+			if sinkKind == sinkJSON || (file == "" && sinkKind != sinkExec) {
+				return nil
+			}
+
+			data := &RotationsPrinter{Sink: sinkKind}
+			for i := range creds {
+				data.Rotations = append(data.Rotations, Rotation{Username: creds[i].Username, RotatedAt: creds[i].RotatedAt})
+			}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	userRotate.Flags().Bool(
+		"all-users",
+		false,
+		"(optional) rotate the passwords of every user of the database except the default user",
+	)
+	userRotate.Flags().Int("length", rotateDefaultLength, "(optional) length of the generated passwords")
+	userRotate.Flags().String(
+		"sink",
+		sinkJSON,
+		"(optional) where to write the new credentials (json, dotenv, k8s-secret, exec)",
+	)
+	userRotate.Flags().StringP(
+		"file",
+		"f",
+		"",
+		"(optional) file to write the credentials to instead of stdout, required for the dotenv sink",
+	)
+	userRotate.Flags().String(
+		"secret-name",
+		"",
+		"(optional) name of the Kubernetes secret, defaults to the database label",
+	)
+	userRotate.Flags().String("namespace", "", "(optional) namespace of the Kubernetes secret")
+	userRotate.Flags().String("hook", "", "(optional) shell command run for each user with the exec sink")
+
 	user.AddCommand(
 		userList,
 		userGet,
@@ -924,6 +1305,7 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		userUpdate,
 		userDelete,
 		userACL,
+		userRotate,
 	)
 
 	// Logical Database
@@ -1409,6 +1791,95 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		quotaDelete,
 	)
 
+	// Kafka
+	kafka := &cobra.Command{
+		Use:   "kafka",
+		Short: "Commands to manage kafka clusters declaratively",
+	}
+
+	// Kafka Sync
+	kafkaSync := &cobra.Command{
+		Use:     "sync <Database ID>",
+		Short:   "Sync kafka topics, quotas and users from a manifest",
+		Long:    kafkaSyncLong,
+		Example: kafkaSyncExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errFi := cmd.Flags().GetString("file")
+			if errFi != nil {
+				return fmt.Errorf("error parsing flag 'file' for kafka sync : %v", errFi)
+			}
+
+			prune, errPr := cmd.Flags().GetBool("prune")
+			if errPr != nil {
+				return fmt.Errorf("error parsing flag 'prune' for kafka sync : %v", errPr)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for kafka sync : %v", errDr)
+			}
+
+			manifest, err := loadKafkaManifest(path)
+			if err != nil {
+				return fmt.Errorf("error reading kafka manifest : %v", err)
+			}
+
+			db, err := o.get()
+			if err != nil {
+				return fmt.Errorf("error retrieving database : %v", err)
+			}
+
+			if db.DatabaseEngine != engineKafka {
+				return fmt.Errorf("database %s is not a kafka cluster", db.ID)
+			}
+
+			topics, _, err := o.listTopics()
+			if err != nil {
+				return fmt.Errorf("error retrieving database topics : %v", err)
+			}
+
+			quotas, _, err := o.listQuotas()
+			if err != nil {
+				return fmt.Errorf("error retrieving database quotas : %v", err)
+			}
+
+			users, _, err := o.listUsers()
+			if err != nil {
+				return fmt.Errorf("error retrieving database users : %v", err)
+			}
+
+			changes, err := diffKafka(manifest, topics, quotas, users, prune, db.User)
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				o.Base.Printer.Display(printer.Info("Kafka cluster already matches the manifest"), nil)
+				return nil
+			}
+
+			return o.runKafkaChanges(changes, dryRun)
+		},
+	}
+
+	kafkaSync.Flags().StringP("file", "f", "", "the YAML manifest of topics, quotas and users")
+	if err := kafkaSync.MarkFlagRequired("file"); err != nil {
+		fmt.Printf("error marking kafka sync 'file' flag required: %v", err)
+		os.Exit(1)
+	}
+	kafkaSync.Flags().Bool("prune", false, "(optional) delete topics, quotas and users which are not in the manifest")
+	kafkaSync.Flags().Bool("dry-run", false, "(optional) show the changes without applying them")
+
+	kafka.AddCommand(
+		kafkaSync,
+	)
+
 	// Available Connector
 	availableConnector := &cobra.Command{
 		Use:   "available-connector",
@@ -1549,6 +2020,16 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error parsing flag 'config' for database connector create : %v", err)
 			}
 
+			configFile, err := cmd.Flags().GetString("config-file")
+			if err != nil {
+				return fmt.Errorf("error parsing flag 'config-file' for database connector create : %v", err)
+			}
+
+			noValidate, err := cmd.Flags().GetBool("no-validate")
+			if err != nil {
+				return fmt.Errorf("error parsing flag 'no-validate' for database connector create : %v", err)
+			}
+
 			var configMap map[string]interface{}
 			if config != "" {
 				if err := json.Unmarshal([]byte(config), &configMap); err != nil {
@@ -1556,6 +2037,13 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				}
 			}
 
+			if configFile != "" {
+				configMap, err = o.connectorConfigFromFile(configFile, class, true, !noValidate)
+				if err != nil {
+					return err
+				}
+			}
+
 			o.ConnectorCreateReq = &govultr.DatabaseConnectorCreateReq{
 				Name:   name,
 				Class:  class,
@@ -1579,6 +2067,18 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	connectorCreate.Flags().StringP("class", "c", "", "class for the new managed database connector")
 	connectorCreate.Flags().StringP("topics", "t", "", "topics for the new managed database connector")
 	connectorCreate.Flags().StringP("config", "", "", "configuration json for the new managed database connector")
+	connectorCreate.Flags().StringP(
+		"config-file",
+		"f",
+		"",
+		"(optional) a YAML or JSON file of configuration for the new managed database connector",
+	)
+	connectorCreate.Flags().Bool(
+		"no-validate",
+		false,
+		"(optional) skip validating the configuration file against the connector configuration schema",
+	)
+	connectorCreate.MarkFlagsMutuallyExclusive("config", "config-file")
 
 	// Connector Update
 	connectorUpdate := &cobra.Command{
@@ -1601,6 +2101,16 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error parsing flag 'config' for database connector update : %v", err)
 			}
 
+			configFile, err := cmd.Flags().GetString("config-file")
+			if err != nil {
+				return fmt.Errorf("error parsing flag 'config-file' for database connector update : %v", err)
+			}
+
+			noValidate, err := cmd.Flags().GetBool("no-validate")
+			if err != nil {
+				return fmt.Errorf("error parsing flag 'no-validate' for database connector update : %v", err)
+			}
+
 			var configMap map[string]interface{}
 			if config != "" {
 				if err := json.Unmarshal([]byte(config), &configMap); err != nil {
@@ -1608,13 +2118,25 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				}
 			}
 
+			if configFile != "" {
+				existing, errGe := o.getConnector()
+				if errGe != nil {
+					return fmt.Errorf("error retrieving database connector : %v", errGe)
+				}
+
+				configMap, err = o.connectorConfigFromFile(configFile, existing.Class, false, !noValidate)
+				if err != nil {
+					return err
+				}
+			}
+
 			o.ConnectorUpdateReq = &govultr.DatabaseConnectorUpdateReq{}
 
 			if cmd.Flags().Changed("topics") {
 				o.ConnectorUpdateReq.Topics = topics
 			}
 
-			if cmd.Flags().Changed("config") {
+			if cmd.Flags().Changed("config") || configFile != "" {
 				o.ConnectorUpdateReq.Config = configMap
 			}
 
@@ -1632,6 +2154,73 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 
 	connectorUpdate.Flags().StringP("topics", "t", "", "topics for the managed database connector")
 	connectorUpdate.Flags().StringP("config", "c", "", "configuration json for the managed database connector")
+	connectorUpdate.Flags().StringP(
+		"config-file",
+		"f",
+		"",
+		"(optional) a YAML or JSON file of configuration for the managed database connector",
+	)
+	connectorUpdate.Flags().Bool(
+		"no-validate",
+		false,
+		"(optional) skip validating the configuration file against the connector configuration schema",
+	)
+	connectorUpdate.MarkFlagsMutuallyExclusive("config", "config-file")
+
+	// Connector Watch
+	connectorWatch := &cobra.Command{
+		Use:     "watch <Database ID> <Connector Name>",
+		Short:   "Watch the status of a database connector",
+		Long:    connectorWatchLong,
+		Example: connectorWatchExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("please provide a database ID and a connector name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			autoRestart, errAu := cmd.Flags().GetBool("auto-restart")
+			if errAu != nil {
+				return fmt.Errorf("error parsing flag 'auto-restart' for database connector watch : %v", errAu)
+			}
+
+			maxRestarts, errMa := cmd.Flags().GetInt("max-restarts")
+			if errMa != nil {
+				return fmt.Errorf("error parsing flag 'max-restarts' for database connector watch : %v", errMa)
+			}
+
+			interval, errIn := cmd.Flags().GetInt("interval")
+			if errIn != nil {
+				return fmt.Errorf("error parsing flag 'interval' for database connector watch : %v", errIn)
+			}
+
+			if interval < 1 {
+				return errors.New("error parsing flag 'interval' for database connector watch : must be at least 1 second")
+			}
+
+			// JSON and YAML output exit once displayed, which would stop the watch
+			if !o.Base.Printer.IsText() {
+				return errors.New("database connector watch is only supported with text output")
+			}
+
+			w := newConnectorWatcher(autoRestart, maxRestarts)
+			return o.watchConnector(w, time.Duration(interval)*time.Second)
+		},
+	}
+
+	connectorWatch.Flags().Bool("auto-restart", false, "(optional) restart failed tasks")
+	connectorWatch.Flags().Int(
+		"max-restarts",
+		connectorDefaultMaxRestarts,
+		"(optional) the number of times each failed task is restarted with --auto-restart",
+	)
+	connectorWatch.Flags().IntP(
+		"interval",
+		"i",
+		connectorDefaultWatchInterval,
+		"(optional) number of seconds between status checks",
+	)
 
 	// Connector Delete
 	connectorDelete := &cobra.Command{
@@ -1772,6 +2361,7 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		connectorPause,
 		connectorResume,
 		connectorRestartTask,
+		connectorWatch,
 	)
 
 	// Usage
@@ -1916,6 +2506,152 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		alertList,
 	)
 
+	// Fleet
+	fleet := &cobra.Command{
+		Use:   "fleet",
+		Short: "Commands to report on and maintain all managed databases",
+	}
+
+	// Fleet Report
+	fleetReport := &cobra.Command{
+		Use:     "report",
+		Short:   "Report pending maintenance, upgrades, alerts and disk usage of all databases",
+		Long:    fleetReportLong,
+		Example: fleetReportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, errFi := fleetFilterFlags(cmd)
+			if errFi != nil {
+				return errFi
+			}
+
+			period, errPe := cmd.Flags().GetString("alert-period")
+			if errPe != nil {
+				return fmt.Errorf("error parsing flag 'alert-period' for fleet report : %v", errPe)
+			}
+
+			maxDisk, errMd := cmd.Flags().GetFloat32("max-disk")
+			if errMd != nil {
+				return fmt.Errorf("error parsing flag 'max-disk' for fleet report : %v", errMd)
+			}
+
+			maxAlerts, errMa := cmd.Flags().GetInt("max-alerts")
+			if errMa != nil {
+				return fmt.Errorf("error parsing flag 'max-alerts' for fleet report : %v", errMa)
+			}
+
+			failMaint, errFm := cmd.Flags().GetBool("fail-on-maintenance")
+			if errFm != nil {
+				return fmt.Errorf("error parsing flag 'fail-on-maintenance' for fleet report : %v", errFm)
+			}
+
+			failUpgrade, errFu := cmd.Flags().GetBool("fail-on-upgrade")
+			if errFu != nil {
+				return fmt.Errorf("error parsing flag 'fail-on-upgrade' for fleet report : %v", errFu)
+			}
+
+			thresholds := &fleetThresholds{
+				MaxDisk:           maxDisk,
+				MaxAlerts:         maxAlerts,
+				FailOnMaintenance: failMaint,
+				FailOnUpgrade:     failUpgrade,
+			}
+
+			entries, err := o.fleetReport(filter, period, thresholds)
+			if entries == nil {
+				return err
+			}
+
+			return o.Base.Printer.DisplayWithError(&FleetReportPrinter{Databases: entries}, err)
+		},
+	}
+
+	addFleetFilterFlags(fleetReport)
+	fleetReport.Flags().String(
+		"alert-period",
+		fleetDefaultAlertPeriod,
+		"(optional) period (day, week, month, year) of service alerts to count",
+	)
+	fleetReport.Flags().Float32(
+		"max-disk",
+		0,
+		"(optional) exit non-zero when the disk usage percentage of a database exceeds this value",
+	)
+	fleetReport.Flags().Int(
+		"max-alerts",
+		-1,
+		"(optional) exit non-zero when a database has more alerts in the period than this value",
+	)
+	fleetReport.Flags().Bool(
+		"fail-on-maintenance",
+		false,
+		"(optional) exit non-zero when a database has pending maintenance",
+	)
+	fleetReport.Flags().Bool(
+		"fail-on-upgrade",
+		false,
+		"(optional) exit non-zero when a database has a version upgrade available",
+	)
+
+	// Fleet Maintenance
+	fleetMaintenance := &cobra.Command{
+		Use:     "maintenance",
+		Short:   "Start maintenance on all databases with pending updates",
+		Long:    fleetMaintenanceLong,
+		Example: fleetMaintenanceExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, errFi := fleetFilterFlags(cmd)
+			if errFi != nil {
+				return errFi
+			}
+
+			window, errWi := cmd.Flags().GetString("window")
+			if errWi != nil {
+				return fmt.Errorf("error parsing flag 'window' for fleet maintenance : %v", errWi)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for fleet maintenance : %v", errDr)
+			}
+
+			if filter.Tag == "" && filter.Engine == "" {
+				return errors.New("please provide a --tag or --engine to select the databases")
+			}
+
+			var w *maintenanceWindow
+			if window != "" {
+				var err error
+				if w, err = parseMaintenanceWindow(window); err != nil {
+					return fmt.Errorf("error parsing flag 'window' for fleet maintenance : %v", err)
+				}
+			}
+
+			results, err := o.fleetMaintenance(filter, w, dryRun)
+			if results == nil {
+				return err
+			}
+
+			return o.Base.Printer.DisplayWithError(&FleetMaintenancePrinter{Results: results}, err)
+		},
+	}
+
+	addFleetFilterFlags(fleetMaintenance)
+	fleetMaintenance.Flags().String(
+		"window",
+		"",
+		"(optional) UTC window in the form HH:MM-HH:MM outside of which no maintenance is started",
+	)
+	fleetMaintenance.Flags().Bool(
+		"dry-run",
+		false,
+		"(optional) show the databases with pending maintenance without starting it",
+	)
+
+	fleet.AddCommand(
+		fleetReport,
+		fleetMaintenance,
+	)
+
 	// Migration
 	migration := &cobra.Command{
 		Use:   "migration",
@@ -2054,9 +2790,136 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		},
 	}
 
+	// Migration Run
+	migrationRun := &cobra.Command{
+		Use:     "run <Database ID>",
+		Short:   "Check the source, start a migration and follow it to completion",
+		Long:    migrationRunLong,
+		Example: migrationRunExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			host, errHo := cmd.Flags().GetString("host")
+			if errHo != nil {
+				return fmt.Errorf("error parsing flag 'host' for migration run : %v", errHo)
+			}
+
+			port, errPo := cmd.Flags().GetInt("port")
+			if errPo != nil {
+				return fmt.Errorf("error parsing flag 'port' for migration run : %v", errPo)
+			}
+
+			username, errUs := cmd.Flags().GetString("username")
+			if errUs != nil {
+				return fmt.Errorf("error parsing flag 'username' for migration run : %v", errUs)
+			}
+
+			password, errPa := cmd.Flags().GetString("password")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'password' for migration run : %v", errPa)
+			}
+
+			database, errDa := cmd.Flags().GetString("database")
+			if errDa != nil {
+				return fmt.Errorf("error parsing flag 'database' for migration run : %v", errDa)
+			}
+
+			ignored, errIg := cmd.Flags().GetString("ignored-dbs")
+			if errIg != nil {
+				return fmt.Errorf("error parsing flag 'ignored-dbs' for migration run : %v", errIg)
+			}
+
+			ssl, errSs := cmd.Flags().GetBool("ssl")
+			if errSs != nil {
+				return fmt.Errorf("error parsing flag 'ssl' for migration run : %v", errSs)
+			}
+
+			skipPreflight, errSk := cmd.Flags().GetBool("skip-preflight")
+			if errSk != nil {
+				return fmt.Errorf("error parsing flag 'skip-preflight' for migration run : %v", errSk)
+			}
+
+			detach, errDe := cmd.Flags().GetBool("detach")
+			if errDe != nil {
+				return fmt.Errorf("error parsing flag 'detach' for migration run : %v", errDe)
+			}
+
+			interval, errIn := cmd.Flags().GetInt("interval")
+			if errIn != nil {
+				return fmt.Errorf("error parsing flag 'interval' for migration run : %v", errIn)
+			}
+
+			if interval < 1 {
+				return errors.New("error parsing flag 'interval' for migration run : must be at least 1 second")
+			}
+
+			db, err := o.get()
+			if err != nil {
+				return fmt.Errorf("error retrieving database : %v", err)
+			}
+
+			o.MigrationReq = &govultr.DatabaseMigrationStartReq{
+				Host:             host,
+				Port:             port,
+				Username:         username,
+				Password:         password,
+				Database:         database,
+				IgnoredDatabases: ignored,
+				SSL:              &ssl,
+			}
+
+			run, err := o.runMigration(db.DatabaseEngine, skipPreflight, time.Duration(interval)*time.Second)
+			if err != nil {
+				return err
+			}
+
+			if err := o.finishMigration(run, detach); err != nil {
+				return err
+			}
+
+			data := &MigrationRunPrinter{Run: run}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	migrationRun.Flags().String("host", "", "source host for the managed database migration")
+	migrationRun.Flags().Int("port", 0, "source port for the managed database migration")
+	migrationRun.Flags().String(
+		"username",
+		"",
+		"source username for the managed database migration (uses `default` for caching databases if omitted)",
+	)
+	migrationRun.Flags().String("password", "", "source password for the managed database migration")
+	migrationRun.Flags().String(
+		"database",
+		"",
+		"source database for the managed database migration (MySQL/PostgreSQL only)",
+	)
+	migrationRun.Flags().String(
+		"ignored-dbs",
+		"",
+		"comma-separated list of ignored databases for the managed database migration (MySQL/PostgreSQL only)",
+	)
+	migrationRun.Flags().Bool("ssl", true, "source ssl requirement for the managed database migration")
+	migrationRun.Flags().Bool("skip-preflight", false, "(optional) start the migration without checking the source")
+	migrationRun.Flags().Bool("detach", false, "(optional) detach the migration once it completes without prompting")
+	migrationRun.Flags().IntP(
+		"interval",
+		"i",
+		migrationDefaultInterval,
+		"(optional) number of seconds between status checks",
+	)
+
 	migration.AddCommand(
 		migrationGet,
 		migrationStart,
+		migrationRun,
 		migrationDetach,
 	)
 
@@ -2124,23 +2987,120 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			if len(args) != 1 {
 				return errors.New("please provide a database ID")
 			}
-			return nil
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := o.promoteReadReplica(); err != nil {
-				return fmt.Errorf("error promoting database read replica: %v", err)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.promoteReadReplica(); err != nil {
+				return fmt.Errorf("error promoting database read replica: %v", err)
+			}
+
+			o.Base.Printer.Display(printer.Info("Read replica has been promoted"), nil)
+
+			return nil
+		},
+	}
+
+	readReplica.AddCommand(
+		readReplicaCreate,
+		readReplicaPromote,
+	)
+
+	// Failover
+	failover := &cobra.Command{
+		Use:     "failover <Primary ID>",
+		Short:   "Fail over from a database to one of its read replicas",
+		Long:    failoverLong,
+		Example: failoverExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a primary database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			replica, errRe := cmd.Flags().GetString("replica")
+			if errRe != nil {
+				return fmt.Errorf("error parsing flag 'replica' for database failover : %v", errRe)
+			}
+
+			maxLag, errMa := cmd.Flags().GetInt("max-lag")
+			if errMa != nil {
+				return fmt.Errorf("error parsing flag 'max-lag' for database failover : %v", errMa)
+			}
+
+			force, errFo := cmd.Flags().GetBool("force")
+			if errFo != nil {
+				return fmt.Errorf("error parsing flag 'force' for database failover : %v", errFo)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for database failover : %v", errDr)
+			}
+
+			dnsDomain, errDd := cmd.Flags().GetString("dns-domain")
+			if errDd != nil {
+				return fmt.Errorf("error parsing flag 'dns-domain' for database failover : %v", errDd)
+			}
+
+			dnsRecord, errDn := cmd.Flags().GetString("dns-record")
+			if errDn != nil {
+				return fmt.Errorf("error parsing flag 'dns-record' for database failover : %v", errDn)
 			}
 
-			o.Base.Printer.Display(printer.Info("Read replica has been promoted"), nil)
+			configFile, errCo := cmd.Flags().GetString("config-file")
+			if errCo != nil {
+				return fmt.Errorf("error parsing flag 'config-file' for database failover : %v", errCo)
+			}
 
-			return nil
+			auditLog, errAu := cmd.Flags().GetString("audit-log")
+			if errAu != nil {
+				return fmt.Errorf("error parsing flag 'audit-log' for database failover : %v", errAu)
+			}
+
+			f := o.newFailover(&failoverOptions{
+				ReplicaID:  replica,
+				MaxLag:     maxLag,
+				Force:      force,
+				DryRun:     dryRun,
+				DNSDomain:  dnsDomain,
+				DNSRecord:  dnsRecord,
+				ConfigFile: configFile,
+				AuditLog:   auditLog,
+			})
+
+			// text output shows each step as it runs
+			steps, err := f.run()
+			if o.Base.Printer.IsText() {
+				return err
+			}
+
+			return o.Base.Printer.DisplayWithError(&FailoverStepsPrinter{Steps: steps}, err)
 		},
 	}
 
-	readReplica.AddCommand(
-		readReplicaCreate,
-		readReplicaPromote,
+	failover.Flags().String("replica", "", "ID of the read replica to promote")
+	if err := failover.MarkFlagRequired("replica"); err != nil {
+		fmt.Printf("error marking database failover 'replica' flag required: %v", err)
+		os.Exit(1)
+	}
+
+	failover.Flags().Int(
+		"max-lag",
+		failoverDefaultMaxLag,
+		"(optional) maximum replication lag allowed before promoting the replica, in seconds or in bytes for valkey",
+	)
+	failover.Flags().Bool("force", false, "(optional) fail over even if the replication lag check fails")
+	failover.Flags().Bool("dry-run", false, "(optional) run the checks and show the planned changes without applying them")
+	failover.Flags().String("dns-domain", "", "(optional) DNS domain of the record to point at the new primary")
+	failover.Flags().String("dns-record", "", "(optional) name or ID of the CNAME or A record to point at the new primary")
+	failover.Flags().String(
+		"config-file",
+		"",
+		"(optional) connection pool or application configuration file to point at the new primary",
 	)
+	failover.Flags().String("audit-log", "", "(optional) file to append each step to as a JSON line")
+	failover.MarkFlagsRequiredTogether("dns-domain", "dns-record")
 
 	// Backup
 	backup := &cobra.Command{
@@ -2173,8 +3133,10 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 
 	// Backup Restore
 	backupRestore := &cobra.Command{
-		Use:   "restore <Database ID>",
-		Short: "Restore a database backup",
+		Use:     "restore <Database ID>",
+		Short:   "Restore a database backup",
+		Long:    backupRestoreLong,
+		Example: backupRestoreExample,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return errors.New("please provide a database ID")
@@ -2197,16 +3159,45 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error parsing flag 'date' for backup restore : %v", errDa)
 			}
 
-			time, errTi := cmd.Flags().GetString("time")
+			rtime, errTi := cmd.Flags().GetString("time")
 			if errTi != nil {
 				return fmt.Errorf("error parsing flag 'time' for backup restore : %v", errTi)
 			}
 
+			at, errAt := cmd.Flags().GetString("at")
+			if errAt != nil {
+				return fmt.Errorf("error parsing flag 'at' for backup restore : %v", errAt)
+			}
+
+			wait, errWa := cmd.Flags().GetBool("wait")
+			if errWa != nil {
+				return fmt.Errorf("error parsing flag 'wait' for backup restore : %v", errWa)
+			}
+
+			timeout, errTo := cmd.Flags().GetInt("wait-timeout")
+			if errTo != nil {
+				return fmt.Errorf("error parsing flag 'wait-timeout' for backup restore : %v", errTo)
+			}
+
+			if at != "" {
+				db, err := o.get()
+				if err != nil {
+					return fmt.Errorf("error retrieving database : %v", err)
+				}
+
+				point, err := o.planRestoreAt(at, db.DatabaseEngine)
+				if err != nil {
+					return err
+				}
+
+				rtype, date, rtime = point.Type, point.Date, point.Time
+			}
+
 			o.BackupReq = &govultr.DatabaseBackupRestoreReq{
 				Label: label,
 				Type:  rtype,
 				Date:  date,
-				Time:  time,
+				Time:  rtime,
 			}
 
 			bk, err := o.restoreBackup()
@@ -2214,6 +3205,17 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error restoring database from backup : %v", err)
 			}
 
+			if wait {
+				bk, err = o.waitForDatabase(
+					bk.ID,
+					backupDefaultWaitInterval*time.Second,
+					time.Duration(timeout)*time.Minute,
+				)
+				if err != nil {
+					return err
+				}
+			}
+
 			data := &DBPrinter{DB: bk}
 			o.Base.Printer.Display(data, nil)
 
@@ -2234,11 +3236,27 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	)
 	backupRestore.Flags().String("date", "", "backup date to use for point-in-time recovery")
 	backupRestore.Flags().String("time", "", "backup time to use for point-in-time recovery")
+	backupRestore.Flags().String(
+		"at",
+		"",
+		"(optional) RFC 3339 time to restore to, choosing the restoration type, date and time automatically",
+	)
+	backupRestore.Flags().Bool("wait", false, "(optional) wait until the restored database is running")
+	backupRestore.Flags().Int(
+		"wait-timeout",
+		backupDefaultWaitTimeout,
+		"(optional) number of minutes to wait with --wait",
+	)
+	backupRestore.MarkFlagsMutuallyExclusive("at", "type")
+	backupRestore.MarkFlagsMutuallyExclusive("at", "date")
+	backupRestore.MarkFlagsMutuallyExclusive("at", "time")
 
 	// Backup Fork
 	backupFork := &cobra.Command{
-		Use:   "fork <Database ID>",
-		Short: "Fork a database from backup",
+		Use:     "fork <Database ID>",
+		Short:   "Fork a database from backup",
+		Long:    backupForkLong,
+		Example: backupForkExample,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return errors.New("please provide a database ID")
@@ -2271,18 +3289,52 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error parsing flag 'date' for backup fork: %v", errDa)
 			}
 
-			time, errTi := cmd.Flags().GetString("time")
+			rtime, errTi := cmd.Flags().GetString("time")
 			if errTi != nil {
 				return fmt.Errorf("error parsing flag 'time' for backup fork: %v", errTi)
 			}
 
+			at, errAt := cmd.Flags().GetString("at")
+			if errAt != nil {
+				return fmt.Errorf("error parsing flag 'at' for backup fork : %v", errAt)
+			}
+
+			wait, errWa := cmd.Flags().GetBool("wait")
+			if errWa != nil {
+				return fmt.Errorf("error parsing flag 'wait' for backup fork : %v", errWa)
+			}
+
+			timeout, errTo := cmd.Flags().GetInt("wait-timeout")
+			if errTo != nil {
+				return fmt.Errorf("error parsing flag 'wait-timeout' for backup fork : %v", errTo)
+			}
+
+			if at != "" || region == "" || plan == "" {
+				src, err := o.get()
+				if err != nil {
+					return fmt.Errorf("error retrieving database : %v", err)
+				}
+
+				region = printer.ValueOr(region, src.Region)
+				plan = printer.ValueOr(plan, src.Plan)
+
+				if at != "" {
+					point, err := o.planRestoreAt(at, src.DatabaseEngine)
+					if err != nil {
+						return err
+					}
+
+					rtype, date, rtime = point.Type, point.Date, point.Time
+				}
+			}
+
 			o.ForkReq = &govultr.DatabaseForkReq{
 				Label:  label,
 				Region: region,
 				Plan:   plan,
 				Type:   rtype,
 				Date:   date,
-				Time:   time,
+				Time:   rtime,
 			}
 
 			db, err := o.fork()
@@ -2290,6 +3342,17 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error forking database from backup : %v", err)
 			}
 
+			if wait {
+				db, err = o.waitForDatabase(
+					db.ID,
+					backupDefaultWaitInterval*time.Second,
+					time.Duration(timeout)*time.Minute,
+				)
+				if err != nil {
+					return err
+				}
+			}
+
 			data := &DBPrinter{DB: db}
 			o.Base.Printer.Display(data, nil)
 
@@ -2303,17 +3366,16 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		os.Exit(1)
 	}
 
-	backupFork.Flags().String("region", "", "region id for the new managed database forked from the backup")
-	if err := backupFork.MarkFlagRequired("region"); err != nil {
-		fmt.Printf("error marking backup fork 'region' flag required: %v", err)
-		os.Exit(1)
-	}
-
-	backupFork.Flags().String("plan", "", "plan id for the new managed database forked from the backup")
-	if err := backupFork.MarkFlagRequired("plan"); err != nil {
-		fmt.Printf("error marking backup fork 'label' flag required: %v", err)
-		os.Exit(1)
-	}
+	backupFork.Flags().String(
+		"region",
+		"",
+		"(optional) region id for the new managed database forked from the backup, defaults to the source region",
+	)
+	backupFork.Flags().String(
+		"plan",
+		"",
+		"(optional) plan id for the new managed database forked from the backup, defaults to the source plan",
+	)
 
 	backupFork.Flags().String(
 		"type",
@@ -2322,6 +3384,20 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	)
 	backupFork.Flags().String("date", "", "backup date to use for point-in-time recovery")
 	backupFork.Flags().String("time", "", "backup time to use for point-in-time recovery")
+	backupFork.Flags().String(
+		"at",
+		"",
+		"(optional) RFC 3339 time to fork from, choosing the restoration type, date and time automatically",
+	)
+	backupFork.Flags().Bool("wait", false, "(optional) wait until the forked database is running")
+	backupFork.Flags().Int(
+		"wait-timeout",
+		backupDefaultWaitTimeout,
+		"(optional) number of minutes to wait with --wait",
+	)
+	backupFork.MarkFlagsMutuallyExclusive("at", "type")
+	backupFork.MarkFlagsMutuallyExclusive("at", "date")
+	backupFork.MarkFlagsMutuallyExclusive("at", "time")
 
 	backup.AddCommand(
 		backupGet,
@@ -2561,12 +3637,107 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		},
 	}
 
+	// Connection Pool Plan
+	connectionPoolPlan := &cobra.Command{
+		Use:     "plan <Database ID>",
+		Short:   "Propose or apply connection pool sizes within the connection limit of the plan",
+		Long:    connectionPoolPlanLong,
+		Example: connectionPoolPlanExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, errTa := cmd.Flags().GetStringSlice("pool")
+			if errTa != nil {
+				return fmt.Errorf("error parsing flag 'pool' for connection pool plan : %v", errTa)
+			}
+
+			mode, errMo := cmd.Flags().GetString("mode")
+			if errMo != nil {
+				return fmt.Errorf("error parsing flag 'mode' for connection pool plan : %v", errMo)
+			}
+
+			size, errSi := cmd.Flags().GetInt("size")
+			if errSi != nil {
+				return fmt.Errorf("error parsing flag 'size' for connection pool plan : %v", errSi)
+			}
+
+			reserved, errRe := cmd.Flags().GetInt("reserved")
+			if errRe != nil {
+				return fmt.Errorf("error parsing flag 'reserved' for connection pool plan : %v", errRe)
+			}
+
+			apply, errAp := cmd.Flags().GetBool("apply")
+			if errAp != nil {
+				return fmt.Errorf("error parsing flag 'apply' for connection pool plan : %v", errAp)
+			}
+
+			if err := validatePoolMode(mode); err != nil {
+				return err
+			}
+
+			if size < 0 || reserved < 0 {
+				return errors.New("pool size and reserved connections cannot be negative")
+			}
+
+			plan, err := o.planConnectionPools(&poolPlanOptions{
+				Targets:  targets,
+				Mode:     mode,
+				Size:     size,
+				Reserved: reserved,
+			})
+			if err != nil {
+				return err
+			}
+
+			if apply {
+				if err := o.applyPoolPlan(plan); err != nil {
+					return err
+				}
+			}
+
+			data := &PoolPlanPrinter{Plan: plan}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	connectionPoolPlan.Flags().StringSliceP(
+		"pool",
+		"p",
+		[]string{},
+		"(optional) database:user pairs to plan pools for, defaults to every database with the default user",
+	)
+	connectionPoolPlan.Flags().StringP(
+		"mode",
+		"m",
+		poolDefaultMode,
+		"(optional) mode for the planned pools (session, transaction, statement)",
+	)
+	connectionPoolPlan.Flags().IntP(
+		"size",
+		"s",
+		0,
+		"(optional) size for each planned pool, defaults to (vCPUs * 2) + 1 within the remaining budget",
+	)
+	connectionPoolPlan.Flags().Int(
+		"reserved",
+		0,
+		"(optional) number of connections to keep free for clients which do not use a pool",
+	)
+	connectionPoolPlan.Flags().Bool("apply", false, "(optional) create and update the planned pools")
+
 	connectionPool.AddCommand(
 		connectionPoolList,
 		connectionPoolGet,
 		connectionPoolCreate,
 		connectionPoolUpdate,
 		connectionPoolDelete,
+		connectionPoolPlan,
 	)
 
 	// Advanced Option
@@ -2609,6 +3780,25 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, errFf := cmd.Flags().GetString("from-file")
+			if errFf != nil {
+				return fmt.Errorf("error parsing flag 'from-file' for advanced options update : %v", errFf)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for advanced options update : %v", errDr)
+			}
+
+			if fromFile != "" {
+				if optionFlagsChanged(cmd) {
+					return errors.New("option flags cannot be combined with --from-file")
+				}
+				return o.updateAdvancedOptionsFromFile(advancedOptionsDatabase, fromFile, dryRun)
+			} else if dryRun {
+				return errors.New("--dry-run can only be used with --from-file")
+			}
+
 			// MySQL and PostgreSQL flags
 			autovacuumAnalyzeScaleFactor, errAu := cmd.Flags().GetFloat32("autovacuum-analyze-scale-factor")
 			if errAu != nil {
@@ -4352,6 +5542,44 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		"set the managed kafka configuration value for transaction_partition_verification_enable",
 	)
 
+	advancedOptionUpdate.Flags().String(
+		"from-file",
+		"",
+		"(optional) a YAML or JSON file of option names and values to apply instead of option flags",
+	)
+	advancedOptionUpdate.Flags().Bool(
+		"dry-run",
+		false,
+		"(optional) with --from-file, show the changes without applying them",
+	)
+
+	// Advanced Option Export
+	advancedOptionExport := &cobra.Command{
+		Use:   "export <Database ID>",
+		Short: "Export the configured advanced options of a managed database",
+		Long:  advancedOptionExportLong,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'output-file' for advanced options export : %v", errPa)
+			}
+
+			return o.exportAdvancedOptions(advancedOptionsDatabase, path)
+		},
+	}
+
+	advancedOptionExport.Flags().String(
+		"output-file",
+		"",
+		"(optional) the file path to write the options to. A .json extension writes JSON, otherwise YAML",
+	)
+
 	// Advanced Option Kafka REST
 	advancedOptionKafkaREST := &cobra.Command{
 		Use:   "kafka-rest",
@@ -4392,6 +5620,25 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, errFf := cmd.Flags().GetString("from-file")
+			if errFf != nil {
+				return fmt.Errorf("error parsing flag 'from-file' for Kafka REST advanced options update : %v", errFf)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for Kafka REST advanced options update : %v", errDr)
+			}
+
+			if fromFile != "" {
+				if optionFlagsChanged(cmd) {
+					return errors.New("option flags cannot be combined with --from-file")
+				}
+				return o.updateAdvancedOptionsFromFile(advancedOptionsKafkaREST, fromFile, dryRun)
+			} else if dryRun {
+				return errors.New("--dry-run can only be used with --from-file")
+			}
+
 			producerAcks, err := cmd.Flags().GetString("producer-acks")
 			if err != nil {
 				return fmt.Errorf(
@@ -4586,9 +5833,48 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		"set the managed database Kafka REST configuration value for simpleconsumer_pool_size_max",
 	)
 
+	advancedOptionKafkaRESTUpdate.Flags().String(
+		"from-file",
+		"",
+		"(optional) a YAML or JSON file of option names and values to apply instead of option flags",
+	)
+	advancedOptionKafkaRESTUpdate.Flags().Bool(
+		"dry-run",
+		false,
+		"(optional) with --from-file, show the changes without applying them",
+	)
+
+	// Advanced Option Kafka REST Export
+	advancedOptionKafkaRESTExport := &cobra.Command{
+		Use:   "export <Database ID>",
+		Short: "Export the configured Kafka REST advanced options of a managed database",
+		Long:  advancedOptionExportLong,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'output-file' for Kafka REST advanced options export : %v", errPa)
+			}
+
+			return o.exportAdvancedOptions(advancedOptionsKafkaREST, path)
+		},
+	}
+
+	advancedOptionKafkaRESTExport.Flags().String(
+		"output-file",
+		"",
+		"(optional) the file path to write the options to. A .json extension writes JSON, otherwise YAML",
+	)
+
 	advancedOptionKafkaREST.AddCommand(
 		advancedOptionKafkaRESTList,
 		advancedOptionKafkaRESTUpdate,
+		advancedOptionKafkaRESTExport,
 	)
 
 	// Advanced Option Schema Registry
@@ -4631,6 +5917,25 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, errFf := cmd.Flags().GetString("from-file")
+			if errFf != nil {
+				return fmt.Errorf("error parsing flag 'from-file' for Schema Registry advanced options update : %v", errFf)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for Schema Registry advanced options update : %v", errDr)
+			}
+
+			if fromFile != "" {
+				if optionFlagsChanged(cmd) {
+					return errors.New("option flags cannot be combined with --from-file")
+				}
+				return o.updateAdvancedOptionsFromFile(advancedOptionsSchemaRegistry, fromFile, dryRun)
+			} else if dryRun {
+				return errors.New("--dry-run can only be used with --from-file")
+			}
+
 			leaderEligibility, err := cmd.Flags().GetBool("leader-eligibility")
 			if err != nil {
 				return fmt.Errorf(
@@ -4699,9 +6004,48 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		"set the managed database Kafka REST configuration value for retriable_errors_silenced",
 	)
 
+	advancedOptionSchemaRegistryUpdate.Flags().String(
+		"from-file",
+		"",
+		"(optional) a YAML or JSON file of option names and values to apply instead of option flags",
+	)
+	advancedOptionSchemaRegistryUpdate.Flags().Bool(
+		"dry-run",
+		false,
+		"(optional) with --from-file, show the changes without applying them",
+	)
+
+	// Advanced Option Schema Registry Export
+	advancedOptionSchemaRegistryExport := &cobra.Command{
+		Use:   "export <Database ID>",
+		Short: "Export the configured Schema Registry advanced options of a managed database",
+		Long:  advancedOptionExportLong,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'output-file' for Schema Registry advanced options export : %v", errPa)
+			}
+
+			return o.exportAdvancedOptions(advancedOptionsSchemaRegistry, path)
+		},
+	}
+
+	advancedOptionSchemaRegistryExport.Flags().String(
+		"output-file",
+		"",
+		"(optional) the file path to write the options to. A .json extension writes JSON, otherwise YAML",
+	)
+
 	advancedOptionSchemaRegistry.AddCommand(
 		advancedOptionSchemaRegistryList,
 		advancedOptionSchemaRegistryUpdate,
+		advancedOptionSchemaRegistryExport,
 	)
 
 	// Advanced Option Kafka Connect
@@ -4744,6 +6088,25 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fromFile, errFf := cmd.Flags().GetString("from-file")
+			if errFf != nil {
+				return fmt.Errorf("error parsing flag 'from-file' for Kafka Connect advanced options update : %v", errFf)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for Kafka Connect advanced options update : %v", errDr)
+			}
+
+			if fromFile != "" {
+				if optionFlagsChanged(cmd) {
+					return errors.New("option flags cannot be combined with --from-file")
+				}
+				return o.updateAdvancedOptionsFromFile(advancedOptionsKafkaConnect, fromFile, dryRun)
+			} else if dryRun {
+				return errors.New("--dry-run can only be used with --from-file")
+			}
+
 			connectorClientConfigOverridePolicy, err := cmd.Flags().GetString("connector-client-config-override-policy")
 			if err != nil {
 				return fmt.Errorf(
@@ -5046,14 +6409,54 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		"set the managed database Kafka Connect configuration value for session_timeout_ms",
 	)
 
+	advancedOptionKafkaConnectUpdate.Flags().String(
+		"from-file",
+		"",
+		"(optional) a YAML or JSON file of option names and values to apply instead of option flags",
+	)
+	advancedOptionKafkaConnectUpdate.Flags().Bool(
+		"dry-run",
+		false,
+		"(optional) with --from-file, show the changes without applying them",
+	)
+
+	// Advanced Option Kafka Connect Export
+	advancedOptionKafkaConnectExport := &cobra.Command{
+		Use:   "export <Database ID>",
+		Short: "Export the configured Kafka Connect advanced options of a managed database",
+		Long:  advancedOptionExportLong,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("please provide a database ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing flag 'output-file' for Kafka Connect advanced options export : %v", errPa)
+			}
+
+			return o.exportAdvancedOptions(advancedOptionsKafkaConnect, path)
+		},
+	}
+
+	advancedOptionKafkaConnectExport.Flags().String(
+		"output-file",
+		"",
+		"(optional) the file path to write the options to. A .json extension writes JSON, otherwise YAML",
+	)
+
 	advancedOptionKafkaConnect.AddCommand(
 		advancedOptionKafkaConnectList,
 		advancedOptionKafkaConnectUpdate,
+		advancedOptionKafkaConnectExport,
 	)
 
 	advancedOption.AddCommand(
 		advancedOptionList,
 		advancedOptionUpdate,
+		advancedOptionExport,
 		advancedOptionKafkaREST,
 		advancedOptionSchemaRegistry,
 		advancedOptionKafkaConnect,
@@ -5136,18 +6539,22 @@ func NewCmdDatabase(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		create,
 		update,
 		del,
+		connect,
 		user,
 		db,
 		topic,
 		quota,
+		kafka,
 		availableConnector,
 		connector,
 		usage,
 		maintenance,
 		plan,
 		alert,
+		fleet,
 		migration,
 		readReplica,
+		failover,
 		backup,
 		connectionPool,
 		advancedOption,
@@ -5209,6 +6616,11 @@ func (o *options) del() error {
 	return o.Base.Client.Database.Delete(o.Base.Context, o.Base.Args[0])
 }
 
+func (o *options) getConnectUser(username string) (*govultr.DatabaseUser, error) {
+	user, _, err := o.Base.Client.Database.GetUser(o.Base.Context, o.Base.Args[0], username)
+	return user, err
+}
+
 func (o *options) listPlans() ([]govultr.DatabasePlan, *govultr.Meta, error) {
 	plans, meta, _, err := o.Base.Client.Database.ListPlans(o.Base.Context, nil)
 	return plans, meta, err
@@ -0,0 +1,443 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	failoverDefaultMaxLag  = 30
+	failoverWaitInterval   = 15 * time.Second
+	failoverWaitTimeout    = 30 * time.Minute
+	failoverAuditFilePerms = 0600
+
+	stepPassed  = "ok"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+	stepPlanned = "planned"
+
+	lagUnitSeconds = "s"
+	lagUnitBytes   = " bytes"
+)
+
+// pgLagQuery returns the replay delay of a PostgreSQL replica in seconds, or 0
+// when it has replayed everything it received so an idle primary shows no lag
+const pgLagQuery = "select case when pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() then 0 " +
+	"else coalesce(extract(epoch from now() - pg_last_xact_replay_timestamp()), 0)::int end"
+
+var (
+	mysqlLagPattern    = regexp.MustCompile(`Seconds_Behind_(?:Source|Master):\s*(\S+)`)
+	valkeyFieldPattern = regexp.MustCompile(`(?m)^(\w+):(\S+)`)
+)
+
+// FailoverStep records a single step of a failover for the audit log
+type FailoverStep struct {
+	Time   string `json:"time"`
+	Step   string `json:"step"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// failoverOptions holds the flags of database failover
+type failoverOptions struct {
+	ReplicaID  string
+	MaxLag     int
+	Force      bool
+	DryRun     bool
+	DNSDomain  string
+	DNSRecord  string
+	ConfigFile string
+	AuditLog   string
+}
+
+// failoverRun runs a controlled failover from a primary database to one of its
+// read replicas, recording each step
+type failoverRun struct {
+	o       *options
+	opts    *failoverOptions
+	steps   []FailoverStep
+	header  bool
+	primary *govultr.Database
+	replica *govultr.Database
+}
+
+// replicationLag measures the replication lag of a replica with the local
+// client for the engine and returns it with its unit. PostgreSQL and MySQL
+// report the lag in seconds, Valkey in bytes of replication offset behind the
+// primary
+func replicationLag(ctx context.Context, primary, replica *connection) (int, string, error) {
+	switch replica.Engine {
+	case enginePG:
+		out, err := replica.query(ctx, []string{"-At", "-c", pgLagQuery})
+		if err != nil {
+			return 0, "", err
+		}
+		return parseLag(strings.TrimSpace(out), lagUnitSeconds)
+	case engineMySQL:
+		out, err := replica.query(ctx, []string{"-e", `show replica status\G`})
+		if err != nil {
+			return 0, "", err
+		}
+		m := mysqlLagPattern.FindStringSubmatch(out)
+		if m == nil {
+			return 0, "", errors.New("replica does not report replication status")
+		}
+		return parseLag(m[1], lagUnitSeconds)
+	case engineValkey:
+		return valkeyLag(ctx, primary, replica)
+	}
+
+	return 0, "", fmt.Errorf("measuring replication lag is not supported for %s databases", replica.Engine)
+}
+
+// valkeyLag compares the replication offset of the replica with the offset
+// of the primary. The primary is queried first, so a replica which keeps up
+// has reached that offset by the time it is queried
+func valkeyLag(ctx context.Context, primary, replica *connection) (int, string, error) {
+	primaryInfo, err := valkeyReplicationInfo(ctx, primary)
+	if err != nil {
+		return 0, "", err
+	}
+
+	replicaInfo, err := valkeyReplicationInfo(ctx, replica)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if status := replicaInfo["master_link_status"]; status != "up" {
+		return 0, "", fmt.Errorf("replication is not running (master link %q)", status)
+	}
+
+	primaryOffset, errPr := strconv.Atoi(primaryInfo["master_repl_offset"])
+	replicaOffset, errRe := strconv.Atoi(replicaInfo["slave_repl_offset"])
+	if errPr != nil || errRe != nil {
+		return 0, "", errors.New("replica does not report replication status")
+	}
+
+	return max(primaryOffset-replicaOffset, 0), lagUnitBytes, nil
+}
+
+// valkeyReplicationInfo returns the fields of the replication section of
+// INFO
+func valkeyReplicationInfo(ctx context.Context, c *connection) (map[string]string, error) {
+	out, err := c.query(ctx, []string{"INFO", "replication"})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	for _, m := range valkeyFieldPattern.FindAllStringSubmatch(out, -1) {
+		fields[m[1]] = m[2]
+	}
+
+	return fields, nil
+}
+
+// parseLag parses a lag value reported by a replica
+func parseLag(value, unit string) (int, string, error) {
+	lag, err := strconv.Atoi(value)
+	if err != nil || lag < 0 {
+		return 0, "", fmt.Errorf("replication is not running (lag %q)", value)
+	}
+
+	return lag, unit, nil
+}
+
+// replaceHosts replaces the hosts of the primary with those of the replica
+// and returns the number of replacements. Only public hosts are replaced with
+// public hosts and private hosts with private hosts. The public host contains
+// the private host, so it is matched first and kept as is when the replica
+// has no public host
+func replaceHosts(content string, primary, replica *govultr.Database) (string, int) {
+	if primary.Host == "" || replica.Host == "" {
+		return content, 0
+	}
+
+	var pairs, markers []string
+	switch {
+	case primary.PublicHost != "" && replica.PublicHost != "":
+		pairs = append(pairs, primary.PublicHost, replica.PublicHost)
+		markers = append(markers, primary.PublicHost, "\x00")
+	case primary.PublicHost != "":
+		pairs = append(pairs, primary.PublicHost, primary.PublicHost)
+		markers = append(markers, primary.PublicHost, primary.PublicHost)
+	}
+	pairs = append(pairs, primary.Host, replica.Host)
+	markers = append(markers, primary.Host, "\x00")
+
+	count := strings.Count(strings.NewReplacer(markers...).Replace(content), "\x00")
+	return strings.NewReplacer(pairs...).Replace(content), count
+}
+
+// ======================================
+
+func (o *options) newFailover(opts *failoverOptions) *failoverRun {
+	return &failoverRun{o: o, opts: opts, header: true}
+}
+
+// record adds a step to the audit log, appending it to the audit log file
+// and displaying it as it happens with text output
+func (f *failoverRun) record(step, result, detail string) error {
+	s := FailoverStep{
+		Time:   time.Now().Format(time.RFC3339),
+		Step:   step,
+		Result: result,
+		Detail: detail,
+	}
+	f.steps = append(f.steps, s)
+
+	if f.o.Base.Printer.IsText() {
+		f.o.Base.Printer.Display(&FailoverStepsPrinter{Steps: []FailoverStep{s}, Header: f.header}, nil)
+		f.header = false
+	}
+
+	if f.opts.AuditLog == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Clean(f.opts.AuditLog), os.O_APPEND|os.O_CREATE|os.O_WRONLY, failoverAuditFilePerms)
+	if err != nil {
+		return fmt.Errorf("error opening audit log : %v", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing audit log : %v", err)
+	}
+
+	return nil
+}
+
+// fail records a failed step and returns it as an error
+func (f *failoverRun) fail(step string, err error) error {
+	if errRe := f.record(step, stepFailed, err.Error()); errRe != nil {
+		return errRe
+	}
+	return fmt.Errorf("failover aborted at %s : %v", step, err)
+}
+
+// run performs the failover steps in order, stopping at the first failure
+func (f *failoverRun) run() ([]FailoverStep, error) {
+	steps := []func() error{
+		f.verifyReplica,
+		f.checkLag,
+		f.promote,
+		f.updateDNS,
+		f.updateConfigFile,
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return f.steps, err
+		}
+	}
+
+	return f.steps, nil
+}
+
+// verifyReplica checks that the replica belongs to the primary and is running
+func (f *failoverRun) verifyReplica() error {
+	const step = "verify replica"
+
+	primary, err := f.o.get()
+	if err != nil {
+		return f.fail(step, fmt.Errorf("error retrieving primary database : %v", err))
+	}
+
+	replica, _, err := f.o.Base.Client.Database.Get(f.o.Base.Context, f.opts.ReplicaID)
+	if err != nil {
+		return f.fail(step, fmt.Errorf("error retrieving replica database : %v", err))
+	}
+
+	found := false
+	for i := range primary.ReadReplicas {
+		found = found || primary.ReadReplicas[i].ID == replica.ID
+	}
+
+	if !found {
+		return f.fail(step, fmt.Errorf("%s is not a read replica of %s", replica.ID, primary.ID))
+	}
+
+	if !strings.EqualFold(replica.Status, databaseStatusRunning) {
+		return f.fail(step, fmt.Errorf("replica status is %s", replica.Status))
+	}
+
+	f.primary, f.replica = primary, replica
+	detail := fmt.Sprintf("%s (%s) is a running replica of %s", replica.Label, replica.ID, primary.ID)
+	return f.record(step, stepPassed, detail)
+}
+
+// checkLag measures the replication lag of the replica. Failures abort the
+// failover unless forced
+func (f *failoverRun) checkLag() error {
+	const step = "replication lag"
+
+	replica, err := newConnection(f.replica, nil, "", false)
+	if err != nil {
+		return f.record(step, stepSkipped, err.Error())
+	}
+
+	primary, err := newConnection(f.primary, nil, "", false)
+	if err != nil {
+		return f.record(step, stepSkipped, err.Error())
+	}
+
+	lag, unit, err := replicationLag(f.o.Base.Context, primary, replica)
+	switch {
+	case errors.Is(err, errClientNotFound):
+		return f.record(step, stepSkipped, err.Error())
+	case err != nil && f.opts.Force:
+		return f.record(step, stepSkipped, fmt.Sprintf("%v (forced)", err))
+	case err != nil:
+		return f.fail(step, fmt.Errorf("%v. Use --force to fail over regardless", err))
+	case lag > f.opts.MaxLag && !f.opts.Force:
+		return f.fail(step, fmt.Errorf(
+			"lag of %d%s exceeds the maximum of %d%s. Use --force to fail over regardless",
+			lag,
+			unit,
+			f.opts.MaxLag,
+			unit,
+		))
+	}
+
+	return f.record(step, stepPassed, fmt.Sprintf("%d%s behind the primary", lag, unit))
+}
+
+// promote promotes the replica and waits until it is running as a primary
+func (f *failoverRun) promote() error {
+	const step = "promote"
+
+	if f.opts.DryRun {
+		return f.record(step, stepPlanned, "promote "+f.replica.ID)
+	}
+
+	if err := f.o.Base.Client.Database.PromoteReadReplica(f.o.Base.Context, f.replica.ID); err != nil {
+		return f.fail(step, err)
+	}
+
+	db, err := f.o.waitForDatabase(f.replica.ID, failoverWaitInterval, failoverWaitTimeout)
+	if err != nil {
+		return f.fail(step, err)
+	}
+	f.replica = db
+
+	return f.record(step, stepPassed, fmt.Sprintf("%s promoted and running", f.replica.ID))
+}
+
+// updateDNS points the DNS record at the new primary. CNAME records are set
+// to the host of the new primary and A records to its resolved address
+func (f *failoverRun) updateDNS() error {
+	const step = "dns"
+
+	if f.opts.DNSDomain == "" || f.opts.DNSRecord == "" {
+		return f.record(step, stepSkipped, "no DNS record provided")
+	}
+
+	record, err := f.findDNSRecord()
+	if err != nil {
+		return f.fail(step, err)
+	}
+
+	data := f.replica.Host
+	if strings.EqualFold(record.Type, "A") {
+		addrs, errLo := net.DefaultResolver.LookupIP(f.o.Base.Context, "ip4", f.replica.Host)
+		if errLo != nil || len(addrs) == 0 {
+			return f.fail(step, fmt.Errorf("unable to resolve %s : %v", f.replica.Host, errLo))
+		}
+		data = addrs[0].String()
+	} else if !strings.EqualFold(record.Type, "CNAME") {
+		return f.fail(step, fmt.Errorf("unable to update %s records, expected a CNAME or A record", record.Type))
+	}
+
+	detail := fmt.Sprintf("%s %s.%s : %s -> %s", record.Type, record.Name, f.opts.DNSDomain, record.Data, data)
+	if f.opts.DryRun {
+		return f.record(step, stepPlanned, detail)
+	}
+
+	if err := f.o.Base.Client.DomainRecord.Update(
+		f.o.Base.Context,
+		f.opts.DNSDomain,
+		record.ID,
+		&govultr.DomainRecordUpdateReq{Type: record.Type, Data: data, TTL: record.TTL},
+	); err != nil {
+		return f.fail(step, err)
+	}
+
+	return f.record(step, stepPassed, detail)
+}
+
+// findDNSRecord returns the record of the domain matching the record flag by
+// ID or by name
+func (f *failoverRun) findDNSRecord() (*govultr.DomainRecord, error) {
+	listOptions := &govultr.ListOptions{}
+	for {
+		records, meta, _, err := f.o.Base.Client.DomainRecord.List(f.o.Base.Context, f.opts.DNSDomain, listOptions)
+		if err != nil {
+			return nil, fmt.Errorf("error listing DNS records : %v", err)
+		}
+
+		for i := range records {
+			if records[i].ID == f.opts.DNSRecord || records[i].Name == f.opts.DNSRecord {
+				return &records[i], nil
+			}
+		}
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return nil, fmt.Errorf("DNS record %s not found in %s", f.opts.DNSRecord, f.opts.DNSDomain)
+		}
+		listOptions.Cursor = meta.Links.Next
+	}
+}
+
+// updateConfigFile replaces the hosts of the old primary with those of the
+// new primary in a connection pool or application configuration file
+func (f *failoverRun) updateConfigFile() error {
+	const step = "config file"
+
+	if f.opts.ConfigFile == "" {
+		return f.record(step, stepSkipped, "no configuration file provided")
+	}
+
+	path := filepath.Clean(f.opts.ConfigFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		return f.fail(step, err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return f.fail(step, err)
+	}
+
+	content, count := replaceHosts(string(raw), f.primary, f.replica)
+	if count == 0 {
+		return f.fail(step, fmt.Errorf("%s does not reference %s", path, f.primary.Host))
+	}
+
+	detail := fmt.Sprintf("%d references in %s updated to %s", count, path, f.replica.Host)
+	if f.opts.DryRun {
+		return f.record(step, stepPlanned, detail)
+	}
+
+	if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+		return f.fail(step, err)
+	}
+
+	return f.record(step, stepPassed, detail)
+}
@@ -0,0 +1,322 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	fleetDefaultAlertPeriod = "day"
+	fleetWindowLayout       = "15:04"
+
+	fleetMaintenanceStarted = "started"
+	fleetMaintenancePending = "pending"
+	fleetMaintenanceSkipped = "skipped"
+	fleetMaintenanceFailed  = "failed"
+)
+
+// FleetEntry summarizes the pending work and health of a single database
+type FleetEntry struct {
+	ID          string   `json:"id"`
+	Label       string   `json:"label"`
+	Engine      string   `json:"engine"`
+	Version     string   `json:"version"`
+	Region      string   `json:"region"`
+	Status      string   `json:"status"`
+	Maintenance []string `json:"maintenance"`
+	Upgrades    []string `json:"upgrades"`
+	Alerts      int      `json:"alerts"`
+	LatestAlert string   `json:"latest_alert,omitempty"`
+	DiskPercent float32  `json:"disk_percent"`
+	Breaches    []string `json:"breaches,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// fleetFilter selects the databases of the fleet
+type fleetFilter struct {
+	Tag    string
+	Region string
+	Engine string
+}
+
+// fleetThresholds are the conditions under which fleet report exits non-zero
+type fleetThresholds struct {
+	MaxDisk           float32
+	MaxAlerts         int
+	FailOnMaintenance bool
+	FailOnUpgrade     bool
+}
+
+// FleetMaintenanceResult records the outcome of starting maintenance on a
+// single database
+type FleetMaintenanceResult struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Engine  string `json:"engine"`
+	Updates int    `json:"updates"`
+	Result  string `json:"result"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// maintenanceWindow is a daily window of UTC times. A window whose end is
+// before its start spans midnight
+type maintenanceWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// parseMaintenanceWindow parses a window in the form HH:MM-HH:MM
+func parseMaintenanceWindow(s string) (*maintenanceWindow, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q, expected HH:MM-HH:MM", s)
+	}
+
+	w := &maintenanceWindow{}
+	for _, p := range []struct {
+		value string
+		dest  *time.Duration
+	}{{start, &w.Start}, {end, &w.End}} {
+		t, err := time.Parse(fleetWindowLayout, strings.TrimSpace(p.value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q, expected HH:MM-HH:MM", s)
+		}
+		*p.dest = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	if w.Start == w.End {
+		return nil, fmt.Errorf("invalid window %q, start and end are the same", s)
+	}
+
+	return w, nil
+}
+
+// contains reports whether the UTC time of day of t falls within the window
+func (w *maintenanceWindow) contains(t time.Time) bool {
+	t = t.UTC()
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	if w.Start < w.End {
+		return now >= w.Start && now < w.End
+	}
+
+	return now >= w.Start || now < w.End
+}
+
+// check records the thresholds breached by the entry and reports whether any
+// were breached
+func (t *fleetThresholds) check(e *FleetEntry) bool {
+	if t.MaxDisk > 0 && e.DiskPercent > t.MaxDisk {
+		e.Breaches = append(e.Breaches, fmt.Sprintf("disk %.1f%% > %.1f%%", e.DiskPercent, t.MaxDisk))
+	}
+
+	if t.MaxAlerts >= 0 && e.Alerts > t.MaxAlerts {
+		e.Breaches = append(e.Breaches, fmt.Sprintf("alerts %d > %d", e.Alerts, t.MaxAlerts))
+	}
+
+	if t.FailOnMaintenance && len(e.Maintenance) > 0 {
+		e.Breaches = append(e.Breaches, "maintenance pending")
+	}
+
+	if t.FailOnUpgrade && len(e.Upgrades) > 0 {
+		e.Breaches = append(e.Breaches, "upgrade available")
+	}
+
+	return len(e.Breaches) > 0
+}
+
+// ======================================
+
+// listFleet returns the databases matching the filter
+func (o *options) listFleet(filter *fleetFilter) ([]govultr.Database, error) {
+	dbs, _, _, err := o.Base.Client.Database.List(
+		o.Base.Context,
+		&govultr.DBListOptions{Tag: filter.Tag, Region: filter.Region},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving database list : %v", err)
+	}
+
+	if filter.Engine == "" {
+		return dbs, nil
+	}
+
+	var matched []govultr.Database
+	for i := range dbs {
+		if strings.EqualFold(dbs[i].DatabaseEngine, filter.Engine) {
+			matched = append(matched, dbs[i])
+		}
+	}
+
+	return matched, nil
+}
+
+// fleetEntry gathers the maintenance updates, available versions, alerts and
+// disk usage of a database. Errors are recorded on the entry rather than
+// returned, since not every engine supports every call
+func (o *options) fleetEntry(db *govultr.Database, period string) *FleetEntry {
+	e := &FleetEntry{
+		ID:          db.ID,
+		Label:       db.Label,
+		Engine:      db.DatabaseEngine,
+		Version:     db.DatabaseEngineVersion,
+		Region:      db.Region,
+		Status:      db.Status,
+		Maintenance: []string{},
+		Upgrades:    []string{},
+	}
+
+	client := o.Base.Client.Database
+	ctx := o.Base.Context
+
+	if updates, _, err := client.ListMaintenanceUpdates(ctx, db.ID); err != nil {
+		e.Errors = append(e.Errors, fmt.Sprintf("maintenance : %v", err))
+	} else if updates != nil {
+		e.Maintenance = updates
+	}
+
+	if versions, _, err := client.ListAvailableVersions(ctx, db.ID); err != nil {
+		e.Errors = append(e.Errors, fmt.Sprintf("versions : %v", err))
+	} else if versions != nil {
+		e.Upgrades = versions
+	}
+
+	if alerts, _, err := client.ListServiceAlerts(ctx, db.ID, &govultr.DatabaseListAlertsReq{Period: period}); err != nil {
+		e.Errors = append(e.Errors, fmt.Sprintf("alerts : %v", err))
+	} else {
+		e.Alerts = len(alerts)
+		for i := range alerts {
+			if alerts[i].Timestamp > e.LatestAlert {
+				e.LatestAlert = alerts[i].Timestamp
+			}
+		}
+	}
+
+	if usage, _, err := client.GetUsage(ctx, db.ID); err != nil {
+		e.Errors = append(e.Errors, fmt.Sprintf("usage : %v", err))
+	} else {
+		e.DiskPercent = usage.Disk.Percentage
+	}
+
+	return e
+}
+
+// fleetReport builds the report for every database matching the filter and
+// returns an error when any database breaches the thresholds
+func (o *options) fleetReport(filter *fleetFilter, period string, thresholds *fleetThresholds) ([]FleetEntry, error) {
+	dbs, err := o.listFleet(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []FleetEntry{}
+	breached := 0
+	for i := range dbs {
+		e := o.fleetEntry(&dbs[i], period)
+		if thresholds.check(e) {
+			breached++
+		}
+		entries = append(entries, *e)
+	}
+
+	if breached > 0 {
+		return entries, fmt.Errorf("%d of %d databases breached the report thresholds", breached, len(entries))
+	}
+
+	return entries, nil
+}
+
+// fleetMaintenance starts maintenance on every database matching the filter
+// which has pending updates. No maintenance is started outside of the window
+// and the remaining databases are skipped if the window closes part way. Dry
+// runs ignore the window
+func (o *options) fleetMaintenance(
+	filter *fleetFilter,
+	window *maintenanceWindow,
+	dryRun bool,
+) ([]FleetMaintenanceResult, error) {
+	if window != nil && !dryRun && !window.contains(time.Now()) {
+		return nil, errors.New("the current time is outside of the maintenance window")
+	}
+
+	dbs, err := o.listFleet(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []FleetMaintenanceResult{}
+	failed := 0
+	for i := range dbs {
+		r := FleetMaintenanceResult{ID: dbs[i].ID, Label: dbs[i].Label, Engine: dbs[i].DatabaseEngine}
+
+		updates, _, errUp := o.Base.Client.Database.ListMaintenanceUpdates(o.Base.Context, dbs[i].ID)
+		r.Updates = len(updates)
+
+		switch {
+		case errUp != nil:
+			r.Result, r.Detail = fleetMaintenanceFailed, errUp.Error()
+		case len(updates) == 0:
+			r.Result, r.Detail = fleetMaintenanceSkipped, "no pending updates"
+		case dryRun:
+			r.Result = fleetMaintenancePending
+		case window != nil && !window.contains(time.Now()):
+			r.Result, r.Detail = fleetMaintenanceSkipped, "maintenance window closed"
+		default:
+			msg, _, errSt := o.Base.Client.Database.StartMaintenance(o.Base.Context, dbs[i].ID)
+			if errSt != nil {
+				r.Result, r.Detail = fleetMaintenanceFailed, errSt.Error()
+			} else {
+				r.Result, r.Detail = fleetMaintenanceStarted, msg
+			}
+		}
+
+		if r.Result == fleetMaintenanceFailed {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("maintenance could not be started on %d of %d databases", failed, len(results))
+	}
+
+	return results, nil
+}
+
+// formatDiskPercent renders a disk usage percentage for display
+func formatDiskPercent(p float32) string {
+	return strconv.FormatFloat(float64(p), 'f', 1, 32) + "%"
+}
+
+// addFleetFilterFlags adds the flags which select the databases of the fleet
+func addFleetFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("tag", "", "(optional) only include databases with this tag")
+	cmd.Flags().String("region", "", "(optional) only include databases in this region")
+	cmd.Flags().String("engine", "", "(optional) only include databases of this engine (mysql, pg, valkey, kafka)")
+}
+
+// fleetFilterFlags reads the flags added by addFleetFilterFlags
+func fleetFilterFlags(cmd *cobra.Command) (*fleetFilter, error) {
+	tag, errTa := cmd.Flags().GetString("tag")
+	if errTa != nil {
+		return nil, fmt.Errorf("error parsing flag 'tag' for database fleet : %v", errTa)
+	}
+
+	region, errRe := cmd.Flags().GetString("region")
+	if errRe != nil {
+		return nil, fmt.Errorf("error parsing flag 'region' for database fleet : %v", errRe)
+	}
+
+	engine, errEn := cmd.Flags().GetString("engine")
+	if errEn != nil {
+		return nil, fmt.Errorf("error parsing flag 'engine' for database fleet : %v", errEn)
+	}
+
+	return &fleetFilter{Tag: tag, Region: region, Engine: engine}, nil
+}
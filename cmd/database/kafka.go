@@ -0,0 +1,527 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	changeCreate = "create"
+	changeUpdate = "update"
+	changeDelete = "delete"
+
+	resultPlanned = "planned"
+	resultDone    = "done"
+
+	kafkaResourceTopic = "topic"
+	kafkaResourceQuota = "quota"
+	kafkaResourceUser  = "user"
+
+	// the Kafka broker defaults, used for new topics which omit retention
+	kafkaDefaultRetentionHours = 168
+	kafkaDefaultRetentionBytes = -1
+)
+
+var kafkaPermissions = []string{"admin", "read", "write", "readwrite"}
+
+// kafkaManifest is the declarative format read by kafka sync
+type kafkaManifest struct {
+	Topics []kafkaTopicSpec `yaml:"topics"`
+	Quotas []kafkaQuotaSpec `yaml:"quotas"`
+	Users  []kafkaUserSpec  `yaml:"users"`
+}
+
+// kafkaTopicSpec describes a topic. Settings which are omitted keep their
+// current value on existing topics
+type kafkaTopicSpec struct {
+	Name           string `yaml:"name"`
+	Partitions     *int   `yaml:"partitions"`
+	Replication    *int   `yaml:"replication"`
+	RetentionHours *int   `yaml:"retention_hours"`
+	RetentionBytes *int   `yaml:"retention_bytes"`
+}
+
+// kafkaQuotaSpec describes a quota for a client and user. Settings which are
+// omitted keep their current value on existing quotas
+type kafkaQuotaSpec struct {
+	ClientID          string `yaml:"client_id"`
+	User              string `yaml:"user"`
+	ConsumerByteRate  *int   `yaml:"consumer_byte_rate"`
+	ProducerByteRate  *int   `yaml:"producer_byte_rate"`
+	RequestPercentage *int   `yaml:"request_percentage"`
+}
+
+// kafkaUserSpec describes a user and its permission level
+type kafkaUserSpec struct {
+	Username   string `yaml:"username"`
+	Permission string `yaml:"permission"`
+}
+
+// KafkaChange describes a single change needed to bring the topics, quotas
+// and users of a kafka cluster to the state in a manifest
+type KafkaChange struct {
+	Resource string   `json:"resource"`
+	Action   string   `json:"action"`
+	Name     string   `json:"name"`
+	Changes  []string `json:"changes,omitempty"`
+	Result   string   `json:"result"`
+
+	topic      *govultr.DatabaseTopic
+	quota      *govultr.DatabaseQuota
+	permission string
+}
+
+// loadKafkaManifest reads and validates a kafka manifest file
+func loadKafkaManifest(path string) (*kafkaManifest, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var m kafkaManifest
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("error parsing %s : %v", path, err)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (m *kafkaManifest) validate() error {
+	var problems []string
+
+	topics := make(map[string]bool)
+	for i := range m.Topics {
+		t := &m.Topics[i]
+		switch {
+		case t.Name == "":
+			problems = append(problems, fmt.Sprintf("topic %d : name is required", i+1))
+		case topics[t.Name]:
+			problems = append(problems, fmt.Sprintf("topic %s : defined more than once", t.Name))
+		}
+		topics[t.Name] = true
+
+		for field, v := range map[string]*int{"partitions": t.Partitions, "replication": t.Replication} {
+			if v != nil && *v < 1 {
+				problems = append(problems, fmt.Sprintf("topic %s : %s must be at least 1", t.Name, field))
+			}
+		}
+	}
+
+	quotas := make(map[string]bool)
+	for i := range m.Quotas {
+		q := &m.Quotas[i]
+		key := quotaKey(q.ClientID, q.User)
+		switch {
+		case q.ClientID == "" || q.User == "":
+			problems = append(problems, fmt.Sprintf("quota %d : client_id and user are required", i+1))
+		case quotas[key]:
+			problems = append(problems, fmt.Sprintf("quota %s : defined more than once", key))
+		}
+		quotas[key] = true
+	}
+
+	users := make(map[string]bool)
+	for i := range m.Users {
+		u := &m.Users[i]
+		switch {
+		case u.Username == "":
+			problems = append(problems, fmt.Sprintf("user %d : username is required", i+1))
+		case users[u.Username]:
+			problems = append(problems, fmt.Sprintf("user %s : defined more than once", u.Username))
+		case !validPermission(u.Permission):
+			problems = append(problems, fmt.Sprintf(
+				"user %s : permission must be one of %s",
+				u.Username,
+				strings.Join(kafkaPermissions, ", "),
+			))
+		}
+		users[u.Username] = true
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid kafka manifest\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+func validPermission(permission string) bool {
+	for i := range kafkaPermissions {
+		if permission == kafkaPermissions[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func quotaKey(clientID, user string) string {
+	return clientID + "/" + user
+}
+
+// diffKafka compares the current topics, quotas and users of a cluster with
+// the manifest and returns the changes required. Resources missing from the
+// manifest are only deleted when prune is set, and the protected user is
+// never deleted. Creates and updates are ordered users, topics then quotas so
+// that quotas can refer to new users, and deletes in the reverse order
+func diffKafka(
+	m *kafkaManifest,
+	topics []govultr.DatabaseTopic,
+	quotas []govultr.DatabaseQuota,
+	users []govultr.DatabaseUser,
+	prune bool,
+	protectedUser string,
+) ([]KafkaChange, error) {
+	topicChanges, err := diffTopics(m.Topics, topics, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaChanges := diffQuotas(m.Quotas, quotas, prune)
+	userChanges := diffUsers(m.Users, users, prune, protectedUser)
+
+	var additions, deletions []KafkaChange
+	for _, group := range [][]KafkaChange{userChanges, topicChanges, quotaChanges} {
+		for i := range group {
+			if group[i].Action != changeDelete {
+				additions = append(additions, group[i])
+			}
+		}
+	}
+
+	for _, group := range [][]KafkaChange{quotaChanges, topicChanges, userChanges} {
+		for i := range group {
+			if group[i].Action == changeDelete {
+				deletions = append(deletions, group[i])
+			}
+		}
+	}
+
+	return append(additions, deletions...), nil
+}
+
+func diffTopics(specs []kafkaTopicSpec, topics []govultr.DatabaseTopic, prune bool) ([]KafkaChange, error) {
+	existing := make(map[string]*govultr.DatabaseTopic, len(topics))
+	for i := range topics {
+		existing[topics[i].Name] = &topics[i]
+	}
+
+	var changes []KafkaChange
+	wanted := make(map[string]bool, len(specs))
+	for i := range specs {
+		spec := &specs[i]
+		wanted[spec.Name] = true
+
+		have, ok := existing[spec.Name]
+		if !ok {
+			if spec.Partitions == nil || spec.Replication == nil {
+				return nil, fmt.Errorf("topic %s : partitions and replication are required to create it", spec.Name)
+			}
+
+			topic := &govultr.DatabaseTopic{
+				Name:           spec.Name,
+				RetentionHours: kafkaDefaultRetentionHours,
+				RetentionBytes: kafkaDefaultRetentionBytes,
+			}
+			var details []string
+			details = setInt(details, "partitions", &topic.Partitions, spec.Partitions)
+			details = setInt(details, "replication", &topic.Replication, spec.Replication)
+			details = setInt(details, "retention_hours", &topic.RetentionHours, spec.RetentionHours)
+			details = setInt(details, "retention_bytes", &topic.RetentionBytes, spec.RetentionBytes)
+
+			changes = append(changes, KafkaChange{
+				Resource: kafkaResourceTopic,
+				Action:   changeCreate,
+				Name:     spec.Name,
+				Changes:  details,
+				topic:    topic,
+			})
+			continue
+		}
+
+		if spec.Partitions != nil && *spec.Partitions < have.Partitions {
+			return nil, fmt.Errorf(
+				"topic %s : partitions cannot be reduced from %d to %d",
+				spec.Name,
+				have.Partitions,
+				*spec.Partitions,
+			)
+		}
+
+		topic := *have
+		var details []string
+		details = updateInt(details, "partitions", &topic.Partitions, spec.Partitions)
+		details = updateInt(details, "replication", &topic.Replication, spec.Replication)
+		details = updateInt(details, "retention_hours", &topic.RetentionHours, spec.RetentionHours)
+		details = updateInt(details, "retention_bytes", &topic.RetentionBytes, spec.RetentionBytes)
+
+		if len(details) > 0 {
+			changes = append(changes, KafkaChange{
+				Resource: kafkaResourceTopic,
+				Action:   changeUpdate,
+				Name:     spec.Name,
+				Changes:  details,
+				topic:    &topic,
+			})
+		}
+	}
+
+	if prune {
+		for i := range topics {
+			if !wanted[topics[i].Name] {
+				changes = append(changes, KafkaChange{
+					Resource: kafkaResourceTopic,
+					Action:   changeDelete,
+					Name:     topics[i].Name,
+					topic:    &topics[i],
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func diffQuotas(specs []kafkaQuotaSpec, quotas []govultr.DatabaseQuota, prune bool) []KafkaChange {
+	existing := make(map[string]*govultr.DatabaseQuota, len(quotas))
+	for i := range quotas {
+		existing[quotaKey(quotas[i].ClientID, quotas[i].User)] = &quotas[i]
+	}
+
+	var changes []KafkaChange
+	wanted := make(map[string]bool, len(specs))
+	for i := range specs {
+		spec := &specs[i]
+		key := quotaKey(spec.ClientID, spec.User)
+		wanted[key] = true
+
+		quota := govultr.DatabaseQuota{ClientID: spec.ClientID, User: spec.User}
+		action := changeCreate
+		if have, ok := existing[key]; ok {
+			quota = *have
+			action = changeUpdate
+		}
+
+		var details []string
+		set := setInt
+		if action == changeUpdate {
+			set = updateInt
+		}
+		details = set(details, "consumer_byte_rate", &quota.ConsumerByteRate, spec.ConsumerByteRate)
+		details = set(details, "producer_byte_rate", &quota.ProducerByteRate, spec.ProducerByteRate)
+		details = set(details, "request_percentage", &quota.RequestPercentage, spec.RequestPercentage)
+
+		if action == changeCreate || len(details) > 0 {
+			changes = append(changes, KafkaChange{
+				Resource: kafkaResourceQuota,
+				Action:   action,
+				Name:     key,
+				Changes:  details,
+				quota:    &quota,
+			})
+		}
+	}
+
+	if prune {
+		for i := range quotas {
+			key := quotaKey(quotas[i].ClientID, quotas[i].User)
+			if !wanted[key] {
+				changes = append(changes, KafkaChange{
+					Resource: kafkaResourceQuota,
+					Action:   changeDelete,
+					Name:     key,
+					quota:    &quotas[i],
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+func diffUsers(specs []kafkaUserSpec, users []govultr.DatabaseUser, prune bool, protectedUser string) []KafkaChange {
+	existing := make(map[string]*govultr.DatabaseUser, len(users))
+	for i := range users {
+		existing[users[i].Username] = &users[i]
+	}
+
+	var changes []KafkaChange
+	wanted := make(map[string]bool, len(specs))
+	for i := range specs {
+		spec := &specs[i]
+		wanted[spec.Username] = true
+
+		have, ok := existing[spec.Username]
+		switch {
+		case !ok:
+			changes = append(changes, KafkaChange{
+				Resource:   kafkaResourceUser,
+				Action:     changeCreate,
+				Name:       spec.Username,
+				Changes:    []string{"permission=" + spec.Permission},
+				permission: spec.Permission,
+			})
+		case have.Permission != spec.Permission:
+			changes = append(changes, KafkaChange{
+				Resource:   kafkaResourceUser,
+				Action:     changeUpdate,
+				Name:       spec.Username,
+				Changes:    []string{fmt.Sprintf("permission %s -> %s", have.Permission, spec.Permission)},
+				permission: spec.Permission,
+			})
+		}
+	}
+
+	if prune {
+		for i := range users {
+			if !wanted[users[i].Username] && users[i].Username != protectedUser {
+				changes = append(changes, KafkaChange{
+					Resource: kafkaResourceUser,
+					Action:   changeDelete,
+					Name:     users[i].Username,
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// setInt sets a value of a new resource and records it in the details when
+// the spec provides it
+func setInt(details []string, name string, field, spec *int) []string {
+	if spec == nil {
+		return details
+	}
+
+	*field = *spec
+	return append(details, fmt.Sprintf("%s=%d", name, *spec))
+}
+
+// updateInt updates a value of an existing resource and records the change
+// in the details when the spec provides a different value
+func updateInt(details []string, name string, field, spec *int) []string {
+	if spec == nil || *spec == *field {
+		return details
+	}
+
+	details = append(details, fmt.Sprintf("%s %d -> %d", name, *field, *spec))
+	*field = *spec
+
+	return details
+}
+
+// ======================================
+
+// runKafkaChanges applies the changes, or only marks them as planned for a dry
+// run, and displays the result of each
+func (o *options) runKafkaChanges(changes []KafkaChange, dryRun bool) error {
+	data := &KafkaChangesPrinter{Changes: changes}
+
+	if dryRun {
+		for i := range changes {
+			changes[i].Result = resultPlanned
+		}
+		o.Base.Printer.Display(data, nil)
+		return nil
+	}
+
+	err := o.applyKafkaChanges(changes)
+
+	return o.Base.Printer.DisplayWithError(data, err)
+}
+
+// applyKafkaChanges applies the changes in order, recording the result of
+// each. It continues past failures and returns an error when any change could
+// not be applied
+func (o *options) applyKafkaChanges(changes []KafkaChange) error { //nolint:gocyclo
+	id := o.Base.Args[0]
+
+	failed := 0
+	for i := range changes {
+		var err error
+		c := &changes[i]
+
+		switch c.Resource {
+		case kafkaResourceTopic:
+			switch c.Action {
+			case changeCreate:
+				_, _, err = o.Base.Client.Database.CreateTopic(o.Base.Context, id, &govultr.DatabaseTopicCreateReq{
+					Name:           c.topic.Name,
+					Partitions:     c.topic.Partitions,
+					Replication:    c.topic.Replication,
+					RetentionHours: c.topic.RetentionHours,
+					RetentionBytes: c.topic.RetentionBytes,
+				})
+			case changeUpdate:
+				_, _, err = o.Base.Client.Database.UpdateTopic(o.Base.Context, id, c.topic.Name, &govultr.DatabaseTopicUpdateReq{
+					Partitions:     c.topic.Partitions,
+					Replication:    c.topic.Replication,
+					RetentionHours: c.topic.RetentionHours,
+					RetentionBytes: c.topic.RetentionBytes,
+				})
+			case changeDelete:
+				err = o.Base.Client.Database.DeleteTopic(o.Base.Context, id, c.topic.Name)
+			}
+		case kafkaResourceQuota:
+			q := c.quota
+			switch c.Action {
+			case changeCreate:
+				_, _, err = o.Base.Client.Database.CreateQuota(o.Base.Context, id, &govultr.DatabaseQuotaCreateReq{
+					ClientID:          q.ClientID,
+					User:              q.User,
+					ConsumerByteRate:  q.ConsumerByteRate,
+					ProducerByteRate:  q.ProducerByteRate,
+					RequestPercentage: q.RequestPercentage,
+				})
+			case changeUpdate:
+				req := &govultr.DatabaseQuotaUpdateReq{
+					ConsumerByteRate:  q.ConsumerByteRate,
+					ProducerByteRate:  q.ProducerByteRate,
+					RequestPercentage: q.RequestPercentage,
+				}
+				_, _, err = o.Base.Client.Database.UpdateQuota(o.Base.Context, id, q.ClientID, q.User, req)
+			case changeDelete:
+				err = o.Base.Client.Database.DeleteQuota(o.Base.Context, id, q.ClientID, q.User)
+			}
+		case kafkaResourceUser:
+			switch c.Action {
+			case changeCreate:
+				_, _, err = o.Base.Client.Database.CreateUser(o.Base.Context, id, &govultr.DatabaseUserCreateReq{
+					Username:   c.Name,
+					Permission: c.permission,
+				})
+			case changeUpdate:
+				_, _, err = o.Base.Client.Database.UpdateUserACL(o.Base.Context, id, c.Name, &govultr.DatabaseUserACLReq{
+					Permission: c.permission,
+				})
+			case changeDelete:
+				err = o.Base.Client.Database.DeleteUser(o.Base.Context, id, c.Name)
+			}
+		}
+
+		if err != nil {
+			failed++
+			c.Result = err.Error()
+			continue
+		}
+
+		c.Result = resultDone
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d kafka changes failed", failed, len(changes))
+	}
+
+	return nil
+}
@@ -0,0 +1,372 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/printer"
+	"github.com/vultr/vultr-cli/v3/cmd/utils"
+)
+
+const (
+	migrationDefaultInterval = 10
+	migrationDialTimeout     = 10 * time.Second
+
+	checkPassed  = "ok"
+	checkFailed  = "failed"
+	checkSkipped = "skipped"
+
+	pgSSLRequestCode = 80877103
+	pgSSLRequestLen  = 8
+
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+	mysqlMaxPacketSize          = 1 << 24
+	mysqlCharsetUTF8MB4         = 255
+	mysqlSSLRequestLen          = 32
+	mysqlErrPacket              = 0xff
+	mysqlErrMessageOffset       = 3
+	mysqlSQLStateLen            = 6
+	mysqlHandshakeSkip          = 4 + 8 + 1
+)
+
+// migrationComplete and migrationFailed are the lower case migration statuses
+// at which migration run stops watching
+var (
+	migrationComplete = map[string]bool{"done": true, "syncing": true, "complete": true, "completed": true}
+	migrationFailed   = map[string]bool{"failed": true, "error": true}
+)
+
+// PreflightCheck holds the result of a single check of the migration source
+type PreflightCheck struct {
+	Check  string `json:"check"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// MigrationEvent records a change of migration status observed by migration
+// run
+type MigrationEvent struct {
+	Time    string `json:"time"`
+	Elapsed string `json:"elapsed"`
+	Status  string `json:"status"`
+	Method  string `json:"method,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// preflightMigration checks that the migration source accepts TCP
+// connections, negotiates TLS when ssl is set and accepts the credentials.
+// The credentials are tested with the local client for the engine and the
+// check is skipped when the client is not installed
+func preflightMigration(ctx context.Context, engine string, req *govultr.DatabaseMigrationStartReq) []PreflightCheck {
+	ssl := req.SSL == nil || *req.SSL
+	addr := net.JoinHostPort(req.Host, strconv.Itoa(req.Port))
+
+	d := net.Dialer{Timeout: migrationDialTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return []PreflightCheck{
+			{Check: "tcp", Result: checkFailed, Detail: err.Error()},
+			{Check: "tls", Result: checkSkipped},
+			{Check: "credentials", Result: checkSkipped},
+		}
+	}
+	defer conn.Close() //nolint:errcheck
+
+	checks := []PreflightCheck{{Check: "tcp", Result: checkPassed, Detail: "connected to " + addr}}
+
+	switch {
+	case !ssl:
+		checks = append(checks, PreflightCheck{Check: "tls", Result: checkSkipped, Detail: "ssl is disabled"})
+	default:
+		if errDe := conn.SetDeadline(time.Now().Add(migrationDialTimeout)); errDe != nil {
+			return append(checks, PreflightCheck{Check: "tls", Result: checkFailed, Detail: errDe.Error()})
+		}
+
+		detail, errTLS := negotiateTLS(ctx, conn, engine, req.Host)
+		if errTLS != nil {
+			return append(checks,
+				PreflightCheck{Check: "tls", Result: checkFailed, Detail: errTLS.Error()},
+				PreflightCheck{Check: "credentials", Result: checkSkipped},
+			)
+		}
+		checks = append(checks, PreflightCheck{Check: "tls", Result: checkPassed, Detail: detail})
+	}
+
+	return append(checks, checkCredentials(ctx, engine, req))
+}
+
+// negotiateTLS upgrades the connection to TLS the way the clients of the
+// engine do and describes the negotiated session
+func negotiateTLS(ctx context.Context, conn net.Conn, engine, host string) (string, error) {
+	var err error
+	switch engine {
+	case enginePG:
+		err = pgSSLRequest(conn)
+	case engineMySQL:
+		err = mysqlSSLRequest(conn)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// The source certificate is not verified here; the check only confirms
+	// that the source negotiates TLS
+	tc := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true}) //nolint:gosec
+	if err := tc.HandshakeContext(ctx); err != nil {
+		return "", fmt.Errorf("TLS handshake failed : %v", err)
+	}
+
+	state := tc.ConnectionState()
+	detail := tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		detail += ", certificate " + state.PeerCertificates[0].Subject.String()
+	}
+
+	return detail, nil
+}
+
+// pgSSLRequest asks a PostgreSQL server to start TLS on the connection
+func pgSSLRequest(conn net.Conn) error {
+	req := make([]byte, pgSSLRequestLen)
+	binary.BigEndian.PutUint32(req[0:4], pgSSLRequestLen)
+	binary.BigEndian.PutUint32(req[4:8], pgSSLRequestCode)
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+
+	if resp[0] != 'S' {
+		return errors.New("server does not accept TLS connections")
+	}
+
+	return nil
+}
+
+// mysqlSSLRequest reads the initial handshake of a MySQL server and, when the
+// server supports TLS, asks it to start TLS on the connection
+func mysqlSSLRequest(conn net.Conn) error {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return err
+	}
+
+	if len(payload) > mysqlErrMessageOffset && payload[0] == mysqlErrPacket {
+		message := string(payload[mysqlErrMessageOffset:])
+		if strings.HasPrefix(message, "#") && len(message) > mysqlSQLStateLen {
+			message = message[mysqlSQLStateLen:]
+		}
+		return fmt.Errorf("server refused the connection : %s", message)
+	}
+
+	// The protocol version and the null terminated server version are followed
+	// by the connection id, the first auth data and a filler before the lower
+	// capability flags
+	nul := bytes.IndexByte(payload, 0)
+	offset := nul + 1 + mysqlHandshakeSkip
+	if nul < 0 || len(payload) < offset+2 {
+		return errors.New("unexpected handshake from server")
+	}
+
+	capabilities := binary.LittleEndian.Uint16(payload[offset : offset+2])
+	if capabilities&mysqlClientSSL == 0 {
+		return errors.New("server does not accept TLS connections")
+	}
+
+	req := make([]byte, 4+mysqlSSLRequestLen)
+	req[0] = mysqlSSLRequestLen
+	req[3] = 1
+	binary.LittleEndian.PutUint32(req[4:8], mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	binary.LittleEndian.PutUint32(req[8:12], mysqlMaxPacketSize)
+	req[12] = mysqlCharsetUTF8MB4
+
+	_, err := conn.Write(req)
+	return err
+}
+
+// checkCredentials runs a trivial command on the source with the local
+// client for the engine
+func checkCredentials(ctx context.Context, engine string, req *govultr.DatabaseMigrationStartReq) PreflightCheck {
+	check := PreflightCheck{Check: "credentials"}
+
+	if _, ok := connectClients[engine]; !ok || engine == engineKafka {
+		check.Result = checkSkipped
+		check.Detail = fmt.Sprintf("not supported for %s", engine)
+		return check
+	}
+
+	c := &connection{
+		Engine:   engine,
+		Host:     req.Host,
+		Port:     strconv.Itoa(req.Port),
+		User:     req.Username,
+		Password: req.Password,
+		Database: req.Database,
+		Plain:    req.SSL != nil && !*req.SSL,
+	}
+
+	if engine == engineValkey && c.User == "" {
+		c.User = "default"
+	}
+
+	var query []string
+	switch engine {
+	case enginePG:
+		query = []string{"-c", "select 1"}
+	case engineMySQL:
+		query = []string{"-e", "select 1"}
+	case engineValkey:
+		query = []string{"PING"}
+	}
+
+	output, err := c.query(ctx, query)
+	switch {
+	case errors.Is(err, errClientNotFound):
+		check.Result = checkSkipped
+		check.Detail = err.Error()
+	case err != nil:
+		check.Result = checkFailed
+		check.Detail = err.Error()
+	case engine == engineValkey && !strings.Contains(output, "PONG"):
+		check.Result = checkFailed
+		check.Detail = firstLine(output)
+	default:
+		check.Result = checkPassed
+		check.Detail = "authenticated with " + connectClients[engine]
+	}
+
+	return check
+}
+
+// preflightFailed reports whether any preflight check failed
+func preflightFailed(checks []PreflightCheck) bool {
+	for i := range checks {
+		if checks[i].Result == checkFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// ======================================
+
+// MigrationRun holds the outcome of migration run for JSON and YAML output
+type MigrationRun struct {
+	Preflight  []PreflightCheck           `json:"preflight,omitempty"`
+	Events     []MigrationEvent           `json:"events"`
+	Migration  *govultr.DatabaseMigration `json:"migration"`
+	Detached   bool                       `json:"detached"`
+	Connection string                     `json:"connection,omitempty"`
+}
+
+// runMigration runs the preflight checks, starts the migration and follows
+// its status until it completes or fails
+func (o *options) runMigration(engine string, skipPreflight bool, interval time.Duration) (*MigrationRun, error) {
+	run := &MigrationRun{}
+
+	if !skipPreflight {
+		run.Preflight = preflightMigration(o.Base.Context, engine, o.MigrationReq)
+		if o.Base.Printer.IsText() {
+			o.Base.Printer.Display(&PreflightPrinter{Checks: run.Preflight}, nil)
+			fmt.Println()
+		}
+
+		if preflightFailed(run.Preflight) {
+			return run, errors.New("preflight checks failed. Use --skip-preflight to start the migration regardless")
+		}
+	}
+
+	mig, err := o.startMigration()
+	if err != nil {
+		return run, fmt.Errorf("error starting database migration : %v", err)
+	}
+
+	start := time.Now()
+	header := true
+	last := ""
+	for {
+		key := mig.Status + "|" + mig.Error
+		if key != last {
+			event := MigrationEvent{
+				Time:    time.Now().Format(time.RFC3339),
+				Elapsed: time.Since(start).Truncate(time.Second).String(),
+				Status:  mig.Status,
+				Method:  mig.Method,
+				Error:   mig.Error,
+			}
+			run.Events = append(run.Events, event)
+
+			if o.Base.Printer.IsText() {
+				o.Base.Printer.Display(&MigrationEventsPrinter{Events: []MigrationEvent{event}, Header: header}, nil)
+				header = false
+			}
+			last = key
+		}
+
+		run.Migration = mig
+		status := strings.ToLower(mig.Status)
+		switch {
+		case migrationFailed[status] || mig.Error != "":
+			return run, fmt.Errorf("database migration failed : %s", printer.ValueOr(mig.Error, mig.Status))
+		case migrationComplete[status]:
+			return run, nil
+		}
+
+		time.Sleep(interval)
+
+		mig, err = o.getMigrationStatus()
+		if err != nil {
+			return run, fmt.Errorf("error retrieving database migration status : %v", err)
+		}
+	}
+}
+
+// finishMigration detaches the migration when requested or confirmed and
+// records the connection details of the database
+func (o *options) finishMigration(run *MigrationRun, detach bool) error {
+	if !detach && o.Base.Printer.IsText() {
+		fmt.Println()
+		detach = utils.Confirm("Detach the migration and stop replicating from the source?")
+	}
+
+	if detach {
+		if err := o.detachMigration(); err != nil {
+			return fmt.Errorf("error detaching migration from database : %v", err)
+		}
+		run.Detached = true
+	}
+
+	db, err := o.get()
+	if err != nil {
+		return fmt.Errorf("error retrieving database : %v", err)
+	}
+
+	if conn, errCo := newConnection(db, nil, "", false); errCo == nil {
+		run.Connection = conn.uri(true)
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/printer"
+)
+
+const (
+	poolDefaultMode = "transaction"
+
+	poolActionCreate    = "create"
+	poolActionUpdate    = "update"
+	poolActionUnchanged = "unchanged"
+	poolActionExisting  = "existing"
+)
+
+// poolModes are the pool modes supported by PgBouncer
+var poolModes = []string{"session", "transaction", "statement"}
+
+// PoolPlanEntry describes the proposed configuration of a single connection
+// pool
+type PoolPlanEntry struct {
+	Name        string `json:"name"`
+	Database    string `json:"database"`
+	Username    string `json:"username"`
+	Mode        string `json:"mode"`
+	CurrentSize int    `json:"current_size"`
+	Size        int    `json:"size"`
+	Action      string `json:"action"`
+}
+
+// PoolPlan is a proposed connection pool configuration and the connection
+// budget remaining after it
+type PoolPlan struct {
+	VCPUs     int             `json:"vcpus"`
+	RAM       int             `json:"ram"`
+	Max       int             `json:"max_connections"`
+	Reserved  int             `json:"reserved"`
+	Allocated int             `json:"allocated"`
+	Remaining int             `json:"remaining"`
+	Pools     []PoolPlanEntry `json:"pools"`
+	Applied   bool            `json:"applied"`
+}
+
+// poolTarget is a database and user pair to plan a pool for
+type poolTarget struct {
+	Database string
+	Username string
+}
+
+// poolPlanOptions holds the flags of connection-pool plan
+type poolPlanOptions struct {
+	Targets  []string
+	Mode     string
+	Size     int
+	Reserved int
+}
+
+// parsePoolTargets parses database:user pairs. A pair without a user uses
+// the default user of the database
+func parsePoolTargets(pairs []string, defaultUser string) ([]poolTarget, error) {
+	var targets []poolTarget
+	for _, p := range pairs {
+		database, user, _ := strings.Cut(p, ":")
+		if database == "" {
+			return nil, fmt.Errorf("invalid pool target %q, expected database:user", p)
+		}
+
+		targets = append(targets, poolTarget{Database: database, Username: printer.ValueOr(user, defaultUser)})
+	}
+
+	return targets, nil
+}
+
+// validatePoolMode checks that the mode is supported
+func validatePoolMode(mode string) error {
+	if !slices.Contains(poolModes, mode) {
+		return fmt.Errorf("invalid pool mode %q. Supported modes : %s", mode, strings.Join(poolModes, ", "))
+	}
+
+	return nil
+}
+
+// recommendedPoolSize is the number of server connections a single pool can
+// keep busy on the plan, following the (cores * 2) + 1 rule of thumb for
+// PostgreSQL
+func recommendedPoolSize(vcpus int) int {
+	return vcpus*2 + 1
+}
+
+// poolName names a new pool after its database, adding the user when it is
+// not the default user
+func poolName(t poolTarget, defaultUser string) string {
+	if t.Username == defaultUser {
+		return t.Database
+	}
+
+	return t.Database + "-" + t.Username
+}
+
+// planPools proposes a pool for each target. Pools which already serve a
+// target are updated in place and other existing pools are kept, counting
+// against the budget. Sizes are capped so that the pools share the budget
+// left after the existing pools
+func planPools(
+	db *govultr.Database,
+	conns *govultr.DatabaseConnections,
+	pools []govultr.DatabaseConnectionPool,
+	targets []poolTarget,
+	opts *poolPlanOptions,
+) *PoolPlan {
+	plan := &PoolPlan{
+		VCPUs:    db.PlanVCPUs,
+		RAM:      db.PlanRAM,
+		Max:      conns.Max,
+		Reserved: opts.Reserved,
+		Pools:    []PoolPlanEntry{},
+	}
+
+	existing := make(map[poolTarget]*govultr.DatabaseConnectionPool, len(pools))
+	for i := range pools {
+		existing[poolTarget{Database: pools[i].Database, Username: pools[i].Username}] = &pools[i]
+	}
+
+	targeted := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		if p, ok := existing[t]; ok {
+			targeted[p.Name] = true
+		}
+	}
+
+	kept := 0
+	for i := range pools {
+		if targeted[pools[i].Name] {
+			continue
+		}
+
+		kept += pools[i].Size
+		plan.Pools = append(plan.Pools, PoolPlanEntry{
+			Name:        pools[i].Name,
+			Database:    pools[i].Database,
+			Username:    pools[i].Username,
+			Mode:        pools[i].Mode,
+			CurrentSize: pools[i].Size,
+			Size:        pools[i].Size,
+			Action:      poolActionExisting,
+		})
+	}
+
+	size := opts.Size
+	if size == 0 {
+		size = recommendedPoolSize(db.PlanVCPUs)
+		if len(targets) > 0 {
+			if share := (plan.Max - plan.Reserved - kept) / len(targets); share < size {
+				size = max(share, 1)
+			}
+		}
+	}
+
+	for _, t := range targets {
+		entry := PoolPlanEntry{
+			Name:     poolName(t, db.User),
+			Database: t.Database,
+			Username: t.Username,
+			Mode:     opts.Mode,
+			Size:     size,
+			Action:   poolActionCreate,
+		}
+
+		if p, ok := existing[t]; ok {
+			entry.Name = p.Name
+			entry.CurrentSize = p.Size
+			entry.Action = poolActionUpdate
+			if p.Size == size && p.Mode == opts.Mode {
+				entry.Action = poolActionUnchanged
+			}
+		}
+
+		plan.Pools = append(plan.Pools, entry)
+	}
+
+	for i := range plan.Pools {
+		plan.Allocated += plan.Pools[i].Size
+	}
+	plan.Remaining = plan.Max - plan.Reserved - plan.Allocated
+
+	sort.SliceStable(plan.Pools, func(i, j int) bool { return plan.Pools[i].Name < plan.Pools[j].Name })
+
+	return plan
+}
+
+// ======================================
+
+// planConnectionPools reads the plan, pools, databases and users of a
+// PostgreSQL database and proposes a pool for each target, defaulting to
+// one pool per logical database for the default user
+func (o *options) planConnectionPools(opts *poolPlanOptions) (*PoolPlan, error) {
+	db, err := o.get()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving database : %v", err)
+	}
+
+	if db.DatabaseEngine != enginePG {
+		return nil, errors.New("connection pools are only available for PostgreSQL databases")
+	}
+
+	conns, pools, _, err := o.listConnectionPools()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving connection pool data : %v", err)
+	}
+
+	if conns == nil || conns.Max == 0 {
+		return nil, errors.New("the database did not report its connection limit")
+	}
+
+	dbs, _, err := o.listDBs()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving logical databases : %v", err)
+	}
+
+	users, _, err := o.listUsers()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving database users : %v", err)
+	}
+
+	targets, err := parsePoolTargets(opts.Targets, db.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) == 0 {
+		for i := range dbs {
+			targets = append(targets, poolTarget{Database: dbs[i].Name, Username: db.User})
+		}
+	}
+
+	if err := validatePoolTargets(targets, dbs, users); err != nil {
+		return nil, err
+	}
+
+	return planPools(db, conns, pools, targets, opts), nil
+}
+
+// validatePoolTargets checks that the databases and users of the targets
+// exist
+func validatePoolTargets(targets []poolTarget, dbs []govultr.DatabaseDB, users []govultr.DatabaseUser) error {
+	names := make(map[string]bool, len(dbs))
+	for i := range dbs {
+		names[dbs[i].Name] = true
+	}
+
+	usernames := make(map[string]bool, len(users))
+	for i := range users {
+		usernames[users[i].Username] = true
+	}
+
+	var problems []string
+	for _, t := range targets {
+		if !names[t.Database] {
+			problems = append(problems, fmt.Sprintf("database %s does not exist", t.Database))
+		}
+		if !usernames[t.Username] {
+			problems = append(problems, fmt.Sprintf("user %s does not exist", t.Username))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid pool targets\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// applyPoolPlan creates and updates the pools of the plan. Plans which
+// exceed the connection limit of the database are refused
+func (o *options) applyPoolPlan(plan *PoolPlan) error {
+	if plan.Remaining < 0 {
+		return fmt.Errorf(
+			"the planned pools allocate %d connections, exceeding the %d available after %d reserved",
+			plan.Allocated,
+			plan.Max-plan.Reserved,
+			plan.Reserved,
+		)
+	}
+
+	for i := range plan.Pools {
+		p := &plan.Pools[i]
+
+		var err error
+		switch p.Action {
+		case poolActionCreate:
+			_, _, err = o.Base.Client.Database.CreateConnectionPool(
+				o.Base.Context,
+				o.Base.Args[0],
+				&govultr.DatabaseConnectionPoolCreateReq{
+					Name:     p.Name,
+					Database: p.Database,
+					Username: p.Username,
+					Mode:     p.Mode,
+					Size:     p.Size,
+				},
+			)
+		case poolActionUpdate:
+			_, _, err = o.Base.Client.Database.UpdateConnectionPool(
+				o.Base.Context,
+				o.Base.Args[0],
+				p.Name,
+				&govultr.DatabaseConnectionPoolUpdateReq{Mode: p.Mode, Size: p.Size},
+			)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error applying connection pool %s : %v", p.Name, err)
+		}
+	}
+
+	plan.Applied = true
+
+	return nil
+}
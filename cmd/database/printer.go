@@ -2249,3 +2249,683 @@ func (c *ConnectorStatusPrinter) Data() [][]string {
 func (c *ConnectorStatusPrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// ConnectionDetailsPrinter ...
+type ConnectionDetailsPrinter struct {
+	Engine  string `json:"engine"`
+	Format  string `json:"format"`
+	Details string `json:"details"`
+}
+
+// JSON ...
+func (c *ConnectionDetailsPrinter) JSON() []byte {
+	return printer.MarshalObject(c, "json")
+}
+
+// YAML ...
+func (c *ConnectionDetailsPrinter) YAML() []byte {
+	return printer.MarshalObject(c, "yaml")
+}
+
+// Columns ...
+func (c *ConnectionDetailsPrinter) Columns() [][]string {
+	return nil
+}
+
+// Data ...
+func (c *ConnectionDetailsPrinter) Data() [][]string {
+	var data [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(c.Details, "\n"), "\n") {
+		data = append(data, []string{line})
+	}
+
+	return data
+}
+
+// Paging ...
+func (c *ConnectionDetailsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// AdvancedOptionChangesPrinter ...
+type AdvancedOptionChangesPrinter struct {
+	Changes []AdvancedOptionChange `json:"changes"`
+	Applied bool                   `json:"applied"`
+}
+
+// JSON ...
+func (a *AdvancedOptionChangesPrinter) JSON() []byte {
+	return printer.MarshalObject(a, "json")
+}
+
+// YAML ...
+func (a *AdvancedOptionChangesPrinter) YAML() []byte {
+	return printer.MarshalObject(a, "yaml")
+}
+
+// Columns ...
+func (a *AdvancedOptionChangesPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"NAME",
+		"CURRENT",
+		"NEW",
+		"STATUS",
+	}}
+}
+
+// Data ...
+func (a *AdvancedOptionChangesPrinter) Data() [][]string {
+	if len(a.Changes) == 0 {
+		return [][]string{0: {"---", "---", "---", "no changes"}}
+	}
+
+	status := "planned"
+	if a.Applied {
+		status = "applied"
+	}
+
+	var data [][]string
+	for i := range a.Changes {
+		current := "(unset)"
+		if a.Changes[i].Current != nil {
+			current = formatOptionValue(a.Changes[i].Current)
+		}
+
+		data = append(data, []string{
+			a.Changes[i].Name,
+			current,
+			formatOptionValue(a.Changes[i].New),
+			status,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (a *AdvancedOptionChangesPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// AdvancedOptionsExportPrinter ...
+type AdvancedOptionsExportPrinter struct {
+	Options map[string]any
+}
+
+// JSON ...
+func (a *AdvancedOptionsExportPrinter) JSON() []byte {
+	return printer.MarshalObject(a.Options, "json")
+}
+
+// YAML ...
+func (a *AdvancedOptionsExportPrinter) YAML() []byte {
+	return printer.MarshalObject(a.Options, "yaml")
+}
+
+// Columns ...
+func (a *AdvancedOptionsExportPrinter) Columns() [][]string {
+	return nil
+}
+
+// Data ...
+func (a *AdvancedOptionsExportPrinter) Data() [][]string {
+	if len(a.Options) == 0 {
+		return [][]string{0: {"No configured options"}}
+	}
+
+	var data [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(string(a.YAML()), "\n"), "\n") {
+		data = append(data, []string{line})
+	}
+
+	return data
+}
+
+// Paging ...
+func (a *AdvancedOptionsExportPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// KafkaChangesPrinter ...
+type KafkaChangesPrinter struct {
+	Changes []KafkaChange `json:"changes"`
+}
+
+// JSON ...
+func (k *KafkaChangesPrinter) JSON() []byte {
+	return printer.MarshalObject(k, "json")
+}
+
+// YAML ...
+func (k *KafkaChangesPrinter) YAML() []byte {
+	return printer.MarshalObject(k, "yaml")
+}
+
+// Columns ...
+func (k *KafkaChangesPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"RESOURCE",
+		"ACTION",
+		"NAME",
+		"CHANGES",
+		"RESULT",
+	}}
+}
+
+// Data ...
+func (k *KafkaChangesPrinter) Data() [][]string {
+	if len(k.Changes) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range k.Changes {
+		data = append(data, []string{
+			k.Changes[i].Resource,
+			k.Changes[i].Action,
+			k.Changes[i].Name,
+			strings.Join(k.Changes[i].Changes, ", "),
+			k.Changes[i].Result,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (k *KafkaChangesPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// ConnectorEventsPrinter ...
+type ConnectorEventsPrinter struct {
+	Events []ConnectorEvent `json:"events"`
+	Header bool             `json:"-"`
+}
+
+// JSON ...
+func (c *ConnectorEventsPrinter) JSON() []byte {
+	return printer.MarshalObject(c, "json")
+}
+
+// YAML ...
+func (c *ConnectorEventsPrinter) YAML() []byte {
+	return printer.MarshalObject(c, "yaml")
+}
+
+// Columns ...
+func (c *ConnectorEventsPrinter) Columns() [][]string {
+	if !c.Header {
+		return nil
+	}
+
+	return [][]string{0: {
+		"TIME",
+		"TASK",
+		"STATE",
+		"ACTION",
+		"TRACE",
+	}}
+}
+
+// Data ...
+func (c *ConnectorEventsPrinter) Data() [][]string {
+	var data [][]string
+	for i := range c.Events {
+		data = append(data, []string{
+			c.Events[i].Time,
+			c.Events[i].Task,
+			c.Events[i].State,
+			c.Events[i].Action,
+			c.Events[i].Trace,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (c *ConnectorEventsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// PreflightPrinter ...
+type PreflightPrinter struct {
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// JSON ...
+func (p *PreflightPrinter) JSON() []byte {
+	return printer.MarshalObject(p, "json")
+}
+
+// YAML ...
+func (p *PreflightPrinter) YAML() []byte {
+	return printer.MarshalObject(p, "yaml")
+}
+
+// Columns ...
+func (p *PreflightPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"CHECK",
+		"RESULT",
+		"DETAIL",
+	}}
+}
+
+// Data ...
+func (p *PreflightPrinter) Data() [][]string {
+	var data [][]string
+	for i := range p.Checks {
+		data = append(data, []string{
+			p.Checks[i].Check,
+			p.Checks[i].Result,
+			p.Checks[i].Detail,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (p *PreflightPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// MigrationEventsPrinter ...
+type MigrationEventsPrinter struct {
+	Events []MigrationEvent `json:"events"`
+	Header bool             `json:"-"`
+}
+
+// JSON ...
+func (m *MigrationEventsPrinter) JSON() []byte {
+	return printer.MarshalObject(m, "json")
+}
+
+// YAML ...
+func (m *MigrationEventsPrinter) YAML() []byte {
+	return printer.MarshalObject(m, "yaml")
+}
+
+// Columns ...
+func (m *MigrationEventsPrinter) Columns() [][]string {
+	if !m.Header {
+		return nil
+	}
+
+	return [][]string{0: {
+		"TIME",
+		"ELAPSED",
+		"STATUS",
+		"METHOD",
+		"ERROR",
+	}}
+}
+
+// Data ...
+func (m *MigrationEventsPrinter) Data() [][]string {
+	var data [][]string
+	for i := range m.Events {
+		data = append(data, []string{
+			m.Events[i].Time,
+			m.Events[i].Elapsed,
+			m.Events[i].Status,
+			m.Events[i].Method,
+			m.Events[i].Error,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (m *MigrationEventsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// MigrationRunPrinter ...
+type MigrationRunPrinter struct {
+	Run *MigrationRun `json:"migration_run"`
+}
+
+// JSON ...
+func (m *MigrationRunPrinter) JSON() []byte {
+	return printer.MarshalObject(m, "json")
+}
+
+// YAML ...
+func (m *MigrationRunPrinter) YAML() []byte {
+	return printer.MarshalObject(m, "yaml")
+}
+
+// Columns ...
+func (m *MigrationRunPrinter) Columns() [][]string {
+	return nil
+}
+
+// Data ...
+func (m *MigrationRunPrinter) Data() [][]string {
+	var data [][]string
+	if m.Run.Migration != nil {
+		data = append(data, []string{"STATUS", m.Run.Migration.Status})
+	}
+
+	data = append(data, []string{"DETACHED", strconv.FormatBool(m.Run.Detached)})
+
+	if m.Run.Connection != "" {
+		data = append(data, []string{"CONNECTION", m.Run.Connection})
+	}
+
+	return data
+}
+
+// Paging ...
+func (m *MigrationRunPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// FailoverStepsPrinter ...
+type FailoverStepsPrinter struct {
+	Steps  []FailoverStep `json:"steps"`
+	Header bool           `json:"-"`
+}
+
+// JSON ...
+func (f *FailoverStepsPrinter) JSON() []byte {
+	return printer.MarshalObject(f, "json")
+}
+
+// YAML ...
+func (f *FailoverStepsPrinter) YAML() []byte {
+	return printer.MarshalObject(f, "yaml")
+}
+
+// Columns ...
+func (f *FailoverStepsPrinter) Columns() [][]string {
+	if !f.Header {
+		return nil
+	}
+
+	return [][]string{0: {
+		"TIME",
+		"STEP",
+		"RESULT",
+		"DETAIL",
+	}}
+}
+
+// Data ...
+func (f *FailoverStepsPrinter) Data() [][]string {
+	var data [][]string
+	for i := range f.Steps {
+		data = append(data, []string{
+			f.Steps[i].Time,
+			f.Steps[i].Step,
+			f.Steps[i].Result,
+			f.Steps[i].Detail,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (f *FailoverStepsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// FleetReportPrinter ...
+type FleetReportPrinter struct {
+	Databases []FleetEntry `json:"databases"`
+}
+
+// JSON ...
+func (f *FleetReportPrinter) JSON() []byte {
+	return printer.MarshalObject(f, "json")
+}
+
+// YAML ...
+func (f *FleetReportPrinter) YAML() []byte {
+	return printer.MarshalObject(f, "yaml")
+}
+
+// Columns ...
+func (f *FleetReportPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ID",
+		"LABEL",
+		"ENGINE",
+		"VERSION",
+		"STATUS",
+		"MAINTENANCE",
+		"UPGRADES",
+		"ALERTS",
+		"DISK",
+		"BREACHES",
+		"ERRORS",
+	}}
+}
+
+// Data ...
+func (f *FleetReportPrinter) Data() [][]string {
+	if len(f.Databases) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range f.Databases {
+		data = append(data, []string{
+			f.Databases[i].ID,
+			f.Databases[i].Label,
+			f.Databases[i].Engine,
+			f.Databases[i].Version,
+			f.Databases[i].Status,
+			strconv.Itoa(len(f.Databases[i].Maintenance)),
+			printer.ArrayOfStringsToString(f.Databases[i].Upgrades),
+			strconv.Itoa(f.Databases[i].Alerts),
+			formatDiskPercent(f.Databases[i].DiskPercent),
+			strings.Join(f.Databases[i].Breaches, ", "),
+			strings.Join(f.Databases[i].Errors, ", "),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (f *FleetReportPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// FleetMaintenancePrinter ...
+type FleetMaintenancePrinter struct {
+	Results []FleetMaintenanceResult `json:"results"`
+}
+
+// JSON ...
+func (f *FleetMaintenancePrinter) JSON() []byte {
+	return printer.MarshalObject(f, "json")
+}
+
+// YAML ...
+func (f *FleetMaintenancePrinter) YAML() []byte {
+	return printer.MarshalObject(f, "yaml")
+}
+
+// Columns ...
+func (f *FleetMaintenancePrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ID",
+		"LABEL",
+		"ENGINE",
+		"UPDATES",
+		"RESULT",
+		"DETAIL",
+	}}
+}
+
+// Data ...
+func (f *FleetMaintenancePrinter) Data() [][]string {
+	if len(f.Results) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range f.Results {
+		data = append(data, []string{
+			f.Results[i].ID,
+			f.Results[i].Label,
+			f.Results[i].Engine,
+			strconv.Itoa(f.Results[i].Updates),
+			f.Results[i].Result,
+			f.Results[i].Detail,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (f *FleetMaintenancePrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// PoolPlanPrinter ...
+type PoolPlanPrinter struct {
+	Plan *PoolPlan `json:"plan"`
+}
+
+// JSON ...
+func (p *PoolPlanPrinter) JSON() []byte {
+	return printer.MarshalObject(p, "json")
+}
+
+// YAML ...
+func (p *PoolPlanPrinter) YAML() []byte {
+	return printer.MarshalObject(p, "yaml")
+}
+
+// Columns ...
+func (p *PoolPlanPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"NAME",
+		"DATABASE",
+		"USERNAME",
+		"MODE",
+		"CURRENT SIZE",
+		"SIZE",
+		"ACTION",
+	}}
+}
+
+// Data ...
+func (p *PoolPlanPrinter) Data() [][]string {
+	var data [][]string
+	if len(p.Plan.Pools) == 0 {
+		data = append(data, []string{"---", "---", "---", "---", "---", "---", "---"})
+	}
+
+	for i := range p.Plan.Pools {
+		data = append(data, []string{
+			p.Plan.Pools[i].Name,
+			p.Plan.Pools[i].Database,
+			p.Plan.Pools[i].Username,
+			p.Plan.Pools[i].Mode,
+			strconv.Itoa(p.Plan.Pools[i].CurrentSize),
+			strconv.Itoa(p.Plan.Pools[i].Size),
+			p.Plan.Pools[i].Action,
+		})
+	}
+
+	data = append(data,
+		[]string{" "},
+		[]string{"PLAN", fmt.Sprintf("%d vCPU / %d MB", p.Plan.VCPUs, p.Plan.RAM)},
+		[]string{"MAX CONNECTIONS", strconv.Itoa(p.Plan.Max)},
+		[]string{"RESERVED", strconv.Itoa(p.Plan.Reserved)},
+		[]string{"ALLOCATED", strconv.Itoa(p.Plan.Allocated)},
+		[]string{"REMAINING", strconv.Itoa(p.Plan.Remaining)},
+		[]string{"APPLIED", strconv.FormatBool(p.Plan.Applied)},
+	)
+
+	return data
+}
+
+// Paging ...
+func (p *PoolPlanPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// RotationsPrinter ...
+type RotationsPrinter struct {
+	Rotations []Rotation `json:"rotations"`
+	Sink      string     `json:"sink"`
+}
+
+// JSON ...
+func (r *RotationsPrinter) JSON() []byte {
+	return printer.MarshalObject(r, "json")
+}
+
+// YAML ...
+func (r *RotationsPrinter) YAML() []byte {
+	return printer.MarshalObject(r, "yaml")
+}
+
+// Columns ...
+func (r *RotationsPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"USERNAME",
+		"ROTATED AT",
+		"SINK",
+	}}
+}
+
+// Data ...
+func (r *RotationsPrinter) Data() [][]string {
+	if len(r.Rotations) == 0 {
+		return [][]string{0: {"---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range r.Rotations {
+		data = append(data, []string{
+			r.Rotations[i].Username,
+			r.Rotations[i].RotatedAt,
+			r.Sink,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (r *RotationsPrinter) Paging() [][]string {
+	return nil
+}
@@ -0,0 +1,466 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/printer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sinkJSON      = "json"
+	sinkDotenv    = "dotenv"
+	sinkK8sSecret = "k8s-secret"
+	sinkExec      = "exec"
+
+	rotateDefaultLength = 32
+	rotateMinLength     = 16
+
+	rotatedAtAnnotation = "vultr.com/rotated-at"
+	dotenvFilePerms     = 0600
+	k8sNameMaxLength    = 253
+)
+
+// rotateSinks are the destinations for rotated credentials
+var rotateSinks = []string{sinkJSON, sinkDotenv, sinkK8sSecret, sinkExec}
+
+var (
+	passwordClasses = []string{
+		"abcdefghijklmnopqrstuvwxyz",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		"0123456789",
+	}
+	k8sNameInvalid = regexp.MustCompile(`[^a-z0-9.-]+`)
+	envKeyInvalid  = regexp.MustCompile(`[^A-Z0-9_]+`)
+)
+
+// Rotation records when the password of a user was rotated
+type Rotation struct {
+	Username  string `json:"username"`
+	RotatedAt string `json:"rotated_at"`
+}
+
+// RotatedCredential holds the new credentials of a database user and the
+// time they were rotated
+type RotatedCredential struct {
+	DatabaseID string `json:"database_id"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Host       string `json:"host"`
+	Port       string `json:"port"`
+	URI        string `json:"uri,omitempty"`
+	RotatedAt  string `json:"rotated_at"`
+}
+
+// rotateSink holds the flags which describe where credentials are written
+type rotateSink struct {
+	Kind       string
+	File       string
+	SecretName string
+	Namespace  string
+	Hook       string
+}
+
+// generatePassword returns a random alphanumeric password containing at
+// least one character of each class. Symbols are left out so the password
+// can be used in URIs and shell files without escaping
+func generatePassword(length int) (string, error) {
+	if length < rotateMinLength {
+		return "", fmt.Errorf("password length must be at least %d", rotateMinLength)
+	}
+
+	all := strings.Join(passwordClasses, "")
+	password := make([]byte, length)
+	for i := range password {
+		set := all
+		if i < len(passwordClasses) {
+			set = passwordClasses[i]
+		}
+
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// shuffle so the guaranteed classes are not always at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
+
+// envPrefix returns the prefix of the dotenv keys of a user. The username is
+// only included when more than one user is rotated
+func envPrefix(username string, multiple bool) string {
+	if !multiple {
+		return "DB_"
+	}
+
+	return strings.Trim(envKeyInvalid.ReplaceAllString(strings.ToUpper(username), "_"), "_") + "_DB_"
+}
+
+// dotenvValues returns the dotenv keys and values of the credentials in the
+// order they are written
+func dotenvValues(creds []RotatedCredential) ([]string, map[string]string) {
+	var keys []string
+	values := make(map[string]string)
+	for i := range creds {
+		prefix := envPrefix(creds[i].Username, len(creds) > 1)
+		for _, kv := range [][2]string{
+			{"USERNAME", creds[i].Username},
+			{"PASSWORD", creds[i].Password},
+			{"HOST", creds[i].Host},
+			{"PORT", creds[i].Port},
+			{"URI", creds[i].URI},
+			{"ROTATED_AT", creds[i].RotatedAt},
+		} {
+			if kv[1] == "" {
+				continue
+			}
+			keys = append(keys, prefix+kv[0])
+			values[prefix+kv[0]] = kv[1]
+		}
+	}
+
+	return keys, values
+}
+
+// mergeDotenv replaces the values of existing keys in a dotenv file and
+// appends the keys which are not present, keeping all other lines
+func mergeDotenv(existing []byte, keys []string, values map[string]string) []byte {
+	written := make(map[string]bool, len(keys))
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		export := ""
+		if strings.HasPrefix(trimmed, "export ") {
+			export = "export "
+		}
+
+		key, _, found := strings.Cut(strings.TrimPrefix(trimmed, export), "=")
+		key = strings.TrimSpace(key)
+		if value, ok := values[key]; found && ok {
+			fmt.Fprintf(&out, "%s%s=%s\n", export, key, shellQuote(value))
+			written[key] = true
+			continue
+		}
+		out.WriteString(line + "\n")
+	}
+
+	for _, key := range keys {
+		if !written[key] {
+			fmt.Fprintf(&out, "%s=%s\n", key, shellQuote(values[key]))
+		}
+	}
+
+	return out.Bytes()
+}
+
+// k8sSecretName returns a valid Kubernetes object name
+func k8sSecretName(name string) string {
+	name = strings.Trim(k8sNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if len(name) > k8sNameMaxLength {
+		name = name[:k8sNameMaxLength]
+	}
+
+	return name
+}
+
+// k8sSecretManifests renders a Secret manifest for each credential. With a
+// single credential, the secret name is used as is, otherwise the username is
+// appended to it
+func k8sSecretManifests(creds []RotatedCredential, name, namespace string) ([]byte, error) {
+	var out bytes.Buffer
+	for i := range creds {
+		secretName := name
+		if len(creds) > 1 {
+			secretName = name + "-" + creds[i].Username
+		}
+
+		metadata := map[string]any{
+			"name":        k8sSecretName(secretName),
+			"annotations": map[string]string{rotatedAtAnnotation: creds[i].RotatedAt},
+		}
+		if namespace != "" {
+			metadata["namespace"] = namespace
+		}
+
+		data := map[string]string{
+			"username": creds[i].Username,
+			"password": creds[i].Password,
+			"host":     creds[i].Host,
+			"port":     creds[i].Port,
+		}
+		if creds[i].URI != "" {
+			data["uri"] = creds[i].URI
+		}
+
+		manifest, err := yaml.Marshal(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"type":       "Opaque",
+			"metadata":   metadata,
+			"stringData": data,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out.WriteString("---\n")
+		out.Write(manifest)
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeOrPrint writes the content to path, or to stdout when path is empty
+func writeOrPrint(path string, content []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	return os.WriteFile(filepath.Clean(path), content, dotenvFilePerms)
+}
+
+// runRotateHook runs the hook once per credential through the shell. The
+// credential is passed as JSON on stdin and as DB_ prefixed environment
+// variables
+func runRotateHook(ctx context.Context, hook string, cred *RotatedCredential) error {
+	payload, err := json.Marshal(cred)
+	if err != nil {
+		return err
+	}
+
+	keys, values := dotenvValues([]RotatedCredential{*cred})
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook) //nolint:gosec
+	cmd.Env = os.Environ()
+	for _, key := range keys {
+		cmd.Env = append(cmd.Env, key+"="+values[key])
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook failed for %s : %v", cred.Username, err)
+	}
+
+	return nil
+}
+
+// write sends the credentials to the sink
+func (s *rotateSink) write(ctx context.Context, creds []RotatedCredential) error {
+	switch s.Kind {
+	case sinkDotenv:
+		existing, err := os.ReadFile(filepath.Clean(s.File))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		keys, values := dotenvValues(creds)
+		return writeOrPrint(s.File, mergeDotenv(existing, keys, values))
+	case sinkK8sSecret:
+		manifests, err := k8sSecretManifests(creds, s.SecretName, s.Namespace)
+		if err != nil {
+			return err
+		}
+		return writeOrPrint(s.File, manifests)
+	case sinkExec:
+		for i := range creds {
+			if err := runRotateHook(ctx, s.Hook, &creds[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return writeOrPrint(s.File, printer.MarshalObject(map[string]any{"credentials": creds}, "json"))
+	}
+}
+
+// validate checks the sink flags before any password is changed
+func (s *rotateSink) validate() error {
+	switch s.Kind {
+	case sinkJSON, sinkK8sSecret:
+	case sinkDotenv:
+		if s.File == "" {
+			return errors.New("please provide a --file for the dotenv sink")
+		}
+	case sinkExec:
+		if s.Hook == "" {
+			return errors.New("please provide a --hook for the exec sink")
+		}
+	default:
+		return fmt.Errorf("invalid sink %q. Supported sinks : %s", s.Kind, strings.Join(rotateSinks, ", "))
+	}
+
+	if s.File != "" {
+		if err := checkWritable(s.File); err != nil {
+			return fmt.Errorf("unable to write to %s : %v", s.File, err)
+		}
+	}
+
+	return nil
+}
+
+// checkWritable checks that path can be written without creating or
+// truncating it. An existing file is opened for writing, otherwise a probe
+// file is created and removed in its directory
+func checkWritable(path string) error {
+	path = filepath.Clean(path)
+	if _, err := os.Stat(path); err == nil {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+
+	probe, err := os.CreateTemp(filepath.Dir(path), ".vultr-cli-*")
+	if err != nil {
+		return err
+	}
+	if err := probe.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(probe.Name())
+}
+
+// ======================================
+
+// rotateUsers sets a new generated password for each user and writes the
+// credentials to the sink. If the sink fails after passwords were changed,
+// the credentials are printed as JSON so they are not lost
+func (o *options) rotateUsers(
+	usernames []string,
+	allUsers bool,
+	length int,
+	sink *rotateSink,
+) ([]RotatedCredential, error) {
+	if err := sink.validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := o.get()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving database : %v", err)
+	}
+
+	if sink.SecretName == "" {
+		sink.SecretName = db.Label
+	}
+
+	if allUsers {
+		users, _, errLi := o.listUsers()
+		if errLi != nil {
+			return nil, fmt.Errorf("error retrieving database users : %v", errLi)
+		}
+
+		// the default admin user is only rotated when named explicitly
+		usernames = nil
+		for i := range users {
+			if users[i].Username != db.User {
+				usernames = append(usernames, users[i].Username)
+			}
+		}
+
+		if len(usernames) == 0 {
+			return nil, fmt.Errorf("no users to rotate besides the default user %s", db.User)
+		}
+	}
+
+	var creds []RotatedCredential
+	for _, username := range usernames {
+		password, errGe := generatePassword(length)
+		if errGe != nil {
+			return nil, fmt.Errorf("error generating password : %v", errGe)
+		}
+
+		user, _, errUp := o.Base.Client.Database.UpdateUser(
+			o.Base.Context,
+			o.Base.Args[0],
+			username,
+			&govultr.DatabaseUserUpdateReq{Password: password},
+		)
+		if errUp != nil {
+			err = fmt.Errorf("error updating database user %s : %v", username, errUp)
+			break
+		}
+
+		creds = append(creds, newRotatedCredential(db, user, password))
+	}
+
+	if len(creds) == 0 {
+		return nil, err
+	}
+
+	if errWr := sink.write(o.Base.Context, creds); errWr != nil {
+		// only the json sink writing to stdout has already printed the credentials
+		if sink.Kind != sinkJSON || sink.File != "" {
+			fmt.Printf("%s\n", printer.MarshalObject(map[string]any{"credentials": creds}, "json"))
+		}
+		return creds, fmt.Errorf("credentials were rotated but could not be written to the %s sink : %v", sink.Kind, errWr)
+	}
+
+	return creds, err
+}
+
+// newRotatedCredential records the new credentials of a user. The API may
+// return a different password than the one sent, so the returned password is
+// preferred
+func newRotatedCredential(db *govultr.Database, user *govultr.DatabaseUser, password string) RotatedCredential {
+	if user.Password != "" {
+		password = user.Password
+	}
+
+	cred := RotatedCredential{
+		DatabaseID: db.ID,
+		Username:   user.Username,
+		Password:   password,
+		Host:       db.Host,
+		Port:       db.Port,
+		RotatedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	rotated := &govultr.DatabaseUser{Username: user.Username, Password: password}
+	if c, err := newConnection(db, rotated, "", false); err == nil {
+		cred.Port = c.Port
+		cred.URI = c.uri(true)
+	}
+
+	return cred
+}
@@ -0,0 +1,337 @@
+package dns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	changeCreate = "create"
+	changeUpdate = "update"
+	changeDelete = "delete"
+
+	resultPlanned = "planned"
+	resultDone    = "done"
+)
+
+// RecordChange describes a single change needed to bring the records of a
+// domain to the desired state
+type RecordChange struct {
+	Action   string                `json:"action"`
+	Record   govultr.DomainRecord  `json:"record"`
+	Existing *govultr.DomainRecord `json:"existing,omitempty"`
+	Result   string                `json:"result"`
+}
+
+// diffRecords compares the existing records of a domain with the desired
+// records and returns the changes required. Records are grouped by type and
+// name. When a group holds a single record on both sides, the records are
+// matched regardless of their data so that a change becomes an update.
+// Otherwise records are matched on their data and unmatched records are
+// created or, when prune is set, deleted. Existing records which cannot
+// coexist with a desired CNAME are always deleted
+func diffRecords(existing, desired []govultr.DomainRecord, prune bool) []RecordChange {
+	existingGroups := groupRecords(existing)
+	desiredGroups := groupRecords(desired)
+	desiredTypes := typesByName(desired)
+
+	var changes []RecordChange
+	for _, key := range sortedKeys(desiredGroups, existingGroups) {
+		want := desiredGroups[key]
+		have := existingGroups[key]
+
+		if len(want) == 1 && len(have) == 1 {
+			if !sameData(&want[0], &have[0]) || !sameSettings(&want[0], &have[0]) {
+				changes = append(changes, newUpdate(&want[0], &have[0]))
+			}
+			continue
+		}
+
+		matched := make([]bool, len(have))
+		for i := range want {
+			found := false
+			for j := range have {
+				if matched[j] || !sameData(&want[i], &have[j]) {
+					continue
+				}
+
+				matched[j], found = true, true
+				if !sameSettings(&want[i], &have[j]) {
+					changes = append(changes, newUpdate(&want[i], &have[j]))
+				}
+				break
+			}
+
+			if !found {
+				changes = append(changes, RecordChange{Action: changeCreate, Record: want[i]})
+			}
+		}
+
+		for j := range have {
+			if !matched[j] && (prune || cnameConflict(&have[j], desiredTypes)) {
+				changes = append(changes, RecordChange{Action: changeDelete, Record: have[j]})
+			}
+		}
+	}
+
+	return orderChanges(changes, desiredTypes)
+}
+
+func newUpdate(want, have *govultr.DomainRecord) RecordChange {
+	record := *want
+	record.ID = have.ID
+	if record.TTL == 0 {
+		record.TTL = have.TTL
+	}
+
+	existing := *have
+	return RecordChange{Action: changeUpdate, Record: record, Existing: &existing}
+}
+
+// orderChanges sorts the changes so that a name never goes without an answer
+// for longer than necessary. Creates and updates are applied first so that
+// multi-value sets gain their new values before losing the old ones. Where a
+// CNAME replaces other records at a name, or the reverse, the two cannot
+// coexist so the deletes are applied immediately before the creates for that
+// name. Remaining deletes are applied last
+func orderChanges(changes []RecordChange, desiredTypes map[string]map[string]bool) []RecordChange {
+	swaps := make(map[string]bool)
+	for i := range changes {
+		if changes[i].Action == changeDelete && cnameConflict(&changes[i].Record, desiredTypes) {
+			swaps[strings.ToLower(changes[i].Record.Name)] = true
+		}
+	}
+
+	var additions, swapped, deletions []RecordChange
+	for i := range changes {
+		switch {
+		case swaps[strings.ToLower(changes[i].Record.Name)]:
+			swapped = append(swapped, changes[i])
+		case changes[i].Action == changeDelete:
+			deletions = append(deletions, changes[i])
+		default:
+			additions = append(additions, changes[i])
+		}
+	}
+
+	sort.SliceStable(swapped, func(i, j int) bool {
+		ni, nj := strings.ToLower(swapped[i].Record.Name), strings.ToLower(swapped[j].Record.Name)
+		if ni != nj {
+			return ni < nj
+		}
+		return swapped[i].Action == changeDelete && swapped[j].Action != changeDelete
+	})
+
+	ordered := make([]RecordChange, 0, len(changes))
+	ordered = append(ordered, additions...)
+	ordered = append(ordered, swapped...)
+	ordered = append(ordered, deletions...)
+
+	return ordered
+}
+
+// typesByName returns the set of record types present at each name
+func typesByName(records []govultr.DomainRecord) map[string]map[string]bool {
+	types := make(map[string]map[string]bool)
+	for i := range records {
+		name := strings.ToLower(records[i].Name)
+		if types[name] == nil {
+			types[name] = make(map[string]bool)
+		}
+		types[name][strings.ToUpper(records[i].Type)] = true
+	}
+	return types
+}
+
+// cnameConflict reports whether an existing record cannot coexist with the
+// desired records at its name because one of them is a CNAME
+func cnameConflict(r *govultr.DomainRecord, desiredTypes map[string]map[string]bool) bool {
+	types := desiredTypes[strings.ToLower(r.Name)]
+	if len(types) == 0 {
+		return false
+	}
+
+	if strings.EqualFold(r.Type, "CNAME") {
+		return len(types) > 1 || !types["CNAME"]
+	}
+
+	return types["CNAME"]
+}
+
+// groupRecords groups records by their type and name
+func groupRecords(records []govultr.DomainRecord) map[string][]govultr.DomainRecord {
+	groups := make(map[string][]govultr.DomainRecord)
+	for i := range records {
+		key := recordKey(&records[i])
+		groups[key] = append(groups[key], records[i])
+	}
+	return groups
+}
+
+func recordKey(r *govultr.DomainRecord) string {
+	return fmt.Sprintf("%s %s", strings.ToUpper(r.Type), strings.ToLower(r.Name))
+}
+
+// sortedKeys returns the keys from all groups in a stable order
+func sortedKeys(groups ...map[string][]govultr.DomainRecord) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for i := range groups {
+		for k := range groups[i] {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sameData compares the data of two records of the same type, ignoring the
+// differences the API and zone files allow in hostnames and TXT quoting
+func sameData(a, b *govultr.DomainRecord) bool {
+	return normalizeData(a) == normalizeData(b)
+}
+
+// sameSettings compares the TTL and priority of two records. A desired TTL of
+// 0 means the TTL is not managed
+func sameSettings(want, have *govultr.DomainRecord) bool {
+	if want.TTL != 0 && want.TTL != have.TTL {
+		return false
+	}
+
+	if (want.Type == "MX" || want.Type == "SRV") && want.Priority != have.Priority {
+		return false
+	}
+
+	return true
+}
+
+func normalizeData(r *govultr.DomainRecord) string {
+	data := strings.TrimSpace(r.Data)
+	switch {
+	case r.Type == "TXT":
+		return unquoteTXT(data)
+	case hostnameTypes[r.Type]:
+		return strings.ToLower(strings.TrimSuffix(data, "."))
+	default:
+		return strings.ToLower(data)
+	}
+}
+
+// unquoteTXT joins the character strings of a TXT value into a single
+// unquoted string
+func unquoteTXT(data string) string {
+	if !strings.HasPrefix(data, `"`) {
+		return data
+	}
+
+	var sb strings.Builder
+	inQuote := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\\' && inQuote && i+1 < len(data):
+			i++
+			sb.WriteByte(data[i])
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// hasApexNS reports whether the records include an NS record at the apex
+func hasApexNS(records []govultr.DomainRecord) bool {
+	for i := range records {
+		if records[i].Type == "NS" && records[i].Name == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// keepApexNS removes the deletion of apex NS records from the changes
+func keepApexNS(changes []RecordChange) []RecordChange {
+	var kept []RecordChange
+	for i := range changes {
+		r := &changes[i].Record
+		if changes[i].Action == changeDelete && r.Type == "NS" && r.Name == "" {
+			continue
+		}
+		kept = append(kept, changes[i])
+	}
+	return kept
+}
+
+// ======================================
+
+// runRecordChanges applies the changes, or only marks them as planned for a
+// dry run, and displays the result of each
+func (o *options) runRecordChanges(domain string, changes []RecordChange, notes []string, dryRun bool) error {
+	data := &DNSRecordChangesPrinter{Changes: changes, Notes: notes}
+
+	if dryRun {
+		for i := range changes {
+			changes[i].Result = resultPlanned
+		}
+		o.Base.Printer.Display(data, nil)
+		return nil
+	}
+
+	err := o.applyRecordChanges(domain, changes)
+	return o.Base.Printer.DisplayWithError(data, err)
+}
+
+// applyRecordChanges applies the changes to the domain in order, recording the
+// result of each. It continues past failures and returns an error when any
+// change could not be applied
+func (o *options) applyRecordChanges(domain string, changes []RecordChange) error {
+	failed := 0
+	for i := range changes {
+		var err error
+		r := &changes[i].Record
+
+		switch changes[i].Action {
+		case changeCreate:
+			_, _, err = o.Base.Client.DomainRecord.Create(o.Base.Context, domain, &govultr.DomainRecordCreateReq{
+				Name:     r.Name,
+				Type:     r.Type,
+				Data:     r.Data,
+				TTL:      r.TTL,
+				Priority: govultr.IntToIntPtr(r.Priority),
+			})
+		case changeUpdate:
+			err = o.Base.Client.DomainRecord.Update(o.Base.Context, domain, r.ID, &govultr.DomainRecordUpdateReq{
+				Name:     govultr.StringToStringPtr(r.Name),
+				Data:     r.Data,
+				TTL:      r.TTL,
+				Priority: govultr.IntToIntPtr(r.Priority),
+			})
+		case changeDelete:
+			err = o.Base.Client.DomainRecord.Delete(o.Base.Context, domain, r.ID)
+		}
+
+		if err != nil {
+			failed++
+			changes[i].Result = err.Error()
+			continue
+		}
+
+		changes[i].Result = resultDone
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d record changes failed", failed, len(changes))
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	checkQueryTimeout = 5 * time.Second
+
+	checkPropagated  = "propagated"
+	checkDiffers     = "differs"
+	checkMissing     = "missing"
+	checkUnsupported = "not checked"
+	checkFailed      = "error"
+)
+
+// defaultNameservers are the Vultr nameservers which serve hosted domains
+var defaultNameservers = []string{"ns1.vultr.com", "ns2.vultr.com"}
+
+// RecordCheck holds the result of comparing the API state of a record set
+// with the answer of a single nameserver
+type RecordCheck struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Nameserver string   `json:"nameserver"`
+	Expected   []string `json:"expected"`
+	Answer     []string `json:"answer"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// checkRecords queries each nameserver directly for every record set of the
+// domain and compares the answers with the records from the API
+func checkRecords(
+	ctx context.Context, domain string, records []govultr.DomainRecord, nameservers []string,
+) []RecordCheck {
+	groups := groupRecords(records)
+	keys := sortedKeys(groups)
+
+	var checks []RecordCheck
+	for _, ns := range nameservers {
+		resolver := nameserverResolver(ns)
+
+		for _, key := range keys {
+			group := groups[key]
+			check := RecordCheck{Type: group[0].Type, Name: group[0].Name, Nameserver: ns}
+
+			for i := range group {
+				check.Expected = append(check.Expected, expectedAnswer(&group[i]))
+			}
+			sort.Strings(check.Expected)
+
+			host := fqdn(domain)
+			if group[0].Name != "" {
+				host = fqdn(group[0].Name + "." + domain)
+			}
+
+			qctx, cancel := context.WithTimeout(ctx, checkQueryTimeout)
+			answer, err := lookupRecords(qctx, resolver, group[0].Type, host)
+			cancel()
+
+			switch {
+			case errors.Is(err, errUnsupportedLookup):
+				check.Status = checkUnsupported
+			case isNotFound(err):
+				check.Status = checkMissing
+			case err != nil:
+				check.Status = checkFailed
+				check.Error = err.Error()
+			default:
+				sort.Strings(answer)
+				check.Answer = answer
+				check.Status = checkPropagated
+				if strings.Join(answer, "\n") != strings.Join(check.Expected, "\n") {
+					check.Status = checkDiffers
+				}
+			}
+
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+// nameserverResolver returns a resolver which sends every query to the
+// nameserver instead of the system resolver
+func nameserverResolver(ns string) *net.Resolver {
+	addr := ns
+	if _, _, err := net.SplitHostPort(ns); err != nil {
+		addr = net.JoinHostPort(ns, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: checkQueryTimeout}
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+var errUnsupportedLookup = errors.New("unsupported record type")
+
+// lookupRecords queries the resolver for the records of a type and returns
+// the answers in the same normalized form as expectedAnswer
+func lookupRecords(ctx context.Context, r *net.Resolver, rType, host string) ([]string, error) {
+	var answer []string
+
+	switch strings.ToUpper(rType) {
+	case "A", "AAAA":
+		network := "ip4"
+		if strings.EqualFold(rType, "AAAA") {
+			network = "ip6"
+		}
+
+		ips, err := r.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ips {
+			answer = append(answer, ips[i].String())
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, normalizeHostname(cname))
+	case "MX":
+		mxs, err := r.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for i := range mxs {
+			answer = append(answer, fmt.Sprintf("%d %s", mxs[i].Pref, normalizeHostname(mxs[i].Host)))
+		}
+	case "NS":
+		nss, err := r.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for i := range nss {
+			answer = append(answer, normalizeHostname(nss[i].Host))
+		}
+	case "TXT":
+		txts, err := r.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, txts...)
+	case "SRV":
+		_, srvs, err := r.LookupSRV(ctx, "", "", host)
+		if err != nil {
+			return nil, err
+		}
+		for i := range srvs {
+			answer = append(answer, fmt.Sprintf(
+				"%d %d %d %s",
+				srvs[i].Priority,
+				srvs[i].Weight,
+				srvs[i].Port,
+				normalizeHostname(srvs[i].Target),
+			))
+		}
+	default:
+		return nil, errUnsupportedLookup
+	}
+
+	return answer, nil
+}
+
+// expectedAnswer returns the answer a nameserver should give for a record
+func expectedAnswer(r *govultr.DomainRecord) string {
+	switch strings.ToUpper(r.Type) {
+	case "A", "AAAA":
+		if ip := net.ParseIP(r.Data); ip != nil {
+			return ip.String()
+		}
+		return r.Data
+	case "CNAME", "NS":
+		return normalizeHostname(r.Data)
+	case "MX":
+		return fmt.Sprintf("%d %s", r.Priority, normalizeHostname(r.Data))
+	case "SRV":
+		fields := strings.Fields(r.Data)
+		if len(fields) == 3 {
+			fields[2] = normalizeHostname(fields[2])
+		}
+		return strconv.Itoa(r.Priority) + " " + strings.Join(fields, " ")
+	case "TXT":
+		return unquoteTXT(r.Data)
+	default:
+		return r.Data
+	}
+}
+
+func normalizeHostname(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
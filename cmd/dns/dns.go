@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -22,6 +23,80 @@ var (
 
 	domainLong    = ``
 	domainExample = ``
+
+	exportLong    = `Export the records of a domain as an RFC 1035 (BIND) zone file`
+	exportExample = `
+	# Full example
+	vultr-cli dns domain export example.com
+
+	# Write the zone file to disk
+	vultr-cli dns domain export example.com --output-file example.com.zone
+	`
+
+	importLong = `Import the records of an RFC 1035 (BIND) zone file into a domain.
+
+The zone file is compared with the existing records of the domain and the
+records are created, updated and deleted so that the domain matches the file.
+SOA records in the file are skipped; use soa-update to change the SOA. When
+the file has no NS records at the apex, the existing apex NS records are kept.`
+	importExample = `
+	# Preview the changes without applying them
+	vultr-cli dns domain import example.com --file example.com.zone --dry-run
+
+	# Full example
+	vultr-cli dns domain import example.com --file example.com.zone
+	`
+
+	syncLong = `Synchronize the records of a domain with a YAML record set file.
+
+Records are matched on type and name, and on data where a type and name hold
+more than one value. Missing records are created and changed records updated.
+Records which are not in the file are only deleted when --prune is passed,
+except where they cannot coexist with a CNAME from the file.
+
+Changes are applied so that names keep resolving: new values are added before
+old ones are removed, and CNAME swaps delete and create back to back.
+
+The file format is:
+
+records:
+  - type: A
+    name: "@"
+    data: 192.0.2.10
+    ttl: 300
+  - type: MX
+    name: "@"
+    data: mail.example.com
+    priority: 10
+  - type: CNAME
+    name: www
+    data: example.com`
+	syncExample = `
+	# Preview the changes without applying them
+	vultr-cli dns record sync example.com --file records.yaml --dry-run
+
+	# Full example, deleting records which are not in the file
+	vultr-cli dns record sync example.com --file records.yaml --prune
+	`
+
+	checkLong = `Query the nameservers of a domain directly and compare their answers with
+the records from the API.
+
+Each record set is reported as propagated when the answer matches the API,
+differs when the answer does not match or missing when the name does not
+resolve. CAA and SSHFP records are not checked. The command exits with an
+error when any record set has not propagated.`
+	checkExample = `
+	# Full example
+	vultr-cli dns record check example.com
+
+	# Query specific nameservers
+	vultr-cli dns record check example.com --nameservers ns1.vultr.com,ns2.vultr.com
+	`
+)
+
+const (
+	zoneFilePermission = 0644
 )
 
 // NewCmdDNS provides the CLI command functionality for DNS
@@ -301,6 +376,115 @@ func NewCmdDNS(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	domainSOAUpdate.Flags().StringP("ns-primary", "n", "", "primary nameserver to store in the SOA record")
 	domainSOAUpdate.Flags().StringP("email", "e", "", "administrative email to store in the SOA record")
 
+	// Domain Export
+	domainExport := &cobra.Command{
+		Use:     "export <Domain Name>",
+		Short:   "Export a domain as a zone file",
+		Long:    exportLong,
+		Example: exportExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a domain name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("output-file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing 'output-file' flag for domain export : %v", errPa)
+			}
+
+			soa, errSo := o.domainSOAGet()
+			if errSo != nil {
+				return fmt.Errorf("error getting domain soa info : %v", errSo)
+			}
+
+			recs, err := o.recordListAll(o.Base.Args[0])
+			if err != nil {
+				return fmt.Errorf("error retrieving domain records : %v", err)
+			}
+
+			zone := exportZone(o.Base.Args[0], soa, recs)
+
+			if path != "" {
+				if errWr := os.WriteFile(path, []byte(zone), zoneFilePermission); errWr != nil {
+					return fmt.Errorf("error writing zone file to %s : %v", path, errWr)
+				}
+				return nil
+			}
+
+			o.Base.Printer.Display(&DNSZonePrinter{Domain: o.Base.Args[0], Zone: zone}, nil)
+
+			return nil
+		},
+	}
+
+	domainExport.Flags().StringP("output-file", "", "", "(optional) the file path to write the zone file to")
+
+	// Domain Import
+	domainImport := &cobra.Command{
+		Use:     "import <Domain Name>",
+		Short:   "Import a zone file into a domain",
+		Long:    importLong,
+		Example: importExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a domain name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing 'file' flag for domain import : %v", errPa)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing 'dry-run' flag for domain import : %v", errDr)
+			}
+
+			file, errOp := os.Open(filepath.Clean(path))
+			if errOp != nil {
+				return fmt.Errorf("error opening zone file : %v", errOp)
+			}
+			defer file.Close() //nolint:errcheck
+
+			desired, skipped, errZo := parseZone(o.Base.Args[0], file)
+			if errZo != nil {
+				return fmt.Errorf("error parsing zone file : %v", errZo)
+			}
+
+			if errVa := validateRecords(desired); errVa != nil {
+				return fmt.Errorf("error validating zone file : %v", errVa)
+			}
+
+			existing, err := o.recordListAll(o.Base.Args[0])
+			if err != nil {
+				return fmt.Errorf("error retrieving domain records : %v", err)
+			}
+
+			changes := diffRecords(existing, desired, true)
+			if !hasApexNS(desired) {
+				changes = keepApexNS(changes)
+				skipped = append(skipped, "deletion of apex NS records (the zone file has none)")
+			}
+
+			if err := o.runRecordChanges(o.Base.Args[0], changes, skipped, dryRun); err != nil {
+				return fmt.Errorf("error importing zone file : %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	domainImport.Flags().StringP("file", "f", "", "path to the zone file to import")
+	if err := domainImport.MarkFlagRequired("file"); err != nil {
+		fmt.Printf("error marking domain import 'file' flag required: %v", err)
+		os.Exit(1)
+	}
+	domainImport.Flags().Bool("dry-run", false, "(optional) show the changes without applying them")
+
 	domain.AddCommand(
 		domainList,
 		domainGet,
@@ -310,6 +494,8 @@ func NewCmdDNS(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		domainDNSSECInfo,
 		domainSOAInfo,
 		domainSOAUpdate,
+		domainExport,
+		domainImport,
 	)
 
 	// Record
@@ -415,12 +601,27 @@ func NewCmdDNS(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 				return fmt.Errorf("error parsing 'priority' flag for domain record create : %v", errPr)
 			}
 
+			noValidate, errNv := cmd.Flags().GetBool("no-validate")
+			if errNv != nil {
+				return fmt.Errorf("error parsing 'no-validate' flag for domain record create : %v", errNv)
+			}
+
+			if !noValidate {
+				rec := &govultr.DomainRecord{Type: rType, Name: name, Data: dt, TTL: ttl, Priority: priority}
+				if err := validateRecord(rec, cmd.Flags().Changed("priority")); err != nil {
+					return fmt.Errorf("error validating domain record : %v", err)
+				}
+			}
+
 			o.RecordCreateReq = &govultr.DomainRecordCreateReq{
-				Name:     name,
-				Type:     rType,
-				Data:     dt,
-				TTL:      ttl,
-				Priority: &priority,
+				Name: name,
+				Type: rType,
+				Data: dt,
+				TTL:  ttl,
+			}
+
+			if cmd.Flags().Changed("priority") {
+				o.RecordCreateReq.Priority = govultr.IntToIntPtr(priority)
 			}
 
 			rec, err := o.recordCreate()
@@ -454,7 +655,8 @@ func NewCmdDNS(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	}
 
 	recordCreate.Flags().IntP("ttl", "l", 0, "ttl for the record")
-	recordCreate.Flags().IntP("priority", "p", -1, "only required for MX and SRV")
+	recordCreate.Flags().IntP("priority", "p", 0, "only required for MX and SRV")
+	recordCreate.Flags().Bool("no-validate", false, "(optional) skip the client-side validation of the record")
 
 	// Record Delete
 	recordDelete := &cobra.Command{
@@ -542,12 +744,122 @@ func NewCmdDNS(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 	recordUpdate.Flags().IntP("ttl", "", 0, "time to live for the record")
 	recordUpdate.Flags().IntP("priority", "p", 0, "only required for MX and SRV")
 
+	// Record Sync
+	recordSync := &cobra.Command{
+		Use:     "sync <Domain Name>",
+		Short:   "Synchronize DNS records with a record set file",
+		Long:    syncLong,
+		Example: syncExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a domain name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, errPa := cmd.Flags().GetString("file")
+			if errPa != nil {
+				return fmt.Errorf("error parsing 'file' flag for domain record sync : %v", errPa)
+			}
+
+			prune, errPr := cmd.Flags().GetBool("prune")
+			if errPr != nil {
+				return fmt.Errorf("error parsing 'prune' flag for domain record sync : %v", errPr)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing 'dry-run' flag for domain record sync : %v", errDr)
+			}
+
+			desired, errRs := loadRecordSet(o.Base.Args[0], path)
+			if errRs != nil {
+				return fmt.Errorf("error reading record set file : %v", errRs)
+			}
+
+			if errVa := validateRecords(desired); errVa != nil {
+				return fmt.Errorf("error validating record set file : %v", errVa)
+			}
+
+			existing, err := o.recordListAll(o.Base.Args[0])
+			if err != nil {
+				return fmt.Errorf("error retrieving domain records : %v", err)
+			}
+
+			var skipped []string
+			changes := diffRecords(existing, desired, prune)
+			if prune && !hasApexNS(desired) {
+				changes = keepApexNS(changes)
+				skipped = append(skipped, "deletion of apex NS records (the record set has none)")
+			}
+
+			if err := o.runRecordChanges(o.Base.Args[0], changes, skipped, dryRun); err != nil {
+				return fmt.Errorf("error synchronizing domain records : %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	recordSync.Flags().StringP("file", "f", "", "path to the YAML record set file")
+	if err := recordSync.MarkFlagRequired("file"); err != nil {
+		fmt.Printf("error marking dns record sync 'file' flag required: %v", err)
+		os.Exit(1)
+	}
+	recordSync.Flags().Bool("prune", false, "(optional) delete records which are not in the record set file")
+	recordSync.Flags().Bool("dry-run", false, "(optional) show the changes without applying them")
+
+	// Record Check
+	recordCheck := &cobra.Command{
+		Use:     "check <Domain Name>",
+		Short:   "Check the propagation of DNS records",
+		Long:    checkLong,
+		Example: checkExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a domain name")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nameservers, errNs := cmd.Flags().GetStringSlice("nameservers")
+			if errNs != nil {
+				return fmt.Errorf("error parsing 'nameservers' flag for domain record check : %v", errNs)
+			}
+
+			recs, err := o.recordListAll(o.Base.Args[0])
+			if err != nil {
+				return fmt.Errorf("error retrieving domain records : %v", err)
+			}
+
+			checks := checkRecords(o.Base.Context, o.Base.Args[0], recs, nameservers)
+
+			var errPr error
+			for i := range checks {
+				if checks[i].Status != checkPropagated && checks[i].Status != checkUnsupported {
+					errPr = errors.New("some records have not propagated")
+					break
+				}
+			}
+
+			return o.Base.Printer.DisplayWithError(&DNSRecordChecksPrinter{Checks: checks}, errPr)
+		},
+	}
+
+	recordCheck.Flags().StringSlice(
+		"nameservers",
+		defaultNameservers,
+		"(optional) nameservers to query, as host or host:port",
+	)
+
 	record.AddCommand(
 		recordList,
 		recordGet,
 		recordCreate,
 		recordUpdate,
 		recordDelete,
+		recordSync,
+		recordCheck,
 	)
 
 	cmd.AddCommand(
@@ -618,6 +930,26 @@ func (o *options) recordList() ([]govultr.DomainRecord, *govultr.Meta, error) {
 	return rec, meta, err
 }
 
+// recordListAll retrieves every record of the domain, following the paging
+// cursors
+func (o *options) recordListAll(domain string) ([]govultr.DomainRecord, error) {
+	var all []govultr.DomainRecord
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		recs, meta, _, err := o.Base.Client.DomainRecord.List(o.Base.Context, domain, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, recs...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
 // recordGet ...
 func (o *options) recordGet() (*govultr.DomainRecord, error) {
 	rec, _, err := o.Base.Client.DomainRecord.Get(o.Base.Context, o.Base.Args[0], o.Base.Args[1])
@@ -1,7 +1,9 @@
 package dns
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/vultr/govultr/v3"
 	"github.com/vultr/vultr-cli/v3/cmd/printer"
@@ -277,3 +279,188 @@ func (d *DNSSECPrinter) Data() [][]string {
 func (d *DNSSECPrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// DNSZonePrinter ...
+type DNSZonePrinter struct {
+	Domain string `json:"domain"`
+	Zone   string `json:"zone"`
+}
+
+// JSON ...
+func (d *DNSZonePrinter) JSON() []byte {
+	return printer.MarshalObject(d, "json")
+}
+
+// YAML ...
+func (d *DNSZonePrinter) YAML() []byte {
+	return printer.MarshalObject(d, "yaml")
+}
+
+// Columns ...
+func (d *DNSZonePrinter) Columns() [][]string {
+	return nil
+}
+
+// Data ...
+func (d *DNSZonePrinter) Data() [][]string {
+	var data [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(d.Zone, "\n"), "\n") {
+		data = append(data, []string{line})
+	}
+
+	return data
+}
+
+// Paging ...
+func (d *DNSZonePrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// DNSRecordChangesPrinter ...
+type DNSRecordChangesPrinter struct {
+	Changes []RecordChange `json:"changes"`
+	Notes   []string       `json:"notes,omitempty"`
+}
+
+// JSON ...
+func (d *DNSRecordChangesPrinter) JSON() []byte {
+	return printer.MarshalObject(d, "json")
+}
+
+// YAML ...
+func (d *DNSRecordChangesPrinter) YAML() []byte {
+	return printer.MarshalObject(d, "yaml")
+}
+
+// Columns ...
+func (d *DNSRecordChangesPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ACTION",
+		"ID",
+		"TYPE",
+		"NAME",
+		"DATA",
+		"PRIORITY",
+		"TTL",
+		"RESULT",
+	}}
+}
+
+// Data ...
+func (d *DNSRecordChangesPrinter) Data() [][]string {
+	var data [][]string
+	if len(d.Changes) == 0 {
+		data = append(data, []string{"---", "---", "---", "---", "---", "---", "---", "no changes"})
+	}
+
+	for i := range d.Changes {
+		r := d.Changes[i].Record
+		prev := d.Changes[i].Existing
+		if prev == nil {
+			prev = &r
+		}
+
+		data = append(data, []string{
+			d.Changes[i].Action,
+			changedValue("", r.ID),
+			r.Type,
+			changedValue(prev.Name, r.Name),
+			changedValue(prev.Data, r.Data),
+			changedValue(strconv.Itoa(prev.Priority), strconv.Itoa(r.Priority)),
+			changedValue(strconv.Itoa(prev.TTL), strconv.Itoa(r.TTL)),
+			d.Changes[i].Result,
+		})
+	}
+
+	for i := range d.Notes {
+		data = append(data, []string{"skip", d.Notes[i]})
+	}
+
+	return data
+}
+
+// Paging ...
+func (d *DNSRecordChangesPrinter) Paging() [][]string {
+	return nil
+}
+
+// changedValue displays a value which is being changed as "old -> new"
+func changedValue(prev, current string) string {
+	switch {
+	case current == "":
+		return "---"
+	case prev == "" || prev == current:
+		return current
+	default:
+		return prev + " -> " + current
+	}
+}
+
+// ======================================
+
+// DNSRecordChecksPrinter ...
+type DNSRecordChecksPrinter struct {
+	Checks []RecordCheck `json:"checks"`
+}
+
+// JSON ...
+func (d *DNSRecordChecksPrinter) JSON() []byte {
+	return printer.MarshalObject(d, "json")
+}
+
+// YAML ...
+func (d *DNSRecordChecksPrinter) YAML() []byte {
+	return printer.MarshalObject(d, "yaml")
+}
+
+// Columns ...
+func (d *DNSRecordChecksPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"TYPE",
+		"NAME",
+		"NAMESERVER",
+		"EXPECTED",
+		"ANSWER",
+		"STATUS",
+	}}
+}
+
+// Data ...
+func (d *DNSRecordChecksPrinter) Data() [][]string {
+	if len(d.Checks) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range d.Checks {
+		name := d.Checks[i].Name
+		if name == "" {
+			name = "@"
+		}
+
+		status := d.Checks[i].Status
+		if d.Checks[i].Error != "" {
+			status = fmt.Sprintf("%s : %s", status, d.Checks[i].Error)
+		}
+
+		data = append(data, []string{
+			d.Checks[i].Type,
+			name,
+			d.Checks[i].Nameserver,
+			printer.ArrayOfStringsToString(d.Checks[i].Expected),
+			printer.ArrayOfStringsToString(d.Checks[i].Answer),
+			status,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (d *DNSRecordChecksPrinter) Paging() [][]string {
+	return nil
+}
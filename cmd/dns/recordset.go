@@ -0,0 +1,67 @@
+package dns
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// recordSet is the declarative format read by record sync
+type recordSet struct {
+	Records []recordSetEntry `yaml:"records"`
+}
+
+// recordSetEntry is a single record in a record set file. An empty name or
+// "@" refers to the apex of the domain
+type recordSetEntry struct {
+	Type     string `yaml:"type"`
+	Name     string `yaml:"name"`
+	Data     string `yaml:"data"`
+	TTL      int    `yaml:"ttl"`
+	Priority int    `yaml:"priority"`
+}
+
+// loadRecordSet reads a YAML record set file and returns the records in the
+// form used by the API
+func loadRecordSet(domain, path string) ([]govultr.DomainRecord, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var set recordSet
+	if err := yaml.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+
+	suffix := "." + strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	records := make([]govultr.DomainRecord, len(set.Records))
+	for i, e := range set.Records {
+		if e.Type == "" || e.Data == "" {
+			return nil, fmt.Errorf("record %d : type and data are required", i+1)
+		}
+
+		name := strings.TrimSuffix(strings.ToLower(e.Name), ".")
+		switch {
+		case name == "@" || name == suffix[1:]:
+			name = ""
+		case strings.HasSuffix(name, suffix):
+			name = strings.TrimSuffix(name, suffix)
+		}
+
+		records[i] = govultr.DomainRecord{
+			Type:     strings.ToUpper(e.Type),
+			Name:     name,
+			Data:     e.Data,
+			TTL:      e.TTL,
+			Priority: e.Priority,
+		}
+	}
+
+	return records, nil
+}
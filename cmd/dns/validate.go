@@ -0,0 +1,245 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	maxPriority     = 65535
+	maxCAAFlags     = 255
+	maxTXTStringLen = 255
+	maxHostnameLen  = 253
+)
+
+var (
+	hostnameLabel = regexp.MustCompile(`^(\*|[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?)$`)
+	caaTag        = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	hexString     = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+// validateRecord checks the data of a record against the rules of its type
+// before it is sent to the API. prioritySet reports whether a priority was
+// provided, which is required for MX and SRV records
+func validateRecord(r *govultr.DomainRecord, prioritySet bool) error {
+	if r.TTL < 0 {
+		return fmt.Errorf("ttl must not be negative")
+	}
+
+	if r.Name != "" {
+		if err := validateHostname(r.Name); err != nil {
+			return fmt.Errorf("invalid name %q : %v", r.Name, err)
+		}
+	}
+
+	switch strings.ToUpper(r.Type) {
+	case "A":
+		ip := net.ParseIP(r.Data)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("A record data %q is not an IPv4 address", r.Data)
+		}
+	case "AAAA":
+		ip := net.ParseIP(r.Data)
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("AAAA record data %q is not an IPv6 address", r.Data)
+		}
+	case "CNAME":
+		if r.Name == "" {
+			return fmt.Errorf("CNAME records are not allowed at the apex of a domain")
+		}
+		return validateHostnameData(r)
+	case "NS", "PTR":
+		return validateHostnameData(r)
+	case "MX":
+		if err := validatePriority(r, prioritySet); err != nil {
+			return err
+		}
+		return validateHostnameData(r)
+	case "SRV":
+		if err := validatePriority(r, prioritySet); err != nil {
+			return err
+		}
+		return validateSRV(r)
+	case "CAA":
+		return validateCAA(r.Data)
+	case "TXT":
+		return validateTXT(r.Data)
+	case "SSHFP":
+		return validateSSHFP(r.Data)
+	default:
+		return fmt.Errorf("unsupported record type %q", r.Type)
+	}
+
+	return nil
+}
+
+func validatePriority(r *govultr.DomainRecord, prioritySet bool) error {
+	if !prioritySet {
+		return fmt.Errorf("%s records require a priority", r.Type)
+	}
+
+	if r.Priority < 0 || r.Priority > maxPriority {
+		return fmt.Errorf("priority must be between 0 and %d", maxPriority)
+	}
+
+	return nil
+}
+
+func validateHostnameData(r *govultr.DomainRecord) error {
+	if err := validateHostname(r.Data); err != nil {
+		return fmt.Errorf("%s record data %q is not a valid hostname : %v", r.Type, r.Data, err)
+	}
+	return nil
+}
+
+// validateHostname checks the labels of a hostname. A trailing dot is allowed
+func validateHostname(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return fmt.Errorf("hostname is empty")
+	}
+
+	if len(name) > maxHostnameLen {
+		return fmt.Errorf("hostname is longer than %d characters", maxHostnameLen)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return fmt.Errorf("invalid label %q", label)
+		}
+	}
+
+	return nil
+}
+
+// validateSRV checks SRV data in the form "weight port target" and the
+// "_service._protocol" form of the name
+func validateSRV(r *govultr.DomainRecord) error {
+	labels := strings.Split(r.Name, ".")
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return fmt.Errorf("SRV record name %q must start with _service._protocol", r.Name)
+	}
+
+	fields := strings.Fields(r.Data)
+	if len(fields) != 3 {
+		return fmt.Errorf("SRV record data %q must be in the form \"weight port target\"", r.Data)
+	}
+
+	for i, field := range []string{"weight", "port"} {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil || v < 0 || v > maxPriority {
+			return fmt.Errorf("SRV record %s %q must be between 0 and %d", field, fields[i], maxPriority)
+		}
+	}
+
+	if fields[2] == "." {
+		return nil
+	}
+
+	if err := validateHostname(fields[2]); err != nil {
+		return fmt.Errorf("SRV record target %q is not a valid hostname : %v", fields[2], err)
+	}
+
+	return nil
+}
+
+// validateCAA checks CAA data in the form `flags tag "value"`
+func validateCAA(data string) error {
+	flags, rest, _ := strings.Cut(strings.TrimSpace(data), " ")
+	tag, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	value = strings.TrimSpace(value)
+
+	f, err := strconv.Atoi(flags)
+	if err != nil || f < 0 || f > maxCAAFlags {
+		return fmt.Errorf("CAA record flags %q must be between 0 and %d", flags, maxCAAFlags)
+	}
+
+	if !caaTag.MatchString(tag) {
+		return fmt.Errorf("CAA record tag %q must be alphanumeric, eg. issue, issuewild or iodef", tag)
+	}
+
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return fmt.Errorf("CAA record value %s must be quoted", value)
+	}
+
+	return nil
+}
+
+// validateTXT checks that quoted TXT data is well formed and that none of its
+// character strings exceed 255 characters
+func validateTXT(data string) error {
+	if data == "" {
+		return fmt.Errorf("TXT record data is empty")
+	}
+
+	if !strings.HasPrefix(data, `"`) {
+		if len(data) > maxTXTStringLen {
+			return fmt.Errorf("unquoted TXT record data is longer than %d characters; split it into quoted strings",
+				maxTXTStringLen)
+		}
+		return nil
+	}
+
+	tokens, depth, err := zoneTokens(data, 0)
+	if err != nil || depth != 0 {
+		return fmt.Errorf("TXT record data %s is not correctly quoted", data)
+	}
+
+	for _, tok := range tokens {
+		if !strings.HasPrefix(tok, `"`) {
+			return fmt.Errorf("TXT record data %s has text outside of quotes", data)
+		}
+
+		if len(unquoteTXT(tok)) > maxTXTStringLen {
+			return fmt.Errorf("TXT record strings must not be longer than %d characters", maxTXTStringLen)
+		}
+	}
+
+	return nil
+}
+
+// validateSSHFP checks SSHFP data in the form "algorithm type fingerprint"
+func validateSSHFP(data string) error {
+	fields := strings.Fields(data)
+	if len(fields) != 3 {
+		return fmt.Errorf("SSHFP record data %q must be in the form \"algorithm type fingerprint\"", data)
+	}
+
+	for _, field := range fields[:2] {
+		if _, err := strconv.Atoi(field); err != nil {
+			return fmt.Errorf("SSHFP record algorithm and type must be numeric")
+		}
+	}
+
+	if !hexString.MatchString(fields[2]) {
+		return fmt.Errorf("SSHFP record fingerprint must be hexadecimal")
+	}
+
+	return nil
+}
+
+// validateRecords validates every record and returns an error listing all of
+// the invalid records
+func validateRecords(records []govultr.DomainRecord) error {
+	var problems []string
+	for i := range records {
+		if err := validateRecord(&records[i], true); err != nil {
+			name := records[i].Name
+			if name == "" {
+				name = "@"
+			}
+			problems = append(problems, fmt.Sprintf("%s %s : %v", records[i].Type, name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid records\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
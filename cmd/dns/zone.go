@@ -0,0 +1,483 @@
+package dns
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+)
+
+const (
+	zoneDefaultTTL     = 3600
+	zoneSOARefresh     = 10800
+	zoneSOARetry       = 3600
+	zoneSOAExpire      = 604800
+	zoneSOAMinimum     = 3600
+	zoneTXTChunkLength = 255
+)
+
+// hostnameTypes are the record types whose data (or the target part of it)
+// is a hostname which is stored without the trailing dot by the API
+var hostnameTypes = map[string]bool{
+	"CNAME": true,
+	"NS":    true,
+	"MX":    true,
+	"PTR":   true,
+	"SRV":   true,
+}
+
+// exportZone renders the domain records as an RFC 1035 zone file
+func exportZone(domain string, soa *govultr.Soa, records []govultr.DomainRecord) string {
+	origin := fqdn(domain)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "; Zone file for %s exported by vultr-cli on %s\n", domain, time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "$ORIGIN %s\n", origin)
+	fmt.Fprintf(&sb, "$TTL %d\n", zoneDefaultTTL)
+
+	if soa != nil && soa.NSPrimary != "" {
+		// SOA timers are not exposed by the API so common defaults are used
+		fmt.Fprintf(
+			&sb,
+			"@\t%d\tIN\tSOA\t%s %s ( %s %d %d %d %d )\n",
+			zoneDefaultTTL,
+			fqdn(soa.NSPrimary),
+			soaMailbox(soa.Email),
+			time.Now().UTC().Format("2006010215"),
+			zoneSOARefresh,
+			zoneSOARetry,
+			zoneSOAExpire,
+			zoneSOAMinimum,
+		)
+	}
+
+	sorted := make([]govultr.DomainRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	for i := range sorted {
+		name := sorted[i].Name
+		if name == "" {
+			name = "@"
+		}
+
+		ttl := sorted[i].TTL
+		if ttl == 0 {
+			ttl = zoneDefaultTTL
+		}
+
+		fmt.Fprintf(&sb, "%s\t%d\tIN\t%s\t%s\n", name, ttl, sorted[i].Type, zoneRData(&sorted[i]))
+	}
+
+	return sb.String()
+}
+
+// zoneRData formats the data of a record as zone file rdata
+func zoneRData(r *govultr.DomainRecord) string {
+	switch r.Type {
+	case "CNAME", "NS", "PTR":
+		return fqdn(r.Data)
+	case "MX":
+		return fmt.Sprintf("%d %s", r.Priority, fqdn(r.Data))
+	case "SRV":
+		fields := strings.Fields(r.Data)
+		if len(fields) == 3 {
+			fields[2] = fqdn(fields[2])
+		}
+		return fmt.Sprintf("%d %s", r.Priority, strings.Join(fields, " "))
+	case "TXT":
+		if strings.HasPrefix(r.Data, `"`) {
+			return r.Data
+		}
+		return quoteTXT(r.Data)
+	default:
+		return r.Data
+	}
+}
+
+// quoteTXT quotes a TXT value, splitting it into strings of at most 255
+// characters
+func quoteTXT(v string) string {
+	var parts []string
+	for len(v) > zoneTXTChunkLength {
+		parts = append(parts, strconv.Quote(v[:zoneTXTChunkLength]))
+		v = v[zoneTXTChunkLength:]
+	}
+	parts = append(parts, strconv.Quote(v))
+
+	return strings.Join(parts, " ")
+}
+
+// soaMailbox converts an email address to the SOA RNAME format
+func soaMailbox(email string) string {
+	local, host, found := strings.Cut(email, "@")
+	if !found {
+		return fqdn(email)
+	}
+
+	return fqdn(strings.ReplaceAll(local, ".", `\.`) + "." + host)
+}
+
+// fqdn appends the trailing dot to a hostname when it is missing
+func fqdn(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ======================================
+
+// zoneParser holds the state needed while reading a zone file
+type zoneParser struct {
+	domain    string
+	origin    string
+	ttl       int
+	lastOwner string
+	lastTTL   int
+	skipped   []string
+}
+
+// parseZone reads an RFC 1035 zone file and returns the records it contains in
+// the form used by the API. SOA records are skipped and reported separately
+func parseZone(domain string, r io.Reader) ([]govultr.DomainRecord, []string, error) {
+	p := &zoneParser{
+		domain: strings.ToLower(fqdn(domain)),
+		origin: strings.ToLower(fqdn(domain)),
+	}
+
+	entries, err := zoneEntries(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []govultr.DomainRecord
+	for i := range entries {
+		record, err := p.parseEntry(&entries[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d : %v", entries[i].line, err)
+		}
+
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	return records, p.skipped, nil
+}
+
+func (p *zoneParser) parseEntry(e *zoneEntry) (*govultr.DomainRecord, error) {
+	tokens := e.tokens
+
+	if strings.HasPrefix(tokens[0], "$") {
+		return nil, p.parseDirective(tokens)
+	}
+
+	owner := p.lastOwner
+	if !e.blankOwner {
+		owner = p.absolute(tokens[0])
+		tokens = tokens[1:]
+	}
+
+	if owner == "" {
+		return nil, fmt.Errorf("record has no owner name")
+	}
+	p.lastOwner = owner
+
+	ttl := p.ttl
+	if p.lastTTL != 0 && p.ttl == 0 {
+		ttl = p.lastTTL
+	}
+
+	var rType string
+	for len(tokens) > 0 && rType == "" {
+		tok := tokens[0]
+		tokens = tokens[1:]
+
+		switch {
+		case strings.EqualFold(tok, "IN"), strings.EqualFold(tok, "CH"), strings.EqualFold(tok, "HS"):
+			continue
+		case tok[0] >= '0' && tok[0] <= '9':
+			v, err := parseTTL(tok)
+			if err != nil {
+				return nil, err
+			}
+			ttl = v
+			p.lastTTL = v
+		default:
+			rType = strings.ToUpper(tok)
+		}
+	}
+
+	if rType == "" {
+		return nil, fmt.Errorf("record has no type")
+	}
+
+	if rType == "SOA" {
+		p.skipped = append(p.skipped, fmt.Sprintf("SOA record for %s (managed with soa-update)", owner))
+		return nil, nil
+	}
+
+	name, err := p.relative(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &govultr.DomainRecord{Name: name, Type: rType, TTL: ttl}
+	if err := p.parseRData(record, tokens); err != nil {
+		return nil, fmt.Errorf("%s record %q : %v", rType, owner, err)
+	}
+
+	return record, nil
+}
+
+func (p *zoneParser) parseDirective(tokens []string) error {
+	if len(tokens) < 2 {
+		return fmt.Errorf("directive %s requires a value", tokens[0])
+	}
+
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		p.origin = p.absolute(tokens[1])
+	case "$TTL":
+		v, err := parseTTL(tokens[1])
+		if err != nil {
+			return err
+		}
+		p.ttl = v
+	default:
+		return fmt.Errorf("unsupported directive %s", tokens[0])
+	}
+
+	return nil
+}
+
+func (p *zoneParser) parseRData(record *govultr.DomainRecord, rdata []string) error {
+	expected := map[string]int{
+		"A": 1, "AAAA": 1, "CNAME": 1, "NS": 1, "PTR": 1, "MX": 2, "SRV": 4, "CAA": 3, "SSHFP": 3,
+	}
+
+	if n, ok := expected[record.Type]; ok && len(rdata) != n {
+		return fmt.Errorf("expected %d data fields, found %d", n, len(rdata))
+	}
+
+	switch record.Type {
+	case "A", "AAAA", "SSHFP", "CAA":
+		record.Data = strings.Join(rdata, " ")
+	case "CNAME", "NS", "PTR":
+		record.Data = p.hostname(rdata[0])
+	case "MX":
+		prio, err := strconv.Atoi(rdata[0])
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", rdata[0])
+		}
+		record.Priority = prio
+		record.Data = p.hostname(rdata[1])
+	case "SRV":
+		prio, err := strconv.Atoi(rdata[0])
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", rdata[0])
+		}
+		record.Priority = prio
+		record.Data = strings.Join([]string{rdata[1], rdata[2], p.hostname(rdata[3])}, " ")
+	case "TXT":
+		if len(rdata) == 0 {
+			return fmt.Errorf("missing data")
+		}
+		record.Data = strings.Join(rdata, " ")
+	default:
+		return fmt.Errorf("unsupported record type")
+	}
+
+	return nil
+}
+
+// absolute resolves a zone file name against the current origin
+func (p *zoneParser) absolute(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case name == "@":
+		return p.origin
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + p.origin
+	}
+}
+
+// relative converts an absolute owner name into the record name used by the
+// API, which is relative to the domain
+func (p *zoneParser) relative(name string) (string, error) {
+	if name == p.domain {
+		return "", nil
+	}
+
+	if rel, ok := strings.CutSuffix(name, "."+p.domain); ok {
+		return rel, nil
+	}
+
+	return "", fmt.Errorf("name %q is outside of zone %q", name, p.domain)
+}
+
+// hostname resolves a hostname in record data and drops the trailing dot
+func (p *zoneParser) hostname(name string) string {
+	return strings.TrimSuffix(p.absolute(name), ".")
+}
+
+// parseTTL parses a TTL in seconds or in the BIND unit notation, eg. 1h30m
+func parseTTL(v string) (int, error) {
+	if n, err := strconv.Atoi(v); err == nil {
+		return n, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	total, current := 0, 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c >= '0' && c <= '9' {
+			current = current*10 + int(c-'0')
+			continue
+		}
+
+		mult, ok := units[c|0x20]
+		if !ok {
+			return 0, fmt.Errorf("invalid TTL %q", v)
+		}
+		total += current * mult
+		current = 0
+	}
+
+	return total + current, nil
+}
+
+// ======================================
+
+// zoneEntry is a single logical line of a zone file split into tokens
+type zoneEntry struct {
+	line       int
+	blankOwner bool
+	tokens     []string
+}
+
+// zoneEntries splits a zone file into logical entries, removing comments and
+// joining lines which are continued within parentheses. Quoted strings are
+// kept as a single token including their quotes
+func zoneEntries(r io.Reader) ([]zoneEntry, error) {
+	var entries []zoneEntry
+	var current *zoneEntry
+	depth := 0
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if current == nil {
+			current = &zoneEntry{
+				line:       lineNum,
+				blankOwner: len(line) > 0 && (line[0] == ' ' || line[0] == '\t'),
+			}
+		}
+
+		tokens, d, err := zoneTokens(line, depth)
+		if err != nil {
+			return nil, fmt.Errorf("line %d : %v", lineNum, err)
+		}
+		depth = d
+		current.tokens = append(current.tokens, tokens...)
+
+		if depth > 0 {
+			continue
+		}
+
+		if len(current.tokens) > 0 {
+			entries = append(entries, *current)
+		}
+		current = nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if depth > 0 {
+		return nil, fmt.Errorf("unbalanced parentheses starting on line %d", current.line)
+	}
+
+	return entries, nil
+}
+
+// zoneTokens splits a single line into tokens, tracking the parenthesis depth
+func zoneTokens(line string, depth int) ([]string, int, error) {
+	var tokens []string
+	var sb strings.Builder
+	inQuote := false
+
+	flush := func() {
+		if sb.Len() > 0 {
+			tokens = append(tokens, sb.String())
+			sb.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if inQuote {
+			sb.WriteByte(c)
+			switch c {
+			case '\\':
+				if i+1 < len(line) {
+					i++
+					sb.WriteByte(line[i])
+				}
+			case '"':
+				inQuote = false
+				flush()
+			}
+			continue
+		}
+
+		switch c {
+		case ';':
+			flush()
+			return tokens, depth, nil
+		case '"':
+			flush()
+			inQuote = true
+			sb.WriteByte(c)
+		case '(':
+			flush()
+			depth++
+		case ')':
+			flush()
+			depth--
+			if depth < 0 {
+				return nil, 0, fmt.Errorf("unexpected ')'")
+			}
+		case ' ', '\t':
+			flush()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	if inQuote {
+		return nil, 0, fmt.Errorf("unterminated quoted string")
+	}
+	flush()
+
+	return tokens, depth, nil
+}
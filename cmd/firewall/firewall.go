@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -65,6 +66,40 @@ var (
 	# Shortened example with aliases
 	vultr-cli fw r l 704ac064-4ff2-49ca-a6e6-88262cca8f8a
 	`
+	ruleApplyLong = `
+	Apply a set of rules to the provided firewall group from a YAML file or a
+	preset. Rules without a subnet are expanded to both IPv4 and IPv6 rules
+	which allow any address, unless an ip_type is given. Rules which already
+	exist in the group are skipped.
+
+	The built-in presets are web, ssh-only, k8s-node and database. Presets can
+	be added or overridden with files named <preset>.yaml in the
+	vultr-cli/firewall-presets directory of the user config directory.
+
+	The file format is:
+
+	rules:
+	  - protocol: tcp
+	    port: "443"
+	    notes: https
+	  - protocol: tcp
+	    port: "5432"
+	    subnet: 10.0.0.0/8
+	  - protocol: tcp
+	    port: "443"
+	    source: cloudflare
+	    ip_type: v4
+	`
+	ruleApplyExample = `
+	# Apply rules from a file
+	vultr-cli firewall rule apply 704ac064-4ff2-49ca-a6e6-88262cca8f8a --file rules.yaml
+
+	# Apply a preset to an existing group
+	vultr-cli firewall rule apply 704ac064-4ff2-49ca-a6e6-88262cca8f8a --preset web
+
+	# Shortened example with aliases
+	vultr-cli fw r a 704ac064-4ff2-49ca-a6e6-88262cca8f8a -f rules.yaml
+	`
 )
 
 // NewCmdFirewall provides the CLI command functionality for Firewall
@@ -156,6 +191,24 @@ func NewCmdFirewall(base *cli.Base) *cobra.Command { //nolint:gocyclo
 				return fmt.Errorf("error parsing 'description' flag for firewall group create: %v", errDe)
 			}
 
+			preset, errPr := cmd.Flags().GetString("preset")
+			if errPr != nil {
+				return fmt.Errorf("error parsing 'preset' flag for firewall group create: %v", errPr)
+			}
+
+			var reqs []govultr.FirewallRuleReq
+			if preset != "" {
+				specs, errLo := loadPreset(preset)
+				if errLo != nil {
+					return fmt.Errorf("error loading firewall preset : %v", errLo)
+				}
+
+				var errEx error
+				if reqs, errEx = expandRules(specs); errEx != nil {
+					return fmt.Errorf("error in firewall preset %q : %v", preset, errEx)
+				}
+			}
+
 			o.GroupReq = &govultr.FirewallGroupReq{
 				Description: description,
 			}
@@ -165,14 +218,32 @@ func NewCmdFirewall(base *cli.Base) *cobra.Command { //nolint:gocyclo
 				return fmt.Errorf("error creating firewall group : %v", err)
 			}
 
-			data := &FirewallGroupPrinter{Group: *grp}
-			o.Base.Printer.Display(data, nil)
+			if preset == "" {
+				data := &FirewallGroupPrinter{Group: *grp}
+				o.Base.Printer.Display(data, nil)
 
-			return nil
+				return nil
+			}
+
+			results, errAp := o.applyRules(grp.ID, reqs)
+			if errAp != nil {
+				errAp = fmt.Errorf("error applying firewall preset : %v", errAp)
+			}
+
+			data := &FirewallRuleApplyPrinter{GroupID: grp.ID, Results: results}
+			return o.Base.Printer.DisplayWithError(data, errAp)
 		},
 	}
 
 	groupCreate.Flags().StringP("description", "d", "", "(optional) Description of firewall group.")
+	groupCreate.Flags().String(
+		"preset",
+		"",
+		fmt.Sprintf(
+			"(optional) Add the IPv4 and IPv6 rules of a preset to the group. Built-in presets: %s",
+			strings.Join(builtinPresetNames(), ", "),
+		),
+	)
 
 	// Group Update
 	groupUpdate := &cobra.Command{
@@ -464,11 +535,72 @@ a port or a colon separated port range.`,
 		},
 	}
 
+	// Rule Apply
+	ruleApply := &cobra.Command{
+		Use:     "apply <Firewall Group ID>",
+		Short:   "Apply a set of firewall rules from a file or preset",
+		Aliases: []string{"a"},
+		Long:    ruleApplyLong,
+		Example: ruleApplyExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a firewall group ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, errFi := cmd.Flags().GetString("file")
+			if errFi != nil {
+				return fmt.Errorf("error parsing 'file' flag for firewall rule apply : %v", errFi)
+			}
+
+			preset, errPr := cmd.Flags().GetString("preset")
+			if errPr != nil {
+				return fmt.Errorf("error parsing 'preset' flag for firewall rule apply : %v", errPr)
+			}
+
+			var specs []ruleSpec
+			var errLo error
+			if file != "" {
+				specs, errLo = loadRuleFile(file)
+			} else {
+				specs, errLo = loadPreset(preset)
+			}
+
+			if errLo != nil {
+				return fmt.Errorf("error loading firewall rules : %v", errLo)
+			}
+
+			reqs, errEx := expandRules(specs)
+			if errEx != nil {
+				return fmt.Errorf("error in firewall rules : %v", errEx)
+			}
+
+			results, err := o.applyRules(o.Base.Args[0], reqs)
+			if results == nil && err != nil {
+				return err
+			}
+
+			if err != nil {
+				err = fmt.Errorf("error applying firewall rules : %v", err)
+			}
+
+			data := &FirewallRuleApplyPrinter{GroupID: o.Base.Args[0], Results: results}
+			return o.Base.Printer.DisplayWithError(data, err)
+		},
+	}
+
+	ruleApply.Flags().StringP("file", "f", "", "Path to a YAML file of rules to apply.")
+	ruleApply.Flags().String("preset", "", "Name of a preset to apply.")
+	ruleApply.MarkFlagsOneRequired("file", "preset")
+	ruleApply.MarkFlagsMutuallyExclusive("file", "preset")
+
 	rule.AddCommand(
 		ruleList,
 		ruleGet,
 		ruleCreate,
 		ruleDelete,
+		ruleApply,
 	)
 
 	cmd.AddCommand(
@@ -519,6 +651,25 @@ func (o *options) listRules() ([]govultr.FirewallRule, *govultr.Meta, error) {
 	return rules, meta, err
 }
 
+// listRulesAll retrieves every rule in the group, following the paging cursors
+func (o *options) listRulesAll(groupID string) ([]govultr.FirewallRule, error) {
+	var all []govultr.FirewallRule
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		rules, meta, _, err := o.Base.Client.FirewallRule.List(o.Base.Context, groupID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, rules...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
 // getRule ...
 func (o *options) getRule() (*govultr.FirewallRule, error) {
 	id, errIn := strconv.Atoi(o.Base.Args[1])
@@ -0,0 +1,323 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vultr/govultr/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ipTypeV4 = "v4"
+	ipTypeV6 = "v6"
+
+	sourceCloudflare = "cloudflare"
+
+	ruleResultCreated = "created"
+	ruleResultSkipped = "skipped (exists)"
+)
+
+// ruleSet is the format of rule files and user-defined presets
+type ruleSet struct {
+	Rules []ruleSpec `yaml:"rules"`
+}
+
+// ruleSpec describes a rule before it is expanded into IPv4 and IPv6 rules.
+// An empty subnet, or "anywhere", allows all addresses of the IP types given
+// by ip_type, which defaults to both
+type ruleSpec struct {
+	Protocol string `yaml:"protocol"`
+	Port     string `yaml:"port"`
+	Subnet   string `yaml:"subnet"`
+	IPType   string `yaml:"ip_type"`
+	Source   string `yaml:"source"`
+	Notes    string `yaml:"notes"`
+}
+
+var privateSubnets = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"}
+
+// builtinPresets are the rule sets available to --preset without a preset
+// file in the config directory
+var builtinPresets = map[string][]ruleSpec{
+	"ssh-only": {
+		{Protocol: "tcp", Port: "22", Notes: "ssh"},
+		{Protocol: "icmp", Notes: "icmp"},
+	},
+	"web": {
+		{Protocol: "tcp", Port: "22", Notes: "ssh"},
+		{Protocol: "tcp", Port: "80", Notes: "http"},
+		{Protocol: "tcp", Port: "443", Notes: "https"},
+		{Protocol: "udp", Port: "443", Notes: "http3"},
+		{Protocol: "icmp", Notes: "icmp"},
+	},
+	"k8s-node": {
+		{Protocol: "tcp", Port: "22", Notes: "ssh"},
+		{Protocol: "tcp", Port: "6443", Notes: "kubernetes api"},
+		{Protocol: "tcp", Port: "10250", Notes: "kubelet"},
+		{Protocol: "tcp", Port: "30000:32767", Notes: "nodeport tcp"},
+		{Protocol: "udp", Port: "30000:32767", Notes: "nodeport udp"},
+		{Protocol: "icmp", Notes: "icmp"},
+	},
+	"database": databasePreset(),
+}
+
+// databasePreset allows ssh from anywhere and the common database ports from
+// private networks only
+func databasePreset() []ruleSpec {
+	rules := []ruleSpec{{Protocol: "tcp", Port: "22", Notes: "ssh"}}
+	for _, port := range []struct{ port, notes string }{
+		{"5432", "postgresql"},
+		{"3306", "mysql"},
+		{"6379", "redis/valkey"},
+	} {
+		for _, subnet := range privateSubnets {
+			rules = append(rules, ruleSpec{Protocol: "tcp", Port: port.port, Subnet: subnet, Notes: port.notes})
+		}
+	}
+
+	return append(rules, ruleSpec{Protocol: "icmp", Notes: "icmp"})
+}
+
+// presetDir returns the directory holding user-defined presets
+func presetDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vultr-cli", "firewall-presets"), nil
+}
+
+// builtinPresetNames returns the names of the built-in presets
+func builtinPresetNames() []string {
+	var names []string
+	for name := range builtinPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// presetNames returns the names of the built-in and user-defined presets
+func presetNames() []string {
+	names := make(map[string]bool)
+	for name := range builtinPresets {
+		names[name] = true
+	}
+
+	if dir, err := presetDir(); err == nil {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		for i := range files {
+			names[strings.TrimSuffix(filepath.Base(files[i]), ".yaml")] = true
+		}
+	}
+
+	var list []string
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// loadPreset returns the rules of a preset. A file named <preset>.yaml in the
+// preset directory takes precedence over a built-in preset of the same name
+func loadPreset(name string) ([]ruleSpec, error) {
+	// names are file names in the preset directory, never paths out of it
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid preset name %q", name)
+	}
+
+	if dir, err := presetDir(); err == nil {
+		path := filepath.Join(dir, name+".yaml")
+		if _, errSt := os.Stat(path); errSt == nil {
+			return loadRuleFile(path)
+		}
+	}
+
+	if rules, ok := builtinPresets[name]; ok {
+		return rules, nil
+	}
+
+	return nil, fmt.Errorf("unknown preset %q. Available presets : %s", name, strings.Join(presetNames(), ", "))
+}
+
+// loadRuleFile reads a YAML rule file
+func loadRuleFile(path string) ([]ruleSpec, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var set ruleSet
+	if err := yaml.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("error parsing %s : %v", path, err)
+	}
+
+	if len(set.Rules) == 0 {
+		return nil, fmt.Errorf("no rules found in %s", path)
+	}
+
+	return set.Rules, nil
+}
+
+// expandRules turns rule specs into the IPv4 and IPv6 rule requests they
+// describe
+func expandRules(specs []ruleSpec) ([]govultr.FirewallRuleReq, error) {
+	var reqs []govultr.FirewallRuleReq
+	for i := range specs {
+		expanded, err := expandRule(&specs[i])
+		if err != nil {
+			return nil, fmt.Errorf("rule %d : %v", i+1, err)
+		}
+		reqs = append(reqs, expanded...)
+	}
+
+	return reqs, nil
+}
+
+func expandRule(spec *ruleSpec) ([]govultr.FirewallRuleReq, error) {
+	protocol := strings.ToLower(spec.Protocol)
+	switch protocol {
+	case "tcp", "udp":
+		if spec.Port == "" {
+			return nil, fmt.Errorf("%s rules require a port", protocol)
+		}
+	case "icmp", "gre", "esp", "ah":
+		if spec.Port != "" {
+			return nil, fmt.Errorf("%s rules do not take a port", protocol)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", spec.Protocol)
+	}
+
+	ipTypes := []string{ipTypeV4, ipTypeV6}
+	if spec.IPType != "" {
+		if spec.IPType != ipTypeV4 && spec.IPType != ipTypeV6 {
+			return nil, fmt.Errorf("ip_type must be v4 or v6")
+		}
+		ipTypes = []string{spec.IPType}
+	}
+
+	source := strings.ToLower(spec.Source)
+	if source != "" && source != sourceCloudflare {
+		return nil, fmt.Errorf("unsupported source %q", spec.Source)
+	}
+
+	base := govultr.FirewallRuleReq{Protocol: protocol, Port: spec.Port, Source: source, Notes: spec.Notes}
+
+	if spec.Subnet != "" && spec.Subnet != "anywhere" {
+		if source != "" {
+			return nil, errors.New("subnet and source cannot be combined")
+		}
+
+		ip, network, err := net.ParseCIDR(spec.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q : %v", spec.Subnet, err)
+		}
+
+		ipType := ipTypeV6
+		if ip.To4() != nil {
+			ipType = ipTypeV4
+		}
+
+		if spec.IPType != "" && spec.IPType != ipType {
+			return nil, fmt.Errorf("subnet %s is not an %s network", spec.Subnet, spec.IPType)
+		}
+
+		size, _ := network.Mask.Size()
+		base.IPType = ipType
+		base.Subnet = network.IP.String()
+		base.SubnetSize = size
+
+		return []govultr.FirewallRuleReq{base}, nil
+	}
+
+	var reqs []govultr.FirewallRuleReq
+	for _, ipType := range ipTypes {
+		req := base
+		req.IPType = ipType
+		req.Subnet = "0.0.0.0"
+		if ipType == ipTypeV6 {
+			req.Subnet = "::"
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs, nil
+}
+
+// ruleKey identifies a rule for duplicate detection
+func ruleKey(ipType, protocol, subnet string, size int, port, source string) string {
+	if strings.EqualFold(source, sourceCloudflare) {
+		subnet, size = "", 0
+	}
+
+	if ip := net.ParseIP(subnet); ip != nil {
+		subnet = ip.String()
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s|%s|%s/%d|%s|%s", ipType, protocol, subnet, size, port, source))
+}
+
+// ======================================
+
+// RuleApplyResult holds the outcome of applying a single rule
+type RuleApplyResult struct {
+	Rule   govultr.FirewallRuleReq `json:"rule"`
+	ID     int                     `json:"id,omitempty"`
+	Result string                  `json:"result"`
+}
+
+// applyRules creates the rules in the group, skipping any rule which already
+// exists in the group. It continues past failures and returns an error when
+// any rule could not be created
+func (o *options) applyRules(groupID string, reqs []govultr.FirewallRuleReq) ([]RuleApplyResult, error) {
+	existing, err := o.listRulesAll(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving firewall rules : %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range existing {
+		r := &existing[i]
+		seen[ruleKey(r.IPType, r.Protocol, r.Subnet, r.SubnetSize, r.Port, r.Source)] = true
+	}
+
+	failed := 0
+	results := make([]RuleApplyResult, len(reqs))
+	for i := range reqs {
+		r := &reqs[i]
+		results[i].Rule = *r
+
+		key := ruleKey(r.IPType, r.Protocol, r.Subnet, r.SubnetSize, r.Port, r.Source)
+		if seen[key] {
+			results[i].Result = ruleResultSkipped
+			continue
+		}
+
+		rule, _, errCr := o.Base.Client.FirewallRule.Create(o.Base.Context, groupID, r)
+		if errCr != nil {
+			failed++
+			results[i].Result = errCr.Error()
+			continue
+		}
+
+		seen[key] = true
+		results[i].ID = rule.ID
+		results[i].Result = ruleResultCreated
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d firewall rules could not be created", failed, len(reqs))
+	}
+
+	return results, nil
+}
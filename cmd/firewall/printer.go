@@ -221,3 +221,72 @@ func (f *FirewallRulePrinter) Data() [][]string {
 func (f *FirewallRulePrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// FirewallRuleApplyPrinter ...
+type FirewallRuleApplyPrinter struct {
+	GroupID string            `json:"firewall_group_id"`
+	Results []RuleApplyResult `json:"rules"`
+}
+
+// JSON ...
+func (f *FirewallRuleApplyPrinter) JSON() []byte {
+	return printer.MarshalObject(f, "json")
+}
+
+// YAML ...
+func (f *FirewallRuleApplyPrinter) YAML() []byte {
+	return printer.MarshalObject(f, "yaml")
+}
+
+// Columns ...
+func (f *FirewallRuleApplyPrinter) Columns() [][]string {
+	return [][]string{
+		0: {"FIREWALL GROUP ID", f.GroupID},
+		1: {
+			"RULE NUMBER",
+			"TYPE",
+			"PROTOCOL",
+			"PORT",
+			"NETWORK",
+			"SOURCE",
+			"NOTES",
+			"RESULT",
+		},
+	}
+}
+
+// Data ...
+func (f *FirewallRuleApplyPrinter) Data() [][]string {
+	if len(f.Results) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range f.Results {
+		id := "---"
+		if f.Results[i].ID != 0 {
+			id = strconv.Itoa(f.Results[i].ID)
+		}
+
+		r := f.Results[i].Rule
+		data = append(data, []string{
+			id,
+			r.IPType,
+			r.Protocol,
+			r.Port,
+			utils.FormatFirewallNetwork(r.Subnet, r.SubnetSize),
+			utils.GetFirewallSource(r.Source),
+			r.Notes,
+			f.Results[i].Result,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (f *FirewallRuleApplyPrinter) Paging() [][]string {
+	return nil
+}
@@ -120,6 +120,20 @@ var (
 	# Full example
 	vultr-cli instance vpc2 detach <instanceID> --vpc-id="2126b7d9-5e2a-491e-8840-838aa6b5f294"
 	`
+
+	migrateLong = `Move an instance to another region. A snapshot of the instance is taken and, once complete, a new
+instance is created from it in --region with the plan of the source unless --plan is given. The label, hostname,
+tags, firewall group and IPv6 setting are carried over and each VPC is replaced by the VPC with the same description
+in the destination region, when there is one. SSH keys are kept on the disk and can be added with --ssh-keys.
+Reserved IPs are bound to their region and stay with the source. The command then asks before deleting the source
+instance, which is kept unless confirmed or --delete-source is given`
+	migrateExample = `
+	# Full example
+	vultr-cli instance migrate a7898453-dd9e-4b47-bdab-9dd7a3448f1f --region="ams" --plan="vc2-2c-4gb"
+
+	# Without a prompt, cleaning up the source and the snapshot
+	vultr-cli instance migrate a7898453-dd9e-4b47-bdab-9dd7a3448f1f --region="ams" --delete-source --delete-snapshot
+	`
 )
 
 // NewCmdInstance ...
@@ -1711,6 +1725,46 @@ func NewCmdInstance(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		vpc2Detach,
 	)
 
+	// Migrate
+	migrate := &cobra.Command{
+		Use:     "migrate <Instance ID>",
+		Short:   "Move an instance to another region through a snapshot",
+		Long:    migrateLong,
+		Example: migrateExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide an instance ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := migrateFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// text output shows each step as it runs
+			steps, err := o.newMigrate(opts).run()
+			if o.Base.Printer.IsText() {
+				return err
+			}
+
+			return o.Base.Printer.DisplayWithError(&MigrateStepsPrinter{Steps: steps}, err)
+		},
+	}
+
+	migrate.Flags().StringP("region", "r", "", "ID of the region to move the instance to")
+	if err := migrate.MarkFlagRequired("region"); err != nil {
+		fmt.Printf("error marking instance migrate 'region' flag required: %v", err)
+		os.Exit(1)
+	}
+
+	migrate.Flags().StringP("plan", "p", "", "(optional) plan of the new instance, defaults to the plan of the source")
+	migrate.Flags().StringSliceP("ssh-keys", "s", []string{}, "(optional) ssh keys to assign to the new instance")
+	migrate.Flags().String("reserved-ipv4", "", "(optional) ID of a reserved IP in the region to use as the main IP")
+	migrate.Flags().Bool("delete-source", false, "(optional) delete the source instance without asking")
+	migrate.Flags().Bool("delete-snapshot", false, "(optional) delete the migration snapshot once done")
+
 	// Bandwidth
 	bandwidth := &cobra.Command{
 		Use:   "bandwidth <Instance ID>",
@@ -1759,6 +1813,7 @@ func NewCmdInstance(base *cli.Base) *cobra.Command { //nolint:funlen,gocyclo
 		vpc,
 		vpc2,
 		bandwidth,
+		migrate,
 	)
 
 	return cmd
@@ -0,0 +1,438 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/printer"
+	"github.com/vultr/vultr-cli/v3/cmd/utils"
+)
+
+const (
+	migrateSnapshotInterval = 15 * time.Second
+	migrateSnapshotTimeout  = 2 * time.Hour
+	migrateInstanceInterval = 10 * time.Second
+	migrateInstanceTimeout  = 30 * time.Minute
+
+	statusActive   = "active"
+	statusComplete = "complete"
+
+	stepPassed  = "ok"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+)
+
+// MigrateStep records a single step of an instance migration
+type MigrateStep struct {
+	Time   string `json:"time"`
+	Step   string `json:"step"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// migrateOptions holds the flags of instance migrate
+type migrateOptions struct {
+	Region         string
+	Plan           string
+	SSHKeys        []string
+	ReservedIPv4   string
+	DeleteSource   bool
+	DeleteSnapshot bool
+}
+
+// migrateRun moves an instance to another region through a snapshot,
+// recording each step
+type migrateRun struct {
+	o           *options
+	opts        *migrateOptions
+	steps       []MigrateStep
+	header      bool
+	source      *govultr.Instance
+	snapshot    *govultr.Snapshot
+	target      *govultr.Instance
+	vpcs        []string
+	reservedIPs []govultr.ReservedIP
+	deleted     bool
+}
+
+// matchVPCs returns the IDs of the VPCs in the region with the same
+// description as the source VPCs, and the source VPCs without a match
+func matchVPCs(source, candidates []govultr.VPC, region string) (matched, missing []string) {
+	for i := range source {
+		idx := slices.IndexFunc(candidates, func(v govultr.VPC) bool {
+			return v.Region == region && v.Description != "" && v.Description == source[i].Description
+		})
+		if idx < 0 {
+			missing = append(missing, printer.ValueOr(source[i].Description, source[i].ID))
+			continue
+		}
+		matched = append(matched, candidates[idx].ID)
+	}
+
+	return matched, missing
+}
+
+// ======================================
+
+func (o *options) newMigrate(opts *migrateOptions) *migrateRun {
+	return &migrateRun{o: o, opts: opts, header: true}
+}
+
+// record adds a step, displaying it as it happens with text output
+func (m *migrateRun) record(step, result, detail string) {
+	s := MigrateStep{
+		Time:   time.Now().Format(time.RFC3339),
+		Step:   step,
+		Result: result,
+		Detail: detail,
+	}
+	m.steps = append(m.steps, s)
+
+	if m.o.Base.Printer.IsText() {
+		m.o.Base.Printer.Display(&MigrateStepsPrinter{Steps: []MigrateStep{s}, Header: m.header}, nil)
+		m.header = false
+	}
+}
+
+// fail records a failed step and returns it as an error
+func (m *migrateRun) fail(step string, err error) error {
+	m.record(step, stepFailed, err.Error())
+	return fmt.Errorf("migration aborted at %s : %v", step, err)
+}
+
+// run performs the migration steps in order, stopping at the first failure.
+// The source instance is never deleted after a failure
+func (m *migrateRun) run() ([]MigrateStep, error) {
+	steps := []func() error{
+		m.inspect,
+		m.networks,
+		m.takeSnapshot,
+		m.create,
+		m.reservedIP,
+		m.deleteSource,
+		m.deleteSnapshot,
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			if m.target != nil && !m.deleted {
+				err = fmt.Errorf("%v, instance %s has been left in place", err, m.target.ID)
+			}
+			return m.steps, err
+		}
+	}
+
+	return m.steps, nil
+}
+
+// inspect checks that the source instance can be moved to the destination
+// region with the plan
+func (m *migrateRun) inspect() error {
+	source, _, err := m.o.Base.Client.Instance.Get(m.o.Base.Context, m.o.Base.Args[0])
+	if err != nil {
+		return m.fail("inspect", fmt.Errorf("error retrieving instance : %v", err))
+	}
+
+	if source.Region == m.opts.Region {
+		return m.fail("inspect", fmt.Errorf("instance is already in %s", source.Region))
+	}
+
+	if m.opts.Plan == "" {
+		m.opts.Plan = source.Plan
+	}
+
+	avail, _, err := m.o.Base.Client.Region.Availability(m.o.Base.Context, m.opts.Region, "all")
+	if err != nil {
+		return m.fail("inspect", fmt.Errorf("error retrieving plan availability of %s : %v", m.opts.Region, err))
+	}
+
+	if !slices.Contains(avail.AvailablePlans, m.opts.Plan) {
+		return m.fail("inspect", fmt.Errorf("plan %s is not available in %s", m.opts.Plan, m.opts.Region))
+	}
+
+	m.source = source
+	m.record("inspect", stepPassed, fmt.Sprintf("%s (%s) from %s to %s", source.Label, m.opts.Plan, source.Region,
+		m.opts.Region))
+
+	return nil
+}
+
+// networks maps the VPCs of the source instance to VPCs with the same
+// description in the destination region and finds its reserved IPs
+func (m *migrateRun) networks() error {
+	infos, _, _, err := m.o.Base.Client.Instance.ListVPCInfo(m.o.Base.Context, m.source.ID, nil)
+	if err != nil {
+		return m.fail("networks", fmt.Errorf("error retrieving instance VPCs : %v", err))
+	}
+
+	var sourceVPCs []govultr.VPC
+	for i := range infos {
+		vpc, _, err := m.o.Base.Client.VPC.Get(m.o.Base.Context, infos[i].ID)
+		if err != nil {
+			return m.fail("networks", fmt.Errorf("error retrieving VPC %s : %v", infos[i].ID, err))
+		}
+		sourceVPCs = append(sourceVPCs, *vpc)
+	}
+
+	var detail []string
+	if len(sourceVPCs) > 0 {
+		vpcs, err := m.o.listAllVPCs()
+		if err != nil {
+			return m.fail("networks", fmt.Errorf("error retrieving VPCs : %v", err))
+		}
+
+		var missing []string
+		m.vpcs, missing = matchVPCs(sourceVPCs, vpcs, m.opts.Region)
+		detail = append(detail, fmt.Sprintf("%d of %d VPCs matched", len(m.vpcs), len(sourceVPCs)))
+		if len(missing) > 0 {
+			detail = append(detail, fmt.Sprintf("no VPC in %s for %s", m.opts.Region, strings.Join(missing, ", ")))
+		}
+	}
+
+	ips, err := m.o.listAllReservedIPs()
+	if err != nil {
+		return m.fail("networks", fmt.Errorf("error retrieving reserved IPs : %v", err))
+	}
+
+	for i := range ips {
+		if ips[i].InstanceID == m.source.ID {
+			m.reservedIPs = append(m.reservedIPs, ips[i])
+		}
+	}
+
+	if len(detail) == 0 {
+		detail = append(detail, "no VPCs attached")
+	}
+	m.record("networks", stepPassed, strings.Join(detail, "; "))
+
+	return nil
+}
+
+// takeSnapshot snapshots the source instance and waits for it to complete
+func (m *migrateRun) takeSnapshot() error {
+	snapshot, _, err := m.o.Base.Client.Snapshot.Create(m.o.Base.Context, &govultr.SnapshotReq{
+		InstanceID:  m.source.ID,
+		Description: fmt.Sprintf("migrate %s to %s", printer.ValueOr(m.source.Label, m.source.ID), m.opts.Region),
+	})
+	if err != nil {
+		return m.fail("snapshot", fmt.Errorf("error creating snapshot : %v", err))
+	}
+
+	deadline := time.Now().Add(migrateSnapshotTimeout)
+	for !strings.EqualFold(snapshot.Status, statusComplete) {
+		if time.Now().After(deadline) {
+			return m.fail("snapshot", fmt.Errorf("timed out waiting for snapshot %s, status is %s", snapshot.ID,
+				snapshot.Status))
+		}
+
+		time.Sleep(migrateSnapshotInterval)
+
+		if snapshot, _, err = m.o.Base.Client.Snapshot.Get(m.o.Base.Context, snapshot.ID); err != nil {
+			return m.fail("snapshot", fmt.Errorf("error retrieving snapshot : %v", err))
+		}
+	}
+
+	m.snapshot = snapshot
+	m.record("snapshot", stepPassed, fmt.Sprintf("created %s", snapshot.ID))
+
+	return nil
+}
+
+// create creates the instance in the destination region from the snapshot,
+// carrying over the settings of the source, and waits for it to be active
+func (m *migrateRun) create() error {
+	req := &govultr.InstanceCreateReq{
+		Region:          m.opts.Region,
+		Plan:            m.opts.Plan,
+		SnapshotID:      m.snapshot.ID,
+		Label:           m.source.Label,
+		Hostname:        m.source.Hostname,
+		Tags:            m.source.Tags,
+		FirewallGroupID: m.source.FirewallGroupID,
+		SSHKeys:         m.opts.SSHKeys,
+		AttachVPC:       m.vpcs,
+		ReservedIPv4:    m.opts.ReservedIPv4,
+		EnableIPv6:      govultr.BoolToBoolPtr(m.source.V6MainIP != ""),
+	}
+
+	target, _, err := m.o.Base.Client.Instance.Create(m.o.Base.Context, req)
+	if err != nil {
+		return m.fail("create", fmt.Errorf("error creating instance : %v", err))
+	}
+	m.target = target
+
+	deadline := time.Now().Add(migrateInstanceTimeout)
+	for !strings.EqualFold(target.Status, statusActive) {
+		if time.Now().After(deadline) {
+			return m.fail("create", fmt.Errorf("timed out waiting for instance %s, status is %s", target.ID,
+				target.Status))
+		}
+
+		time.Sleep(migrateInstanceInterval)
+
+		if target, _, err = m.o.Base.Client.Instance.Get(m.o.Base.Context, m.target.ID); err != nil {
+			return m.fail("create", fmt.Errorf("error retrieving instance : %v", err))
+		}
+	}
+
+	m.target = target
+	m.record("create", stepPassed, fmt.Sprintf("created %s with IP %s", target.ID, target.MainIP))
+
+	return nil
+}
+
+// reservedIP reports the reserved IPs of the source instance, which are
+// bound to its region and cannot follow it
+func (m *migrateRun) reservedIP() error {
+	if len(m.reservedIPs) == 0 {
+		return nil
+	}
+
+	var subnets []string
+	for i := range m.reservedIPs {
+		subnets = append(subnets, m.reservedIPs[i].Subnet)
+	}
+
+	m.record("reserved-ips", stepSkipped, fmt.Sprintf(
+		"reserved IPs cannot leave %s, not moved: %s",
+		m.source.Region,
+		strings.Join(subnets, ", "),
+	))
+
+	return nil
+}
+
+// deleteSource deletes the source instance once confirmed, or when
+// --delete-source was given
+func (m *migrateRun) deleteSource() error {
+	confirmed := m.opts.DeleteSource
+	if !confirmed && m.o.Base.Printer.IsText() {
+		confirmed = utils.Confirm(fmt.Sprintf(
+			"Delete the source instance %s (%s) in %s?",
+			m.source.ID,
+			printer.ValueOr(m.source.Label, m.source.MainIP),
+			m.source.Region,
+		))
+	}
+
+	if !confirmed {
+		m.record("delete-source", stepSkipped, fmt.Sprintf("instance %s has been kept", m.source.ID))
+		return nil
+	}
+
+	if err := m.o.Base.Client.Instance.Delete(m.o.Base.Context, m.source.ID); err != nil {
+		return m.fail("delete-source", fmt.Errorf("error deleting instance : %v", err))
+	}
+	m.deleted = true
+
+	m.record("delete-source", stepPassed, fmt.Sprintf("deleted %s", m.source.ID))
+
+	return nil
+}
+
+// deleteSnapshot deletes the migration snapshot when --delete-snapshot was
+// given
+func (m *migrateRun) deleteSnapshot() error {
+	if !m.opts.DeleteSnapshot {
+		m.record("delete-snapshot", stepSkipped, fmt.Sprintf("snapshot %s has been kept", m.snapshot.ID))
+		return nil
+	}
+
+	if err := m.o.Base.Client.Snapshot.Delete(m.o.Base.Context, m.snapshot.ID); err != nil {
+		return m.fail("delete-snapshot", fmt.Errorf("error deleting snapshot : %v", err))
+	}
+
+	m.record("delete-snapshot", stepPassed, fmt.Sprintf("deleted %s", m.snapshot.ID))
+
+	return nil
+}
+
+// listAllVPCs retrieves every VPC, following the paging cursors
+func (o *options) listAllVPCs() ([]govultr.VPC, error) {
+	var all []govultr.VPC
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		vpcs, meta, _, err := o.Base.Client.VPC.List(o.Base.Context, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, vpcs...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// listAllReservedIPs retrieves every reserved IP, following the paging
+// cursors
+func (o *options) listAllReservedIPs() ([]govultr.ReservedIP, error) {
+	var all []govultr.ReservedIP
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		ips, meta, _, err := o.Base.Client.ReservedIP.List(o.Base.Context, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ips...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// migrateFlags reads the flags of instance migrate
+func migrateFlags(cmd *cobra.Command) (*migrateOptions, error) {
+	region, errRe := cmd.Flags().GetString("region")
+	if errRe != nil {
+		return nil, fmt.Errorf("error parsing flag 'region' for instance migrate : %v", errRe)
+	}
+
+	plan, errPl := cmd.Flags().GetString("plan")
+	if errPl != nil {
+		return nil, fmt.Errorf("error parsing flag 'plan' for instance migrate : %v", errPl)
+	}
+
+	sshKeys, errSs := cmd.Flags().GetStringSlice("ssh-keys")
+	if errSs != nil {
+		return nil, fmt.Errorf("error parsing flag 'ssh-keys' for instance migrate : %v", errSs)
+	}
+
+	reservedIP, errRi := cmd.Flags().GetString("reserved-ipv4")
+	if errRi != nil {
+		return nil, fmt.Errorf("error parsing flag 'reserved-ipv4' for instance migrate : %v", errRi)
+	}
+
+	deleteSource, errDs := cmd.Flags().GetBool("delete-source")
+	if errDs != nil {
+		return nil, fmt.Errorf("error parsing flag 'delete-source' for instance migrate : %v", errDs)
+	}
+
+	deleteSnapshot, errDn := cmd.Flags().GetBool("delete-snapshot")
+	if errDn != nil {
+		return nil, fmt.Errorf("error parsing flag 'delete-snapshot' for instance migrate : %v", errDn)
+	}
+
+	if region == "" {
+		return nil, errors.New("please provide a destination region")
+	}
+
+	return &migrateOptions{
+		Region:         region,
+		Plan:           plan,
+		SSHKeys:        sshKeys,
+		ReservedIPv4:   reservedIP,
+		DeleteSource:   deleteSource,
+		DeleteSnapshot: deleteSnapshot,
+	}, nil
+}
@@ -522,3 +522,55 @@ func (v *VPC2sPrinter) Data() [][]string {
 func (v *VPC2sPrinter) Paging() [][]string {
 	return printer.NewPagingFromMeta(v.Meta).Compose()
 }
+
+// ======================================
+
+// MigrateStepsPrinter ...
+type MigrateStepsPrinter struct {
+	Steps  []MigrateStep `json:"steps"`
+	Header bool          `json:"-"`
+}
+
+// JSON ...
+func (m *MigrateStepsPrinter) JSON() []byte {
+	return printer.MarshalObject(m, "json")
+}
+
+// YAML ...
+func (m *MigrateStepsPrinter) YAML() []byte {
+	return printer.MarshalObject(m, "yaml")
+}
+
+// Columns ...
+func (m *MigrateStepsPrinter) Columns() [][]string {
+	if !m.Header {
+		return nil
+	}
+
+	return [][]string{0: {
+		"TIME",
+		"STEP",
+		"RESULT",
+		"DETAIL",
+	}}
+}
+
+// Data ...
+func (m *MigrateStepsPrinter) Data() [][]string {
+	var data [][]string
+	for i := range m.Steps {
+		data = append(data, []string{
+			m.Steps[i].Time,
+			m.Steps[i].Step,
+			m.Steps[i].Result,
+			m.Steps[i].Detail,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (m *MigrateStepsPrinter) Paging() [][]string {
+	return nil
+}
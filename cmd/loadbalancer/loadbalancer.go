@@ -2,12 +2,18 @@
 package loadbalancer
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -65,6 +71,27 @@ var (
 	#Full example with attached VPC
 	vultr-cli load-balancer update 57539f6f-66a2-4580-936b-d0af934bce5d --vpc="bff36707-977e-4357-8f30-bef3339155cc"
 	`
+
+	statusLong = `Show the status of each backend instance attached to a load balancer.
+
+The power and server status of every attached instance is retrieved from the
+instance service. When --probe is passed, the load balancer health check
+(protocol, port and path) is also performed locally against each backend IP.
+Probes run from this machine, so results can differ from the load balancer's
+own view when firewall rules only allow traffic from the load balancer.`
+	statusExample = `
+	# Full example
+	vultr-cli load-balancer status 57539f6f-66a2-4580-936b-d0af934bce5d
+
+	# Probe the health check path on each backend
+	vultr-cli load-balancer status 57539f6f-66a2-4580-936b-d0af934bce5d --probe
+
+	# Probe the backends on their VPC addresses and refresh every 10 seconds
+	vultr-cli load-balancer status 57539f6f-66a2-4580-936b-d0af934bce5d --probe --internal --watch --interval=10
+
+	# Shortened with alias commands
+	vultr-cli lb s 57539f6f-66a2-4580-936b-d0af934bce5d -p -w
+	`
 )
 
 const (
@@ -76,6 +103,7 @@ const (
 	loadBalancerDefaultPort               = 80
 	loadBalancerDefaultFrontendPort       = 80
 	loadBalancerDefaultBackendPort        = 80
+	loadBalancerDefaultStatusInterval     = 5
 )
 
 // NewCmdLoadBalancer provides the CLI command for load balancers
@@ -1325,12 +1353,93 @@ When not provided, load balancer defaults to public network.`,
 		getFirewallRule,
 	)
 
+	// Status
+	status := &cobra.Command{
+		Use:     "status <Load Balancer ID>",
+		Short:   "Show the health of load balancer backends",
+		Aliases: []string{"s"},
+		Long:    statusLong,
+		Example: statusExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide a load balancer ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			probe, errPr := cmd.Flags().GetBool("probe")
+			if errPr != nil {
+				return fmt.Errorf("error parsing flag 'probe' for load balancer status : %v", errPr)
+			}
+
+			internal, errIn := cmd.Flags().GetBool("internal")
+			if errIn != nil {
+				return fmt.Errorf("error parsing flag 'internal' for load balancer status : %v", errIn)
+			}
+
+			watch, errWa := cmd.Flags().GetBool("watch")
+			if errWa != nil {
+				return fmt.Errorf("error parsing flag 'watch' for load balancer status : %v", errWa)
+			}
+
+			interval, errIt := cmd.Flags().GetInt("interval")
+			if errIt != nil {
+				return fmt.Errorf("error parsing flag 'interval' for load balancer status : %v", errIt)
+			}
+
+			if interval < 1 {
+				return errors.New("error parsing flag 'interval' for load balancer status : must be at least 1 second")
+			}
+
+			// JSON and YAML output exit once displayed, so they cannot be refreshed
+			if watch && !o.Base.Printer.IsText() {
+				return errors.New("--watch is only supported with text output")
+			}
+
+			for {
+				backends, err := o.status(probe, internal)
+				if err != nil {
+					return fmt.Errorf("error retrieving load balancer status : %v", err)
+				}
+
+				o.Base.Printer.Display(&LBStatusPrinter{Backends: backends}, nil)
+
+				if !watch {
+					return nil
+				}
+
+				time.Sleep(time.Duration(interval) * time.Second)
+				fmt.Println()
+			}
+		},
+	}
+
+	status.Flags().BoolP("probe", "p", false, "(optional) Perform the health check locally against each backend.")
+	status.Flags().Bool(
+		"internal",
+		false,
+		"(optional) Probe backends on their internal (VPC) IP instead of their main IP.",
+	)
+	status.Flags().BoolP(
+		"watch",
+		"w",
+		false,
+		"(optional) Refresh the status until interrupted. Only supported with text output.",
+	)
+	status.Flags().IntP(
+		"interval",
+		"i",
+		loadBalancerDefaultStatusInterval,
+		"(optional) Number of seconds between refreshes when using --watch.",
+	)
+
 	cmd.AddCommand(
 		list,
 		get,
 		create,
 		update,
 		del,
+		status,
 		forwarding,
 		firewall,
 		ssl,
@@ -1406,6 +1515,172 @@ func (o *options) getFirewallRule() (*govultr.LBFirewallRule, error) {
 	return r, err
 }
 
+func (o *options) status(probe, internal bool) ([]LBBackendStatus, error) {
+	lb, err := o.get()
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]LBBackendStatus, len(lb.Instances))
+	for i := range lb.Instances {
+		backends[i] = LBBackendStatus{InstanceID: lb.Instances[i], Health: lbHealthUnknown}
+
+		instance, _, errIn := o.Base.Client.Instance.Get(o.Base.Context, lb.Instances[i])
+		if errIn != nil {
+			backends[i].Error = errIn.Error()
+			continue
+		}
+
+		backends[i].Label = instance.Label
+		backends[i].IP = instance.MainIP
+		if internal {
+			backends[i].IP = instance.InternalIP
+		}
+		backends[i].PowerStatus = instance.PowerStatus
+		backends[i].ServerStatus = instance.ServerStatus
+		backends[i].Health = instanceHealth(instance)
+	}
+
+	if probe && lb.HealthCheck != nil {
+		var wg sync.WaitGroup
+		for i := range backends {
+			if backends[i].IP == "" || backends[i].Error != "" {
+				continue
+			}
+
+			wg.Add(1)
+			go func(b *LBBackendStatus) {
+				defer wg.Done()
+				b.probe(o.Base.Context, lb.HealthCheck)
+			}(&backends[i])
+		}
+		wg.Wait()
+	}
+
+	return backends, nil
+}
+
+// ======================================
+
+const (
+	lbHealthUnknown   = "unknown"
+	lbHealthHealthy   = "healthy"
+	lbHealthUnhealthy = "unhealthy"
+	lbHealthDegraded  = "degraded"
+
+	lbProbeDefaultTimeout = 5
+)
+
+// probeClient is shared by every HTTP probe so that --watch reuses its
+// connections. Backends are addressed by IP so their certificates cannot be
+// verified
+var probeClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// LBBackendStatus holds the combined instance and probe status of a single
+// load balancer backend
+type LBBackendStatus struct {
+	InstanceID   string `json:"instance_id"`
+	Label        string `json:"label"`
+	IP           string `json:"ip"`
+	PowerStatus  string `json:"power_status"`
+	ServerStatus string `json:"server_status"`
+	Probe        string `json:"probe,omitempty"`
+	Latency      string `json:"latency,omitempty"`
+	Health       string `json:"health"`
+	Error        string `json:"error,omitempty"`
+}
+
+// instanceHealth derives a backend health value from the instance statuses
+// alone
+func instanceHealth(instance *govultr.Instance) string {
+	if instance.PowerStatus != "running" {
+		return lbHealthUnhealthy
+	}
+
+	if instance.ServerStatus != "ok" {
+		return lbHealthDegraded
+	}
+
+	return lbHealthHealthy
+}
+
+// probe performs the load balancer health check against the backend and marks
+// it unhealthy when the check fails
+func (b *LBBackendStatus) probe(ctx context.Context, hc *govultr.HealthCheck) {
+	timeout := time.Duration(hc.ResponseTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = lbProbeDefaultTimeout * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(b.IP, strconv.Itoa(hc.Port))
+	start := time.Now()
+
+	var err error
+	switch strings.ToLower(hc.Protocol) {
+	case "http", "https":
+		b.Probe, err = probeHTTP(ctx, strings.ToLower(hc.Protocol), addr, hc.Path)
+	default:
+		b.Probe, err = probeTCP(ctx, addr)
+	}
+
+	b.Latency = time.Since(start).Round(time.Millisecond).String()
+
+	if err != nil {
+		b.Probe = err.Error()
+		b.Health = lbHealthUnhealthy
+	}
+}
+
+// probeTCP checks that a TCP connection can be opened to the address
+func probeTCP(ctx context.Context, addr string) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	if err := conn.Close(); err != nil {
+		return "", err
+	}
+
+	return "tcp open", nil
+}
+
+// probeHTTP requests the health check path on the address and treats any
+// non-error status code as healthy
+func probeHTTP(ctx context.Context, scheme, addr, path string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", scheme, addr, path), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("%s %s", strings.ToUpper(scheme), resp.Status)
+	}
+
+	return fmt.Sprintf("%s %s", strings.ToUpper(scheme), resp.Status), nil
+}
+
 // ======================================
 
 // formatFirewallRules parses forwarding rules into proper format
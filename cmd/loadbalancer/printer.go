@@ -517,3 +517,67 @@ func (f *FWRulePrinter) Data() [][]string {
 func (f *FWRulePrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// LBStatusPrinter ...
+type LBStatusPrinter struct {
+	Backends []LBBackendStatus `json:"backends"`
+}
+
+// JSON ...
+func (l *LBStatusPrinter) JSON() []byte {
+	return printer.MarshalObject(l, "json")
+}
+
+// YAML ...
+func (l *LBStatusPrinter) YAML() []byte {
+	return printer.MarshalObject(l, "yaml")
+}
+
+// Columns ...
+func (l *LBStatusPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"INSTANCE ID",
+		"LABEL",
+		"IP",
+		"POWER STATUS",
+		"SERVER STATUS",
+		"PROBE",
+		"LATENCY",
+		"HEALTH",
+	}}
+}
+
+// Data ...
+func (l *LBStatusPrinter) Data() [][]string {
+	if len(l.Backends) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range l.Backends {
+		probe := l.Backends[i].Probe
+		if l.Backends[i].Error != "" {
+			probe = l.Backends[i].Error
+		}
+
+		data = append(data, []string{
+			l.Backends[i].InstanceID,
+			printer.ValueOr(l.Backends[i].Label, "---"),
+			printer.ValueOr(l.Backends[i].IP, "---"),
+			printer.ValueOr(l.Backends[i].PowerStatus, "---"),
+			printer.ValueOr(l.Backends[i].ServerStatus, "---"),
+			printer.ValueOr(probe, "---"),
+			printer.ValueOr(l.Backends[i].Latency, "---"),
+			l.Backends[i].Health,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (l *LBStatusPrinter) Paging() [][]string {
+	return nil
+}
@@ -0,0 +1,317 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/vultr-cli/v3/cmd/printer"
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
+	"gopkg.in/yaml.v3"
+)
+
+// bucketConfig describes a bucket configuration managed by a get, set and
+// delete command group
+type bucketConfig struct {
+	Name    string
+	Short   string
+	Long    string
+	Example string
+
+	get func(ctx context.Context, c *s3.Client, bucket string) (printer.ResourceOutput, error)
+	set func(ctx context.Context, c *s3.Client, bucket string, document []byte, dryRun bool) error
+	del func(ctx context.Context, c *s3.Client, bucket string) error
+}
+
+// bucketConfigs are the bucket configurations managed by object-storage
+// bucket
+var bucketConfigs = []bucketConfig{
+	{
+		Name:    "lifecycle",
+		Short:   "Manage the lifecycle expiry rules of a bucket",
+		Long:    bucketLifecycleLong,
+		Example: bucketLifecycleExample,
+		get: func(ctx context.Context, c *s3.Client, bucket string) (printer.ResourceOutput, error) {
+			config, err := c.GetBucketLifecycle(ctx, bucket)
+			return &LifecyclePrinter{Config: config}, err
+		},
+		set: func(ctx context.Context, c *s3.Client, bucket string, document []byte, dryRun bool) error {
+			config := &s3.LifecycleConfiguration{}
+			if err := decodeDocument(document, config); err != nil {
+				return err
+			}
+			if err := config.Validate(); err != nil || dryRun {
+				return err
+			}
+			return c.PutBucketLifecycle(ctx, bucket, config)
+		},
+		del: func(ctx context.Context, c *s3.Client, bucket string) error {
+			return c.DeleteBucketLifecycle(ctx, bucket)
+		},
+	},
+	{
+		Name:    "cors",
+		Short:   "Manage the CORS rules of a bucket",
+		Long:    bucketCORSLong,
+		Example: bucketCORSExample,
+		get: func(ctx context.Context, c *s3.Client, bucket string) (printer.ResourceOutput, error) {
+			config, err := c.GetBucketCORS(ctx, bucket)
+			return &CORSPrinter{Config: config}, err
+		},
+		set: func(ctx context.Context, c *s3.Client, bucket string, document []byte, dryRun bool) error {
+			config := &s3.CORSConfiguration{}
+			if err := decodeDocument(document, config); err != nil {
+				return err
+			}
+			if err := config.Validate(); err != nil || dryRun {
+				return err
+			}
+			return c.PutBucketCORS(ctx, bucket, config)
+		},
+		del: func(ctx context.Context, c *s3.Client, bucket string) error {
+			return c.DeleteBucketCORS(ctx, bucket)
+		},
+	},
+	{
+		Name:    "policy",
+		Short:   "Manage the policy of a bucket",
+		Long:    bucketPolicyLong,
+		Example: bucketPolicyExample,
+		get: func(ctx context.Context, c *s3.Client, bucket string) (printer.ResourceOutput, error) {
+			policy, err := c.GetBucketPolicy(ctx, bucket)
+			return &PolicyPrinter{Policy: policy}, err
+		},
+		set: func(ctx context.Context, c *s3.Client, bucket string, document []byte, dryRun bool) error {
+			policy := &s3.Policy{}
+			if err := decodeDocument(document, policy); err != nil {
+				return err
+			}
+			if err := policy.Validate(bucket); err != nil || dryRun {
+				return err
+			}
+			return c.PutBucketPolicy(ctx, bucket, policy)
+		},
+		del: func(ctx context.Context, c *s3.Client, bucket string) error {
+			return c.DeleteBucketPolicy(ctx, bucket)
+		},
+	},
+}
+
+// readDocument reads a document from a file, or from stdin when the path is
+// a dash
+func readDocument(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(filepath.Clean(path))
+}
+
+// decodeDocument decodes a JSON or YAML document, rejecting unknown fields so
+// that misspelled settings are not silently dropped
+func decodeDocument(document []byte, out any) error {
+	trimmed := bytes.TrimSpace(document)
+	if len(trimmed) == 0 {
+		return errors.New("the document is empty")
+	}
+
+	if trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(out); err != nil {
+			return fmt.Errorf("error decoding JSON document : %v", err)
+		}
+		return nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(trimmed))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("error decoding YAML document : %v", err)
+	}
+
+	return nil
+}
+
+// bucketName returns the bucket of a bucket name or s3://bucket URI
+func bucketName(arg string) (string, error) {
+	if s3.IsURI(arg) {
+		return parseBucket(arg)
+	}
+
+	if arg == "" {
+		return "", errors.New("please provide a bucket")
+	}
+
+	return arg, nil
+}
+
+// ======================================
+
+// newBucketCmd returns the object-storage bucket command group
+func (o *options) newBucketCmd() *cobra.Command {
+	bucket := &cobra.Command{
+		Use:   "bucket",
+		Short: "Commands to manage the configuration of object storage buckets",
+		Long:  bucketLong,
+	}
+
+	for i := range bucketConfigs {
+		bucket.AddCommand(o.newBucketConfigCmd(&bucketConfigs[i]))
+	}
+
+	return bucket
+}
+
+// newBucketConfigCmd returns the get, set and delete commands of a bucket
+// configuration
+func (o *options) newBucketConfigCmd(config *bucketConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     config.Name,
+		Short:   config.Short,
+		Long:    config.Long,
+		Example: config.Example,
+	}
+
+	args := func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("please provide an object storage ID and a bucket")
+		}
+		return nil
+	}
+
+	// Get
+	get := &cobra.Command{
+		Use:   "get <Object Storage ID> <Bucket>",
+		Short: fmt.Sprintf("Retrieve the %s configuration of a bucket", config.Name),
+		Args:  args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, bucket, err := o.bucketClient(cmd, args[1])
+			if err != nil {
+				return err
+			}
+
+			data, err := config.get(o.Base.Context, client, bucket)
+			if err != nil {
+				return fmt.Errorf("error retrieving bucket %s configuration : %v", config.Name, err)
+			}
+
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(get)
+
+	// Set
+	set := &cobra.Command{
+		Use:   "set <Object Storage ID> <Bucket>",
+		Short: fmt.Sprintf("Replace the %s configuration of a bucket", config.Name),
+		Args:  args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, errFi := cmd.Flags().GetString("file")
+			if errFi != nil {
+				return fmt.Errorf("error parsing flag 'file' for object storage bucket %s set : %v", config.Name, errFi)
+			}
+
+			dryRun, errDr := cmd.Flags().GetBool("dry-run")
+			if errDr != nil {
+				return fmt.Errorf("error parsing flag 'dry-run' for object storage bucket %s set : %v", config.Name, errDr)
+			}
+
+			document, err := readDocument(file)
+			if err != nil {
+				return fmt.Errorf("error reading %s document : %v", config.Name, err)
+			}
+
+			// dry runs only validate the document, so no client is needed
+			var client *s3.Client
+			bucket, err := bucketName(args[1])
+			if !dryRun {
+				client, bucket, err = o.bucketClient(cmd, args[1])
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := config.set(o.Base.Context, client, bucket, document, dryRun); err != nil {
+				return fmt.Errorf("error setting bucket %s configuration : %v", config.Name, err)
+			}
+
+			msg := fmt.Sprintf("%s configuration of bucket %s has been set", config.Name, bucket)
+			if dryRun {
+				msg = fmt.Sprintf("%s configuration for bucket %s is valid", config.Name, bucket)
+			}
+			o.Base.Printer.Display(printer.Info(msg), nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(set)
+	set.Flags().StringP("file", "f", "", "JSON or YAML document to apply, or - to read it from stdin")
+	set.Flags().Bool("dry-run", false, "(optional) validate the document without applying it")
+	if err := set.MarkFlagRequired("file"); err != nil {
+		printer.Error(fmt.Errorf("error marking object storage bucket %s set 'file' flag required : %v", config.Name, err))
+		os.Exit(1)
+	}
+
+	// Delete
+	del := &cobra.Command{
+		Use:     "delete <Object Storage ID> <Bucket>",
+		Short:   fmt.Sprintf("Remove the %s configuration of a bucket", config.Name),
+		Aliases: []string{"destroy"},
+		Args:    args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, bucket, err := o.bucketClient(cmd, args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := config.del(o.Base.Context, client, bucket); err != nil {
+				return fmt.Errorf("error deleting bucket %s configuration : %v", config.Name, err)
+			}
+
+			o.Base.Printer.Display(
+				printer.Info(fmt.Sprintf("%s configuration of bucket %s has been deleted", config.Name, bucket)),
+				nil,
+			)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(del)
+
+	cmd.AddCommand(get, set, del)
+
+	return cmd
+}
+
+// bucketClient returns the S3 client of the object storage and the bucket
+// named by the argument
+func (o *options) bucketClient(cmd *cobra.Command, arg string) (*s3.Client, string, error) {
+	bucket, err := bucketName(arg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts, err := transferFlags(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := o.s3Client(opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return client, bucket, nil
+}
@@ -0,0 +1,553 @@
+package objectstorage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
+)
+
+const (
+	credentialsFormatAWS       = "aws"
+	credentialsFormatRclone    = "rclone"
+	credentialsFormatS3cmd     = "s3cmd"
+	credentialsFormatEnv       = "env"
+	credentialsFormatTerraform = "terraform"
+
+	credentialsDefaultProfile = "default"
+	credentialsFilePerms      = 0600
+	credentialsDirPerms       = 0700
+
+	configCreated = "created"
+	configUpdated = "updated"
+)
+
+// credentialsFormats are the supported output formats of object-storage
+// credentials
+var credentialsFormats = []string{
+	credentialsFormatAWS,
+	credentialsFormatRclone,
+	credentialsFormatS3cmd,
+	credentialsFormatEnv,
+	credentialsFormatTerraform,
+}
+
+// iniKeyNames are the access and secret key names of the formats which are
+// written as INI files
+var iniKeyNames = map[string][2]string{
+	credentialsFormatAWS:    {"aws_access_key_id", "aws_secret_access_key"},
+	credentialsFormatRclone: {"access_key_id", "secret_access_key"},
+	credentialsFormatS3cmd:  {"access_key", "secret_key"},
+}
+
+// ConfigUpdate records a configuration section written to a file
+type ConfigUpdate struct {
+	File    string `json:"file"`
+	Section string `json:"section"`
+	Result  string `json:"result"`
+}
+
+// iniKey is a single key and value of an INI section
+type iniKey struct {
+	Name  string
+	Value string
+}
+
+// configSection is a section of an INI configuration file
+type configSection struct {
+	Path    string
+	Section string
+	Keys    []iniKey
+}
+
+// validateCredentialsFormat checks that the format is supported
+func validateCredentialsFormat(format string) error {
+	if !slices.Contains(credentialsFormats, format) {
+		return fmt.Errorf(
+			"invalid format %q. Supported formats : %s",
+			format,
+			strings.Join(credentialsFormats, ", "),
+		)
+	}
+
+	return nil
+}
+
+// defaultConfigPath returns the configuration file read by the tool, taking
+// the environment overrides of each tool into account
+func defaultConfigPath(name string) (string, error) {
+	envs := map[string]string{
+		"aws-credentials": "AWS_SHARED_CREDENTIALS_FILE",
+		"aws-config":      "AWS_CONFIG_FILE",
+		"rclone":          "RCLONE_CONFIG",
+	}
+
+	if env, ok := envs[name]; ok && os.Getenv(env) != "" {
+		return os.Getenv(env), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the home directory : %v", err)
+	}
+
+	switch name {
+	case "aws-credentials":
+		return filepath.Join(home, ".aws", "credentials"), nil
+	case "aws-config":
+		return filepath.Join(home, ".aws", "config"), nil
+	case "rclone":
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return filepath.Join(dir, "rclone", "rclone.conf"), nil
+		}
+		return filepath.Join(home, ".config", "rclone", "rclone.conf"), nil
+	default:
+		return filepath.Join(home, ".s3cfg"), nil
+	}
+}
+
+// credentialSections returns the INI sections holding the keys in the format.
+// The AWS configuration is split between the credentials and the config
+// files; when a file is given, only the credentials are written to it
+func credentialSections(format, profile, file string, keys *govultr.S3Keys) ([]configSection, error) {
+	endpoint := "https://" + keys.S3Hostname
+	names := iniKeyNames[format]
+	secrets := []iniKey{{names[0], keys.S3AccessKey}, {names[1], keys.S3SecretKey}}
+
+	pathFor := func(name string) (string, error) {
+		if file != "" {
+			return file, nil
+		}
+		return defaultConfigPath(name)
+	}
+
+	switch format {
+	case credentialsFormatAWS:
+		credentials, err := pathFor("aws-credentials")
+		if err != nil {
+			return nil, err
+		}
+
+		sections := []configSection{{Path: credentials, Section: profile, Keys: secrets}}
+		if file != "" {
+			return sections, nil
+		}
+
+		config, err := defaultConfigPath("aws-config")
+		if err != nil {
+			return nil, err
+		}
+
+		configProfile := profile
+		if profile != credentialsDefaultProfile {
+			configProfile = "profile " + profile
+		}
+
+		return append(sections, configSection{Path: config, Section: configProfile, Keys: []iniKey{
+			{"region", s3.DefaultRegion},
+			{"endpoint_url", endpoint},
+		}}), nil
+	case credentialsFormatRclone:
+		path, err := pathFor("rclone")
+		if err != nil {
+			return nil, err
+		}
+
+		fields := append([]iniKey{{"type", "s3"}, {"provider", "Other"}}, secrets...)
+		fields = append(fields, iniKey{"endpoint", endpoint}, iniKey{"acl", "private"})
+
+		return []configSection{{Path: path, Section: profile, Keys: fields}}, nil
+	case credentialsFormatS3cmd:
+		path, err := pathFor("s3cmd")
+		if err != nil {
+			return nil, err
+		}
+
+		// s3cmd only reads the default section, so profiles are separate files
+		// passed to s3cmd with -c
+		if file == "" && profile != credentialsDefaultProfile {
+			path += "-" + profile
+		}
+
+		fields := append(secrets,
+			iniKey{"host_base", keys.S3Hostname},
+			iniKey{"host_bucket", keys.S3Hostname},
+			iniKey{"use_https", "True"},
+		)
+
+		return []configSection{{Path: path, Section: credentialsDefaultProfile, Keys: fields}}, nil
+	}
+
+	return nil, fmt.Errorf("%s credentials are not written as configuration files", format)
+}
+
+// renderSections renders sections for display, noting the file of each
+func renderSections(sections []configSection) []byte {
+	var out bytes.Buffer
+	for i, s := range sections {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "# %s\n[%s]\n", s.Path, s.Section)
+		for _, k := range s.Keys {
+			fmt.Fprintf(&out, "%s = %s\n", k.Name, k.Value)
+		}
+	}
+
+	return out.Bytes()
+}
+
+// renderEnv renders the keys as shell exports understood by AWS SDKs
+func renderEnv(keys *govultr.S3Keys) []byte {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "export AWS_ACCESS_KEY_ID=%s\n", keys.S3AccessKey)
+	fmt.Fprintf(&out, "export AWS_SECRET_ACCESS_KEY=%s\n", keys.S3SecretKey)
+	fmt.Fprintf(&out, "export AWS_ENDPOINT_URL=https://%s\n", keys.S3Hostname)
+	fmt.Fprintf(&out, "export AWS_REGION=%s\n", s3.DefaultRegion)
+
+	return out.Bytes()
+}
+
+// renderTerraform renders an AWS provider block using the object storage as
+// its S3 endpoint. Profiles other than the default become provider aliases
+func renderTerraform(profile string, keys *govultr.S3Keys) []byte {
+	var out bytes.Buffer
+	out.WriteString("provider \"aws\" {\n")
+	if profile != credentialsDefaultProfile {
+		fmt.Fprintf(&out, "  alias                       = %s\n", strconv.Quote(profile))
+	}
+	fmt.Fprintf(&out, "  region                      = %s\n", strconv.Quote(s3.DefaultRegion))
+	fmt.Fprintf(&out, "  access_key                  = %s\n", strconv.Quote(keys.S3AccessKey))
+	fmt.Fprintf(&out, "  secret_key                  = %s\n", strconv.Quote(keys.S3SecretKey))
+	out.WriteString("  s3_use_path_style           = true\n")
+	out.WriteString("  skip_credentials_validation = true\n")
+	out.WriteString("  skip_region_validation      = true\n")
+	out.WriteString("  skip_requesting_account_id  = true\n")
+	out.WriteString("\n  endpoints {\n")
+	fmt.Fprintf(&out, "    s3 = %s\n", strconv.Quote("https://"+keys.S3Hostname))
+	out.WriteString("  }\n}\n")
+
+	return out.Bytes()
+}
+
+// parseINILine returns the section of a header line, or the key of a key and
+// value line
+func parseINILine(line string) (section, key string, isSection bool) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+		return strings.TrimSpace(trimmed[1 : len(trimmed)-1]), "", true
+	}
+
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+		return "", "", false
+	}
+
+	key, _, _ = strings.Cut(trimmed, "=")
+	return "", strings.TrimSpace(key), false
+}
+
+// mergeINI sets the keys of the first occurrence of a section, adding the
+// section when it does not exist. Other keys of the section, other sections
+// and comments are kept
+func mergeINI(existing []byte, section string, keys []iniKey) ([]byte, bool) {
+	values := make(map[string]string, len(keys))
+	for _, k := range keys {
+		values[k.Name] = k.Value
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	written := map[string]bool{}
+	found, inSection := false, false
+	var out []string
+
+	// appendMissing adds the keys not yet written before any blank lines
+	// which end the section
+	appendMissing := func() {
+		end := len(out)
+		for end > 0 && strings.TrimSpace(out[end-1]) == "" {
+			end--
+		}
+
+		var missing []string
+		for _, k := range keys {
+			if !written[k.Name] {
+				missing = append(missing, k.Name+" = "+k.Value)
+			}
+		}
+
+		out = append(out[:end], append(missing, out[end:]...)...)
+	}
+
+	for _, line := range lines {
+		name, key, isSection := parseINILine(line)
+		if isSection {
+			if inSection {
+				appendMissing()
+			}
+			// repeated headers of the section are left as they are
+			inSection = name == section && !found
+			found = found || inSection
+		} else if value, ok := values[key]; inSection && ok {
+			out = append(out, key+" = "+value)
+			written[key] = true
+			continue
+		}
+
+		out = append(out, line)
+	}
+
+	if inSection {
+		appendMissing()
+	}
+
+	if !found {
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, "["+section+"]")
+		for _, k := range keys {
+			out = append(out, k.Name+" = "+k.Value)
+		}
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n"), !found
+}
+
+// sectionsWithValue returns the sections in which the key has the value
+func sectionsWithValue(content []byte, key, value string) []string {
+	var sections []string
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		name, k, isSection := parseINILine(scanner.Text())
+		if isSection {
+			section = name
+			continue
+		}
+
+		_, v, _ := strings.Cut(scanner.Text(), "=")
+		if k == key && strings.TrimSpace(v) == value && !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+// readConfigFile returns the content of a configuration file, which is empty
+// when the file does not exist
+func readConfigFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return content, err
+}
+
+// writeConfigFile writes a configuration file holding credentials, creating
+// its directory and keeping the permissions of an existing file
+func writeConfigFile(path string, content []byte) error {
+	path = filepath.Clean(path)
+	if err := os.MkdirAll(filepath.Dir(path), credentialsDirPerms); err != nil {
+		return err
+	}
+
+	perms := os.FileMode(credentialsFilePerms)
+	if info, err := os.Stat(path); err == nil {
+		perms = info.Mode().Perm()
+	}
+
+	return os.WriteFile(path, content, perms)
+}
+
+// writeSections merges the sections into their files
+func writeSections(sections []configSection) ([]ConfigUpdate, error) {
+	updates := []ConfigUpdate{}
+	for _, s := range sections {
+		existing, err := readConfigFile(s.Path)
+		if err != nil {
+			return updates, err
+		}
+
+		content, created := mergeINI(existing, s.Section, s.Keys)
+		if err := writeConfigFile(s.Path, content); err != nil {
+			return updates, err
+		}
+
+		result := configUpdated
+		if created {
+			result = configCreated
+		}
+		updates = append(updates, ConfigUpdate{File: s.Path, Section: s.Section, Result: result})
+	}
+
+	return updates, nil
+}
+
+// replaceKeys replaces the keys in every section of the AWS, rclone and
+// s3cmd configuration files which holds the old access key
+func replaceKeys(oldAccessKey string, keys *govultr.S3Keys) ([]ConfigUpdate, error) {
+	updates := []ConfigUpdate{}
+	for _, format := range []string{credentialsFormatAWS, credentialsFormatRclone, credentialsFormatS3cmd} {
+		name := format
+		if format == credentialsFormatAWS {
+			name = "aws-credentials"
+		}
+
+		path, err := defaultConfigPath(name)
+		if err != nil {
+			return updates, err
+		}
+
+		paths := []string{path}
+		if format == credentialsFormatS3cmd {
+			profiles, _ := filepath.Glob(path + "-*")
+			paths = append(paths, profiles...)
+		}
+
+		for _, path := range paths {
+			fileUpdates, err := replaceFileKeys(path, iniKeyNames[format], oldAccessKey, keys)
+			updates = append(updates, fileUpdates...)
+			if err != nil {
+				return updates, err
+			}
+		}
+	}
+
+	return updates, nil
+}
+
+// replaceFileKeys replaces the keys in every section of a configuration file
+// which holds the old access key
+func replaceFileKeys(path string, names [2]string, oldAccessKey string, keys *govultr.S3Keys) ([]ConfigUpdate, error) {
+	existing, err := readConfigFile(path)
+	if err != nil || existing == nil {
+		return nil, nil
+	}
+
+	updates := []ConfigUpdate{}
+	content := existing
+	sections := sectionsWithValue(existing, names[0], oldAccessKey)
+	for _, section := range sections {
+		content, _ = mergeINI(content, section, []iniKey{
+			{names[0], keys.S3AccessKey},
+			{names[1], keys.S3SecretKey},
+		})
+		updates = append(updates, ConfigUpdate{File: path, Section: section, Result: configUpdated})
+	}
+
+	if len(sections) > 0 {
+		if err := writeConfigFile(path, content); err != nil {
+			return updates, err
+		}
+	}
+
+	return updates, nil
+}
+
+// ======================================
+
+// credentialsOptions holds the flags of object-storage credentials
+type credentialsOptions struct {
+	Format  string
+	Profile string
+	File    string
+	Write   bool
+}
+
+// credentials prints the keys of the object storage in the format or, with
+// write, merges them into the configuration files of the format. Environment
+// and Terraform output replaces the file it is written to
+func (o *options) credentials(opts *credentialsOptions) ([]ConfigUpdate, error) {
+	storage, err := o.get()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving object storage : %v", err)
+	}
+
+	keys := &storage.S3Keys
+
+	var content []byte
+	switch opts.Format {
+	case credentialsFormatEnv:
+		content = renderEnv(keys)
+	case credentialsFormatTerraform:
+		content = renderTerraform(opts.Profile, keys)
+	default:
+		sections, err := credentialSections(opts.Format, opts.Profile, opts.File, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.Write {
+			return writeSections(sections)
+		}
+		content = renderSections(sections)
+	}
+
+	if !opts.Write {
+		_, err := os.Stdout.Write(content)
+		return nil, err
+	}
+
+	if opts.File == "" {
+		return nil, fmt.Errorf("please provide a --file to write %s credentials to", opts.Format)
+	}
+
+	result := configCreated
+	if _, err := os.Stat(opts.File); err == nil {
+		result = configUpdated
+	}
+
+	if err := writeConfigFile(opts.File, content); err != nil {
+		return nil, err
+	}
+
+	return []ConfigUpdate{{File: opts.File, Section: opts.Format, Result: result}}, nil
+}
+
+// credentialsFlags reads the flags of object-storage credentials. Passing a
+// file implies writing to it
+func credentialsFlags(cmd *cobra.Command) (*credentialsOptions, error) {
+	opts := &credentialsOptions{}
+
+	var err error
+	if opts.Format, err = cmd.Flags().GetString("format"); err != nil {
+		return nil, fmt.Errorf("error parsing flag 'format' for object storage credentials : %v", err)
+	}
+
+	if opts.Profile, err = cmd.Flags().GetString("profile"); err != nil {
+		return nil, fmt.Errorf("error parsing flag 'profile' for object storage credentials : %v", err)
+	}
+
+	if opts.File, err = cmd.Flags().GetString("file"); err != nil {
+		return nil, fmt.Errorf("error parsing flag 'file' for object storage credentials : %v", err)
+	}
+
+	if opts.Write, err = cmd.Flags().GetBool("write"); err != nil {
+		return nil, fmt.Errorf("error parsing flag 'write' for object storage credentials : %v", err)
+	}
+
+	if err := validateCredentialsFormat(opts.Format); err != nil {
+		return nil, err
+	}
+
+	opts.Write = opts.Write || opts.File != ""
+
+	return opts, nil
+}
@@ -0,0 +1,19 @@
+package objectstorage
+
+import "testing"
+
+func TestMergeINIRepeatedSection(t *testing.T) {
+	existing := "[vultr]\naws_access_key_id = old\n\n[other]\nkey = value\n\n[vultr]\naws_access_key_id = stale\n"
+	keys := []iniKey{{"aws_access_key_id", "new"}, {"aws_secret_access_key", "secret"}}
+
+	got, added := mergeINI([]byte(existing), "vultr", keys)
+	if added {
+		t.Error("mergeINI added a section which exists")
+	}
+
+	want := "[vultr]\naws_access_key_id = new\naws_secret_access_key = secret\n\n[other]\nkey = value\n\n" +
+		"[vultr]\naws_access_key_id = stale\n"
+	if string(got) != want {
+		t.Errorf("mergeINI =\n%s\nwant\n%s", got, want)
+	}
+}
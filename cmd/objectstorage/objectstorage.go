@@ -4,13 +4,17 @@ package objectstorage
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
 	"github.com/vultr/vultr-cli/v3/cmd/printer"
 	"github.com/vultr/vultr-cli/v3/cmd/utils"
 	"github.com/vultr/vultr-cli/v3/pkg/cli"
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
 )
 
 var (
@@ -56,6 +60,39 @@ var (
 	regenerateKeysExample = `
 	# Full example
 	vultr-cli object-storage regenerate-keys 57ad432f-66a2-4580-936b-d0af934bce5d
+
+	# Replace the old keys wherever credentials exported them
+	vultr-cli object-storage regenerate-keys 57ad432f-66a2-4580-936b-d0af934bce5d --update-config
+	`
+
+	credentialsLong = `Export the S3 credentials of an object storage for S3 tools
+
+Formats :
+  aws        profile sections of ~/.aws/credentials and ~/.aws/config
+  rclone     a remote in rclone.conf
+  s3cmd      the default section of ~/.s3cfg, or of ~/.s3cfg-<profile> for other profiles
+  env        shell exports of the AWS environment variables
+  terraform  an AWS provider block using the object storage as its S3 endpoint
+
+The credentials are printed unless --write or --file is passed. Writing merges
+the profile into the existing files of the aws, rclone and s3cmd formats,
+keeping all other entries. With --file, only the aws credentials are written to
+the file, leaving the region and endpoint out of ~/.aws/config. The env and
+terraform formats replace the file they are written to. JSON and YAML output
+list the written files, so they require --write or --file.
+`
+	credentialsExample = `
+	# Print an AWS credentials profile
+	vultr-cli object-storage credentials 57ad432f-66a2-4580-936b-d0af934bce5d --profile vultr
+
+	# Add a remote to rclone.conf
+	vultr-cli object-storage credentials 57ad432f-66a2-4580-936b-d0af934bce5d --format rclone --profile vultr --write
+
+	# Write an s3cmd profile to ~/.s3cfg-staging, used with s3cmd -c ~/.s3cfg-staging
+	vultr-cli object-storage credentials 57ad432f-66a2-4580-936b-d0af934bce5d --format s3cmd --profile staging --write
+
+	# Load the credentials into the environment
+	eval "$(vultr-cli object-storage credentials 57ad432f-66a2-4580-936b-d0af934bce5d --format env)"
 	`
 
 	clusterListExample = `
@@ -67,6 +104,138 @@ var (
 	# Full example
 	vultr-cli object-storage cluster tiers --cluster-id=2
 	`
+
+	lsLong = `List the buckets of an object storage, or the objects and prefixes in a bucket
+
+The S3 endpoint and credentials are taken from the object storage subscription.
+Pass --endpoint to use a different S3 compatible endpoint with the same
+credentials.
+`
+	lsExample = `
+	# List buckets
+	vultr-cli object-storage ls 57ad432f-66a2-4580-936b-d0af934bce5d
+
+	# List objects and prefixes below a prefix
+	vultr-cli object-storage ls 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/logs/
+
+	# List every object in a bucket
+	vultr-cli object-storage ls 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket --recursive
+	`
+
+	cpLong = `Copy files to, from or within an object storage
+
+Either the source or the destination must be an s3://bucket/key URI. Files
+larger than the part size are sent as multipart uploads, and with --recursive
+up to --concurrency files are transferred at once.
+`
+	cpExample = `
+	# Upload a file
+	vultr-cli object-storage cp 57ad432f-66a2-4580-936b-d0af934bce5d backup.tar.gz s3://my-bucket/backups/
+
+	# Download a prefix
+	vultr-cli object-storage cp 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/backups ./backups --recursive
+	`
+
+	mvLong = `Move files to, from or within an object storage
+
+Sources are removed once they have been copied. Either the source or the
+destination must be an s3://bucket/key URI.
+`
+	mvExample = `
+	# Archive an object
+	vultr-cli object-storage mv 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/report.csv s3://my-bucket/archive/
+	`
+
+	rmLong    = `Remove an object, or every object below a prefix with --recursive`
+	rmExample = `
+	# Remove an object
+	vultr-cli object-storage rm 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/report.csv
+
+	# Remove a prefix
+	vultr-cli object-storage rm 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/tmp/ --recursive
+	`
+
+	syncLong = `Synchronize a directory or prefix to another
+
+Files are copied when missing from the destination, when their sizes differ or
+when the source is newer. With --delete, files only found in the destination
+are removed. Use --dry-run to list the changes without making them.
+`
+	syncExample = `
+	# Synchronize a local directory to a bucket
+	vultr-cli object-storage sync 57ad432f-66a2-4580-936b-d0af934bce5d ./site s3://my-bucket/site --delete
+	`
+
+	presignLong = `Create a URL which allows an object to be downloaded, or uploaded with --method PUT,
+without credentials, until it expires`
+	presignExample = `
+	# Share an object for one hour
+	vultr-cli object-storage presign 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket/report.csv --expires 3600
+	`
+
+	mbLong    = `Create a bucket in an object storage`
+	mbExample = `
+	# Full example
+	vultr-cli object-storage mb 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket
+	`
+
+	rbLong    = `Remove a bucket from an object storage. Buckets must be empty unless --force is passed`
+	rbExample = `
+	# Remove a bucket and every object in it
+	vultr-cli object-storage rb 57ad432f-66a2-4580-936b-d0af934bce5d s3://my-bucket --force
+	`
+
+	bucketLong = `Manage the lifecycle, CORS and policy configuration of object storage buckets
+
+Configurations are read from JSON or YAML documents using the field names of the
+S3 API and validated before they are sent. The JSON and YAML output of get can be
+passed back to set.
+`
+
+	bucketLifecycleLong = `Manage the lifecycle expiry rules of a bucket
+
+Each rule needs a status of Enabled or Disabled and at least one of Expiration,
+NoncurrentVersionExpiration or AbortIncompleteMultipartUpload. Expiration takes
+exactly one of Days, Date (midnight UTC) or ExpiredObjectDeleteMarker.
+`
+	bucketLifecycleExample = `
+	# Expire logs after 30 days, from a YAML document like
+	#   Rules:
+	#     - ID: expire-logs
+	#       Status: Enabled
+	#       Filter:
+	#         Prefix: logs/
+	#       Expiration:
+	#         Days: 30
+	vultr-cli object-storage bucket lifecycle set 57ad432f-66a2-4580-936b-d0af934bce5d my-bucket --file lifecycle.yaml
+
+	# Show the rules
+	vultr-cli object-storage bucket lifecycle get 57ad432f-66a2-4580-936b-d0af934bce5d my-bucket
+	`
+
+	bucketCORSLong = `Manage the CORS rules of a bucket
+
+Each rule needs AllowedOrigins and AllowedMethods, which may be GET, PUT, POST,
+DELETE or HEAD. AllowedHeaders, ExposeHeaders and MaxAgeSeconds are optional.
+`
+	bucketCORSExample = `
+	# Allow browser uploads, from a JSON document like
+	#   {"CORSRules": [{"AllowedOrigins": ["https://example.com"], "AllowedMethods": ["GET", "PUT"]}]}
+	vultr-cli object-storage bucket cors set 57ad432f-66a2-4580-936b-d0af934bce5d my-bucket --file cors.json
+	`
+
+	bucketPolicyLong = `Manage the policy of a bucket
+
+Policies use the 2012-10-17 policy language. Every statement needs an Effect,
+a Principal, s3: actions and resources within the bucket.
+`
+	bucketPolicyExample = `
+	# Check a policy without applying it
+	vultr-cli object-storage bucket policy set 57ad432f-66a2-4580-936b-d0af934bce5d my-bucket --file policy.json --dry-run
+
+	# Remove the policy
+	vultr-cli object-storage bucket policy delete 57ad432f-66a2-4580-936b-d0af934bce5d my-bucket
+	`
 )
 
 // NewCmdObjectStorage provides the CLI command for object storage functions
@@ -272,18 +441,111 @@ func NewCmdObjectStorage(base *cli.Base) *cobra.Command { //nolint:gocyclo
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			updateConfig, errUp := cmd.Flags().GetBool("update-config")
+			if errUp != nil {
+				return fmt.Errorf("error parsing flag 'update-config' for object storage regenerate-keys : %v", errUp)
+			}
+
+			var oldAccessKey string
+			if updateConfig {
+				storage, errGe := o.get()
+				if errGe != nil {
+					return fmt.Errorf("error retrieving object storage : %v", errGe)
+				}
+				oldAccessKey = storage.S3AccessKey
+			}
+
 			key, err := o.regenerateKeys()
 			if err != nil {
 				return fmt.Errorf("unable to regenerate keys for object storage : %v", err)
 			}
 
 			data := &ObjectStorageKeysPrinter{Keys: key}
+			if !updateConfig {
+				o.Base.Printer.Display(data, nil)
+				return nil
+			}
+
+			data.ConfigUpdates, err = replaceKeys(oldAccessKey, key)
+			if err != nil {
+				err = fmt.Errorf("keys regenerated but updating configuration files failed : %v", err)
+			}
+
+			if !o.Base.Printer.IsText() {
+				return o.Base.Printer.DisplayWithError(data, err)
+			}
+
 			o.Base.Printer.Display(data, nil)
+			return o.Base.Printer.DisplayWithError(&ConfigUpdatesPrinter{Updates: data.ConfigUpdates}, err)
+		},
+	}
+
+	regenerateKeys.Flags().Bool(
+		"update-config",
+		false,
+		"(optional) replace the old keys in AWS, rclone and s3cmd configuration files with the new keys",
+	)
+
+	// Credentials
+	credentials := &cobra.Command{
+		Use:     "credentials <Object Storage ID>",
+		Short:   "Export the S3 credentials of an object storage for S3 tools",
+		Long:    credentialsLong,
+		Example: credentialsExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide an object storage ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := credentialsFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			// printed credentials are the configuration file content itself
+			if !opts.Write && !o.Base.Printer.IsText() {
+				return errors.New("printed credentials are only supported with text output, use --write or --file")
+			}
+
+			updates, err := o.credentials(opts)
+			if err != nil {
+				return fmt.Errorf("error exporting object storage credentials : %v", err)
+			}
+
+			if opts.Write {
+				o.Base.Printer.Display(&ConfigUpdatesPrinter{Updates: updates}, nil)
+			}
 
 			return nil
 		},
 	}
 
+	credentials.Flags().StringP(
+		"format",
+		"f",
+		credentialsFormatAWS,
+		fmt.Sprintf("(optional) credentials format. One of : %s", strings.Join(credentialsFormats, ", ")),
+	)
+	credentials.Flags().StringP(
+		"profile",
+		"p",
+		credentialsDefaultProfile,
+		"(optional) profile, rclone remote or Terraform provider alias to write the credentials as",
+	)
+	credentials.Flags().BoolP(
+		"write",
+		"w",
+		false,
+		"(optional) merge the credentials into the configuration files of the format rather than printing them",
+	)
+	credentials.Flags().String(
+		"file",
+		"",
+		"(optional) file to write the credentials to instead of the default for the format",
+	)
+
 	// Cluster
 	cluster := &cobra.Command{
 		Use:   "cluster",
@@ -378,6 +640,343 @@ func NewCmdObjectStorage(base *cli.Base) *cobra.Command { //nolint:gocyclo
 		tierList,
 	)
 
+	// List Objects
+	ls := &cobra.Command{
+		Use:     "ls <Object Storage ID> [s3://<Bucket>[/<Prefix>]]",
+		Short:   "List buckets or objects in an object storage",
+		Long:    lsLong,
+		Example: lsExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("please provide an object storage ID")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			if len(args) < 2 {
+				buckets, errLi := client.ListBuckets(o.Base.Context)
+				if errLi != nil {
+					return fmt.Errorf("error listing buckets : %v", errLi)
+				}
+
+				o.Base.Printer.Display(&BucketsPrinter{Buckets: buckets}, nil)
+				return nil
+			}
+
+			loc, errLo := parseLocation(args[1])
+			if errLo != nil || !loc.remote() {
+				return fmt.Errorf("invalid bucket URI %q, expected s3://bucket/prefix", args[1])
+			}
+
+			delimiter := "/"
+			if opts.Recursive {
+				delimiter = ""
+			}
+
+			listing, err := client.ListObjects(o.Base.Context, loc.Bucket, loc.Key, delimiter)
+			if err != nil {
+				return fmt.Errorf("error listing objects : %v", err)
+			}
+
+			o.Base.Printer.Display(&ObjectsPrinter{Prefixes: listing.Prefixes, Objects: listing.Objects}, nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(ls)
+	ls.Flags().BoolP("recursive", "r", false, "(optional) list every object below the prefix rather than one level")
+
+	// Copy
+	cp := &cobra.Command{
+		Use:     "cp <Object Storage ID> <Source> <Destination>",
+		Short:   "Copy files to, from or within an object storage",
+		Long:    cpLong,
+		Example: cpExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 3 {
+				return errors.New("please provide an object storage ID, a source and a destination")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.copyObjects(cmd, false)
+		},
+	}
+
+	addTransferFlags(cp)
+	cp.Flags().BoolP("recursive", "r", false, "(optional) copy every file below a directory or prefix")
+
+	// Move
+	mv := &cobra.Command{
+		Use:     "mv <Object Storage ID> <Source> <Destination>",
+		Short:   "Move files to, from or within an object storage",
+		Long:    mvLong,
+		Example: mvExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 3 {
+				return errors.New("please provide an object storage ID, a source and a destination")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.copyObjects(cmd, true)
+		},
+	}
+
+	addTransferFlags(mv)
+	mv.Flags().BoolP("recursive", "r", false, "(optional) move every file below a directory or prefix")
+
+	// Remove
+	rm := &cobra.Command{
+		Use:     "rm <Object Storage ID> s3://<Bucket>/<Key>",
+		Short:   "Remove objects from an object storage",
+		Long:    rmLong,
+		Example: rmExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("please provide an object storage ID and an object URI")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			loc, errLo := parseLocation(args[1])
+			if errLo != nil {
+				return errLo
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			transfers, err := planRemove(o.Base.Context, client, loc, opts.Recursive)
+			if err != nil {
+				return fmt.Errorf("error planning object removal : %v", err)
+			}
+
+			err = o.transfer(client, transfers, opts, false)
+			return o.Base.Printer.DisplayWithError(&TransfersPrinter{Transfers: transfers}, err)
+		},
+	}
+
+	addTransferFlags(rm)
+	rm.Flags().BoolP("recursive", "r", false, "(optional) remove every object below the prefix")
+
+	// Sync
+	syncCmd := &cobra.Command{
+		Use:     "sync <Object Storage ID> <Source> <Destination>",
+		Short:   "Synchronize a directory or prefix to another",
+		Long:    syncLong,
+		Example: syncExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 3 {
+				return errors.New("please provide an object storage ID, a source and a destination")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			src, dst, errLo := parseLocations(args[1], args[2])
+			if errLo != nil {
+				return errLo
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			transfers, err := planSync(o.Base.Context, client, src, dst, opts.Delete)
+			if err != nil {
+				return fmt.Errorf("error planning sync : %v", err)
+			}
+
+			err = o.transfer(client, transfers, opts, false)
+			return o.Base.Printer.DisplayWithError(&TransfersPrinter{Transfers: transfers}, err)
+		},
+	}
+
+	addTransferFlags(syncCmd)
+	syncCmd.Flags().Bool("delete", false, "(optional) remove files from the destination which are not in the source")
+
+	// Presign
+	presign := &cobra.Command{
+		Use:     "presign <Object Storage ID> s3://<Bucket>/<Key>",
+		Short:   "Create a presigned URL for an object",
+		Long:    presignLong,
+		Example: presignExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("please provide an object storage ID and an object URI")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			expires, errEx := cmd.Flags().GetInt("expires")
+			if errEx != nil {
+				return fmt.Errorf("error parsing flag 'expires' for object storage presign : %v", errEx)
+			}
+
+			method, errMe := cmd.Flags().GetString("method")
+			if errMe != nil {
+				return fmt.Errorf("error parsing flag 'method' for object storage presign : %v", errMe)
+			}
+
+			method = strings.ToUpper(method)
+			if method != http.MethodGet && method != http.MethodPut {
+				return fmt.Errorf("invalid method %q, expected GET or PUT", method)
+			}
+
+			loc, errLo := parseLocation(args[1])
+			if errLo != nil || !loc.remote() || loc.isDir() {
+				return fmt.Errorf("invalid object URI %q, expected s3://bucket/key", args[1])
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			ttl := time.Duration(expires) * time.Second
+			presigned, err := client.Presign(method, loc.Bucket, loc.Key, ttl)
+			if err != nil {
+				return fmt.Errorf("error presigning object : %v", err)
+			}
+
+			data := &PresignPrinter{URL: presigned, Method: method, Expires: time.Now().Add(ttl).UTC().Format(time.RFC3339)}
+			o.Base.Printer.Display(data, nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(presign)
+	presign.Flags().IntP("expires", "e", presignDefaultExpiry, "(optional) seconds until the URL expires, up to 604800")
+	presign.Flags().StringP("method", "m", http.MethodGet, "(optional) method the URL allows. GET or PUT")
+
+	// Make Bucket
+	mb := &cobra.Command{
+		Use:     "mb <Object Storage ID> s3://<Bucket>",
+		Short:   "Create a bucket",
+		Long:    mbLong,
+		Example: mbExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("please provide an object storage ID and a bucket URI")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			bucket, errBu := parseBucket(args[1])
+			if errBu != nil {
+				return errBu
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			if err := client.CreateBucket(o.Base.Context, bucket); err != nil {
+				return fmt.Errorf("error creating bucket : %v", err)
+			}
+
+			o.Base.Printer.Display(printer.Info(fmt.Sprintf("bucket %s has been created", bucket)), nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(mb)
+
+	// Remove Bucket
+	rb := &cobra.Command{
+		Use:     "rb <Object Storage ID> s3://<Bucket>",
+		Short:   "Remove a bucket",
+		Long:    rbLong,
+		Example: rbExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("please provide an object storage ID and a bucket URI")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, errFl := transferFlags(cmd)
+			if errFl != nil {
+				return errFl
+			}
+
+			force, errFo := cmd.Flags().GetBool("force")
+			if errFo != nil {
+				return fmt.Errorf("error parsing flag 'force' for object storage rb : %v", errFo)
+			}
+
+			bucket, errBu := parseBucket(args[1])
+			if errBu != nil {
+				return errBu
+			}
+
+			client, err := o.s3Client(opts)
+			if err != nil {
+				return err
+			}
+
+			if force {
+				transfers, errPl := planRemove(o.Base.Context, client, location{Bucket: bucket}, true)
+				if errPl != nil {
+					return fmt.Errorf("error listing bucket objects : %v", errPl)
+				}
+
+				if errTr := o.transfer(client, transfers, opts, false); errTr != nil {
+					return fmt.Errorf("error emptying bucket : %v", errTr)
+				}
+			}
+
+			if err := client.DeleteBucket(o.Base.Context, bucket); err != nil {
+				return fmt.Errorf("error removing bucket : %v", err)
+			}
+
+			o.Base.Printer.Display(printer.Info(fmt.Sprintf("bucket %s has been removed", bucket)), nil)
+
+			return nil
+		},
+	}
+
+	addEndpointFlag(rb)
+	rb.Flags().Int("concurrency", s3.DefaultConcurrency, "(optional) number of objects removed at once with --force")
+	rb.Flags().Bool("force", false, "(optional) remove every object in the bucket before removing it")
+
 	cmd.AddCommand(
 		list,
 		get,
@@ -385,8 +984,18 @@ func NewCmdObjectStorage(base *cli.Base) *cobra.Command { //nolint:gocyclo
 		label,
 		del,
 		regenerateKeys,
+		credentials,
 		cluster,
 		tier,
+		ls,
+		cp,
+		mv,
+		rm,
+		syncCmd,
+		presign,
+		mb,
+		rb,
+		o.newBucketCmd(),
 	)
 
 	return cmd
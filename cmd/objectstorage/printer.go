@@ -1,12 +1,16 @@
 package objectstorage
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vultr/govultr/v3"
 	"github.com/vultr/vultr-cli/v3/cmd/printer"
 	"github.com/vultr/vultr-cli/v3/cmd/utils"
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
 )
 
 // ObjectStoragesPrinter ...
@@ -177,7 +181,8 @@ func (o *ObjectStorageClustersPrinter) Paging() [][]string {
 
 // ObjectStorageKeysPrinter ...
 type ObjectStorageKeysPrinter struct {
-	Keys *govultr.S3Keys `json:"s3_credentials"`
+	Keys          *govultr.S3Keys `json:"s3_credentials"`
+	ConfigUpdates []ConfigUpdate  `json:"config_updates,omitempty"`
 }
 
 // JSON ...
@@ -362,3 +367,464 @@ func (o *ObjectStorageClusterTiersPrinter) Data() [][]string {
 func (o *ObjectStorageClusterTiersPrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// BucketsPrinter ...
+type BucketsPrinter struct {
+	Buckets []s3.Bucket `json:"buckets"`
+}
+
+// JSON ...
+func (b *BucketsPrinter) JSON() []byte {
+	return printer.MarshalObject(b, "json")
+}
+
+// YAML ...
+func (b *BucketsPrinter) YAML() []byte {
+	return printer.MarshalObject(b, "yaml")
+}
+
+// Columns ...
+func (b *BucketsPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"NAME",
+		"DATE CREATED",
+	}}
+}
+
+// Data ...
+func (b *BucketsPrinter) Data() [][]string {
+	if len(b.Buckets) == 0 {
+		return [][]string{0: {"---", "---"}}
+	}
+
+	var data [][]string
+	for i := range b.Buckets {
+		data = append(data, []string{
+			b.Buckets[i].Name,
+			b.Buckets[i].CreationDate.Format(time.RFC3339),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (b *BucketsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// ObjectsPrinter ...
+type ObjectsPrinter struct {
+	Prefixes []string    `json:"prefixes"`
+	Objects  []s3.Object `json:"objects"`
+}
+
+// JSON ...
+func (o *ObjectsPrinter) JSON() []byte {
+	return printer.MarshalObject(o, "json")
+}
+
+// YAML ...
+func (o *ObjectsPrinter) YAML() []byte {
+	return printer.MarshalObject(o, "yaml")
+}
+
+// Columns ...
+func (o *ObjectsPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"KEY",
+		"SIZE",
+		"LAST MODIFIED",
+	}}
+}
+
+// Data ...
+func (o *ObjectsPrinter) Data() [][]string {
+	if len(o.Prefixes) == 0 && len(o.Objects) == 0 {
+		return [][]string{0: {"---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range o.Prefixes {
+		data = append(data, []string{
+			o.Prefixes[i],
+			"PREFIX",
+			"",
+		})
+	}
+
+	for i := range o.Objects {
+		data = append(data, []string{
+			o.Objects[i].Key,
+			strconv.FormatInt(o.Objects[i].Size, 10),
+			o.Objects[i].LastModified.Format(time.RFC3339),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (o *ObjectsPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// TransfersPrinter ...
+type TransfersPrinter struct {
+	Transfers []Transfer `json:"transfers"`
+}
+
+// JSON ...
+func (t *TransfersPrinter) JSON() []byte {
+	return printer.MarshalObject(t, "json")
+}
+
+// YAML ...
+func (t *TransfersPrinter) YAML() []byte {
+	return printer.MarshalObject(t, "yaml")
+}
+
+// Columns ...
+func (t *TransfersPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ACTION",
+		"SOURCE",
+		"DESTINATION",
+		"SIZE",
+		"RESULT",
+	}}
+}
+
+// Data ...
+func (t *TransfersPrinter) Data() [][]string {
+	if len(t.Transfers) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range t.Transfers {
+		data = append(data, []string{
+			t.Transfers[i].Action,
+			t.Transfers[i].Source,
+			t.Transfers[i].Destination,
+			strconv.FormatInt(t.Transfers[i].Size, 10),
+			t.Transfers[i].Result,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (t *TransfersPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// PresignPrinter ...
+type PresignPrinter struct {
+	URL     string `json:"url"`
+	Method  string `json:"method"`
+	Expires string `json:"expires"`
+}
+
+// JSON ...
+func (p *PresignPrinter) JSON() []byte {
+	return printer.MarshalObject(p, "json")
+}
+
+// YAML ...
+func (p *PresignPrinter) YAML() []byte {
+	return printer.MarshalObject(p, "yaml")
+}
+
+// Columns ...
+func (p *PresignPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"METHOD",
+		"EXPIRES",
+		"URL",
+	}}
+}
+
+// Data ...
+func (p *PresignPrinter) Data() [][]string {
+	return [][]string{0: {
+		p.Method,
+		p.Expires,
+		p.URL,
+	}}
+}
+
+// Paging ...
+func (p *PresignPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// ConfigUpdatesPrinter ...
+type ConfigUpdatesPrinter struct {
+	Updates []ConfigUpdate `json:"config_updates"`
+}
+
+// JSON ...
+func (c *ConfigUpdatesPrinter) JSON() []byte {
+	return printer.MarshalObject(c, "json")
+}
+
+// YAML ...
+func (c *ConfigUpdatesPrinter) YAML() []byte {
+	return printer.MarshalObject(c, "yaml")
+}
+
+// Columns ...
+func (c *ConfigUpdatesPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"FILE",
+		"SECTION",
+		"RESULT",
+	}}
+}
+
+// Data ...
+func (c *ConfigUpdatesPrinter) Data() [][]string {
+	if len(c.Updates) == 0 {
+		return [][]string{0: {"---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range c.Updates {
+		data = append(data, []string{
+			c.Updates[i].File,
+			c.Updates[i].Section,
+			c.Updates[i].Result,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (c *ConfigUpdatesPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// LifecyclePrinter ...
+type LifecyclePrinter struct {
+	Config *s3.LifecycleConfiguration
+}
+
+// JSON ...
+func (l *LifecyclePrinter) JSON() []byte {
+	return printer.MarshalObject(l.document(), "json")
+}
+
+// YAML ...
+func (l *LifecyclePrinter) YAML() []byte {
+	return printer.MarshalObject(l.document(), "yaml")
+}
+
+func (l *LifecyclePrinter) document() *s3.LifecycleConfiguration {
+	if l.Config == nil {
+		return &s3.LifecycleConfiguration{Rules: []s3.LifecycleRule{}}
+	}
+	return l.Config
+}
+
+// Columns ...
+func (l *LifecyclePrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ID",
+		"STATUS",
+		"PREFIX",
+		"EXPIRATION",
+		"NONCURRENT DAYS",
+		"ABORT UPLOADS DAYS",
+	}}
+}
+
+// Data ...
+func (l *LifecyclePrinter) Data() [][]string {
+	rules := l.document().Rules
+	if len(rules) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range rules {
+		prefix, expiration, noncurrent, abort := "", "", "", ""
+		if rules[i].Filter != nil {
+			prefix = rules[i].Filter.Prefix
+		}
+
+		if e := rules[i].Expiration; e != nil {
+			switch {
+			case e.Days > 0:
+				expiration = fmt.Sprintf("%d days", e.Days)
+			case e.Date != "":
+				expiration = e.Date
+			case e.ExpiredObjectDeleteMarker:
+				expiration = "delete markers"
+			}
+		}
+
+		if n := rules[i].NoncurrentVersionExpiration; n != nil {
+			noncurrent = strconv.Itoa(n.NoncurrentDays)
+		}
+
+		if a := rules[i].AbortIncompleteMultipartUpload; a != nil {
+			abort = strconv.Itoa(a.DaysAfterInitiation)
+		}
+
+		data = append(data, []string{
+			rules[i].ID,
+			rules[i].Status,
+			prefix,
+			expiration,
+			noncurrent,
+			abort,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (l *LifecyclePrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// CORSPrinter ...
+type CORSPrinter struct {
+	Config *s3.CORSConfiguration
+}
+
+// JSON ...
+func (c *CORSPrinter) JSON() []byte {
+	return printer.MarshalObject(c.document(), "json")
+}
+
+// YAML ...
+func (c *CORSPrinter) YAML() []byte {
+	return printer.MarshalObject(c.document(), "yaml")
+}
+
+func (c *CORSPrinter) document() *s3.CORSConfiguration {
+	if c.Config == nil {
+		return &s3.CORSConfiguration{CORSRules: []s3.CORSRule{}}
+	}
+	return c.Config
+}
+
+// Columns ...
+func (c *CORSPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"ID",
+		"ALLOWED ORIGINS",
+		"ALLOWED METHODS",
+		"ALLOWED HEADERS",
+		"EXPOSE HEADERS",
+		"MAX AGE SECONDS",
+	}}
+}
+
+// Data ...
+func (c *CORSPrinter) Data() [][]string {
+	rules := c.document().CORSRules
+	if len(rules) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range rules {
+		data = append(data, []string{
+			rules[i].ID,
+			printer.ArrayOfStringsToString(rules[i].AllowedOrigins),
+			printer.ArrayOfStringsToString(rules[i].AllowedMethods),
+			printer.ArrayOfStringsToString(rules[i].AllowedHeaders),
+			printer.ArrayOfStringsToString(rules[i].ExposeHeaders),
+			strconv.Itoa(rules[i].MaxAgeSeconds),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (c *CORSPrinter) Paging() [][]string {
+	return nil
+}
+
+// ======================================
+
+// PolicyPrinter ...
+type PolicyPrinter struct {
+	Policy *s3.Policy
+}
+
+// JSON ...
+func (p *PolicyPrinter) JSON() []byte {
+	return printer.MarshalObject(p.document(), "json")
+}
+
+// YAML ...
+func (p *PolicyPrinter) YAML() []byte {
+	return printer.MarshalObject(p.document(), "yaml")
+}
+
+func (p *PolicyPrinter) document() *s3.Policy {
+	if p.Policy == nil {
+		return &s3.Policy{Statement: []s3.PolicyStatement{}}
+	}
+	return p.Policy
+}
+
+// Columns ...
+func (p *PolicyPrinter) Columns() [][]string {
+	return [][]string{0: {
+		"SID",
+		"EFFECT",
+		"PRINCIPAL",
+		"ACTIONS",
+		"RESOURCES",
+	}}
+}
+
+// Data ...
+func (p *PolicyPrinter) Data() [][]string {
+	statements := p.document().Statement
+	if len(statements) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range statements {
+		principal, _ := json.Marshal(statements[i].Principal)
+		data = append(data, []string{
+			statements[i].Sid,
+			statements[i].Effect,
+			string(principal),
+			printer.ArrayOfStringsToString(s3.StringList(statements[i].Action)),
+			printer.ArrayOfStringsToString(s3.StringList(statements[i].Resource)),
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (p *PolicyPrinter) Paging() [][]string {
+	return nil
+}
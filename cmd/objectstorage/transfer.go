@@ -0,0 +1,550 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
+)
+
+const (
+	transferUpload   = "upload"
+	transferDownload = "download"
+	transferCopy     = "copy"
+	transferDelete   = "delete"
+
+	transferOK      = "ok"
+	transferPlanned = "planned"
+
+	presignDefaultExpiry = 3600
+)
+
+// Transfer records a single upload, download, copy or delete
+type Transfer struct {
+	Action      string `json:"action"`
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	Size        int64  `json:"size"`
+	Result      string `json:"result"`
+
+	src location
+	dst location
+}
+
+// location is an object, prefix or bucket in the object storage, or a local
+// file or directory
+type location struct {
+	Bucket string
+	Key    string
+	Path   string
+}
+
+// entry is a file or object found under a location, keyed by its path
+// relative to the location
+type entry struct {
+	Size     int64
+	Modified time.Time
+}
+
+// transferOptions holds the flags shared by the transfer commands
+type transferOptions struct {
+	Endpoint    string
+	Concurrency int
+	Recursive   bool
+	DryRun      bool
+	Delete      bool
+}
+
+// parseLocation parses an s3://bucket/key URI or a local path
+func parseLocation(s string) (location, error) {
+	if !s3.IsURI(s) {
+		if s == "" {
+			return location{}, errors.New("empty path")
+		}
+		return location{Path: s}, nil
+	}
+
+	bucket, key, err := s3.SplitURI(s)
+	if err != nil {
+		return location{}, err
+	}
+
+	return location{Bucket: bucket, Key: key}, nil
+}
+
+// parseLocations parses the source and destination of a transfer
+func parseLocations(source, destination string) (src, dst location, err error) {
+	if src, err = parseLocation(source); err != nil {
+		return location{}, location{}, fmt.Errorf("invalid source : %v", err)
+	}
+
+	if dst, err = parseLocation(destination); err != nil {
+		return location{}, location{}, fmt.Errorf("invalid destination : %v", err)
+	}
+
+	return src, dst, nil
+}
+
+// parseBucket parses an s3://bucket URI
+func parseBucket(uri string) (string, error) {
+	loc, err := parseLocation(uri)
+	if err != nil || !loc.remote() || strings.Trim(loc.Key, "/") != "" {
+		return "", fmt.Errorf("invalid bucket URI %q, expected s3://bucket", uri)
+	}
+
+	return loc.Bucket, nil
+}
+
+func (l location) remote() bool {
+	return l.Bucket != ""
+}
+
+func (l location) String() string {
+	if l.remote() {
+		return "s3://" + l.Bucket + "/" + l.Key
+	}
+	return l.Path
+}
+
+// base returns the last element of the key or path
+func (l location) base() string {
+	if l.remote() {
+		return path.Base(l.Key)
+	}
+	return filepath.Base(l.Path)
+}
+
+// isDir reports whether the location names a prefix or a local directory
+func (l location) isDir() bool {
+	if l.remote() {
+		return l.Key == "" || strings.HasSuffix(l.Key, "/")
+	}
+
+	if strings.HasSuffix(l.Path, string(filepath.Separator)) || strings.HasSuffix(l.Path, "/") {
+		return true
+	}
+
+	info, err := os.Stat(l.Path)
+	return err == nil && info.IsDir()
+}
+
+// join returns the location of a relative path below this location
+func (l location) join(rel string) location {
+	if l.remote() {
+		return location{Bucket: l.Bucket, Key: dirPrefix(l.Key) + rel}
+	}
+	return location{Path: filepath.Join(l.Path, filepath.FromSlash(rel))}
+}
+
+// dirPrefix returns the key as a prefix ending in a slash
+func dirPrefix(key string) string {
+	if key == "" || strings.HasSuffix(key, "/") {
+		return key
+	}
+	return key + "/"
+}
+
+// transferAction returns the action which moves an object from src to dst
+func transferAction(src, dst location) (string, error) {
+	switch {
+	case src.remote() && dst.remote():
+		return transferCopy, nil
+	case src.remote():
+		return transferDownload, nil
+	case dst.remote():
+		return transferUpload, nil
+	}
+
+	return "", errors.New("the source or destination must be an s3:// URI")
+}
+
+func newTransfer(action string, src, dst location, size int64) Transfer {
+	t := Transfer{Action: action, Source: src.String(), Size: size, src: src, dst: dst}
+	if action != transferDelete {
+		t.Destination = dst.String()
+	}
+	return t
+}
+
+// listLocal returns the files below a local directory. A missing directory
+// has no entries
+func listLocal(root string) (map[string]entry, error) {
+	entries := map[string]entry{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		entries[filepath.ToSlash(rel)] = entry{Size: info.Size(), Modified: info.ModTime()}
+		return nil
+	})
+
+	return entries, err
+}
+
+// listRemote returns the objects below a prefix, ignoring directory markers
+func listRemote(ctx context.Context, c *s3.Client, loc location) (map[string]entry, error) {
+	prefix := dirPrefix(loc.Key)
+	listing, err := c.ListObjects(ctx, loc.Bucket, prefix, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]entry{}
+	for i := range listing.Objects {
+		rel := strings.TrimPrefix(listing.Objects[i].Key, prefix)
+		if rel == "" || strings.HasSuffix(rel, "/") {
+			continue
+		}
+		entries[rel] = entry{Size: listing.Objects[i].Size, Modified: listing.Objects[i].LastModified}
+	}
+
+	return entries, nil
+}
+
+func listEntries(ctx context.Context, c *s3.Client, loc location) (map[string]entry, error) {
+	if loc.remote() {
+		return listRemote(ctx, c, loc)
+	}
+	return listLocal(loc.Path)
+}
+
+// planCopy returns the transfers which copy src to dst. Without recursion
+// the source is a single file or object, which keeps its name when the
+// destination is a directory or prefix
+func planCopy(ctx context.Context, c *s3.Client, src, dst location, recursive bool) ([]Transfer, error) {
+	action, err := transferAction(src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !recursive {
+		if src.isDir() {
+			return nil, fmt.Errorf("%s is a directory or prefix, use --recursive to copy it", src)
+		}
+
+		if dst.isDir() {
+			dst = dst.join(src.base())
+		}
+
+		var size int64
+		if src.remote() {
+			obj, err := c.HeadObject(ctx, src.Bucket, src.Key)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s : %v", src, err)
+			}
+			size = obj.Size
+		} else {
+			info, err := os.Stat(src.Path)
+			if err != nil {
+				return nil, err
+			}
+			size = info.Size()
+		}
+
+		return []Transfer{newTransfer(action, src, dst, size)}, nil
+	}
+
+	entries, err := listEntries(ctx, c, src)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := []Transfer{}
+	for _, rel := range sortedKeys(entries) {
+		transfers = append(transfers, newTransfer(action, src.join(rel), dst.join(rel), entries[rel].Size))
+	}
+
+	return transfers, nil
+}
+
+// planSync returns the transfers which make dst match src. Files are copied
+// when missing from the destination, when their sizes differ or when the
+// source is newer. With deleteExtra, files only found in the destination are
+// deleted
+func planSync(ctx context.Context, c *s3.Client, src, dst location, deleteExtra bool) ([]Transfer, error) {
+	action, err := transferAction(src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !src.remote() {
+		if info, err := os.Stat(src.Path); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", src)
+		}
+	}
+
+	srcEntries, err := listEntries(ctx, c, src)
+	if err != nil {
+		return nil, err
+	}
+
+	dstEntries, err := listEntries(ctx, c, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := []Transfer{}
+	for _, rel := range sortedKeys(srcEntries) {
+		s := srcEntries[rel]
+		d, ok := dstEntries[rel]
+		if ok && d.Size == s.Size && !s.Modified.After(d.Modified) {
+			continue
+		}
+		transfers = append(transfers, newTransfer(action, src.join(rel), dst.join(rel), s.Size))
+	}
+
+	if deleteExtra {
+		for _, rel := range sortedKeys(dstEntries) {
+			if _, ok := srcEntries[rel]; !ok {
+				transfers = append(transfers, newTransfer(transferDelete, dst.join(rel), location{}, dstEntries[rel].Size))
+			}
+		}
+	}
+
+	return transfers, nil
+}
+
+// planRemove returns the deletes of an object, or of every object below a
+// prefix with recursion
+func planRemove(ctx context.Context, c *s3.Client, loc location, recursive bool) ([]Transfer, error) {
+	if !loc.remote() {
+		return nil, fmt.Errorf("%s is not an s3:// URI", loc)
+	}
+
+	if !recursive {
+		if loc.isDir() {
+			return nil, fmt.Errorf("%s is a prefix, use --recursive to remove it", loc)
+		}
+		return []Transfer{newTransfer(transferDelete, loc, location{}, 0)}, nil
+	}
+
+	listing, err := c.ListObjects(ctx, loc.Bucket, dirPrefix(loc.Key), "")
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := []Transfer{}
+	for i := range listing.Objects {
+		obj := location{Bucket: loc.Bucket, Key: listing.Objects[i].Key}
+		transfers = append(transfers, newTransfer(transferDelete, obj, location{}, listing.Objects[i].Size))
+	}
+
+	return transfers, nil
+}
+
+// runTransfer performs a single transfer, returning the bytes transferred
+func runTransfer(ctx context.Context, c *s3.Client, t *Transfer) (int64, error) {
+	switch t.Action {
+	case transferUpload:
+		return c.UploadFile(ctx, t.src.Path, t.dst.Bucket, t.dst.Key)
+	case transferDownload:
+		return c.DownloadFile(ctx, t.src.Bucket, t.src.Key, t.dst.Path)
+	case transferCopy:
+		return t.Size, c.CopyObject(ctx, t.src.Bucket, t.src.Key, t.dst.Bucket, t.dst.Key)
+	case transferDelete:
+		return t.Size, removeLocation(ctx, c, t.src)
+	}
+
+	return 0, fmt.Errorf("unknown transfer action %q", t.Action)
+}
+
+func removeLocation(ctx context.Context, c *s3.Client, loc location) error {
+	if loc.remote() {
+		return c.DeleteObject(ctx, loc.Bucket, loc.Key)
+	}
+	return os.Remove(loc.Path)
+}
+
+// runTransfers performs the transfers with up to concurrency in flight,
+// recording the result of each, and returns the number which failed. With
+// removeSource, the source of each successful transfer is removed afterwards
+func runTransfers(ctx context.Context, c *s3.Client, transfers []Transfer, concurrency int, removeSource bool) int {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		sem    = make(chan struct{}, max(concurrency, 1))
+	)
+
+	for i := range transfers {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(t *Transfer) {
+			defer func() { <-sem; wg.Done() }()
+
+			n, err := runTransfer(ctx, c, t)
+			if err == nil && removeSource && t.Action != transferDelete {
+				err = removeLocation(ctx, c, t.src)
+			}
+
+			if err != nil {
+				t.Result = err.Error()
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+
+			t.Size, t.Result = n, transferOK
+		}(&transfers[i])
+	}
+	wg.Wait()
+
+	return failed
+}
+
+func sortedKeys(entries map[string]entry) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// ======================================
+
+// s3Client returns a client for the object storage, using its S3 hostname
+// unless an endpoint is provided
+func (o *options) s3Client(opts *transferOptions) (*s3.Client, error) {
+	storage, err := o.get()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving object storage : %v", err)
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = storage.S3Hostname
+	}
+
+	c, err := s3.New(endpoint, storage.S3AccessKey, storage.S3SecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Concurrency > 0 {
+		c.Concurrency = opts.Concurrency
+	}
+
+	return c, nil
+}
+
+// copyObjects runs cp or, with removeSource, mv
+func (o *options) copyObjects(cmd *cobra.Command, removeSource bool) error {
+	opts, err := transferFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	src, dst, err := parseLocations(o.Base.Args[1], o.Base.Args[2])
+	if err != nil {
+		return err
+	}
+
+	client, err := o.s3Client(opts)
+	if err != nil {
+		return err
+	}
+
+	transfers, err := planCopy(o.Base.Context, client, src, dst, opts.Recursive)
+	if err != nil {
+		return fmt.Errorf("error planning %s : %v", cmd.Name(), err)
+	}
+
+	err = o.transfer(client, transfers, opts, removeSource)
+	return o.Base.Printer.DisplayWithError(&TransfersPrinter{Transfers: transfers}, err)
+}
+
+// transfer performs or, with a dry run, only plans the transfers, returning
+// an error when any fail
+func (o *options) transfer(c *s3.Client, transfers []Transfer, opts *transferOptions, removeSource bool) error {
+	if opts.DryRun {
+		for i := range transfers {
+			transfers[i].Result = transferPlanned
+		}
+		return nil
+	}
+
+	if failed := runTransfers(o.Base.Context, c, transfers, c.Concurrency, removeSource); failed > 0 {
+		return fmt.Errorf("%d of %d transfers failed", failed, len(transfers))
+	}
+
+	return nil
+}
+
+// addEndpointFlag adds the flag which overrides the S3 endpoint
+func addEndpointFlag(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"endpoint",
+		"",
+		"(optional) S3 endpoint to use instead of the object storage hostname, such as http://localhost:9000",
+	)
+}
+
+// addTransferFlags adds the flags shared by the commands which transfer or
+// remove objects
+func addTransferFlags(cmd *cobra.Command) {
+	addEndpointFlag(cmd)
+	cmd.Flags().Int("concurrency", s3.DefaultConcurrency, "(optional) number of objects or parts transferred at once")
+	cmd.Flags().Bool("dry-run", false, "(optional) list the changes without making them")
+}
+
+// transferFlags reads the endpoint flag and, when the command has them, the
+// concurrency, recursive, dry-run and delete flags
+func transferFlags(cmd *cobra.Command) (*transferOptions, error) {
+	opts := &transferOptions{}
+
+	var err error
+	if opts.Endpoint, err = cmd.Flags().GetString("endpoint"); err != nil {
+		return nil, fmt.Errorf("error parsing flag 'endpoint' for object storage %s : %v", cmd.Name(), err)
+	}
+
+	if cmd.Flags().Lookup("concurrency") != nil {
+		if opts.Concurrency, err = cmd.Flags().GetInt("concurrency"); err != nil {
+			return nil, fmt.Errorf("error parsing flag 'concurrency' for object storage %s : %v", cmd.Name(), err)
+		}
+	}
+
+	for name, dest := range map[string]*bool{
+		"recursive": &opts.Recursive,
+		"dry-run":   &opts.DryRun,
+		"delete":    &opts.Delete,
+	} {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		if *dest, err = cmd.Flags().GetBool(name); err != nil {
+			return nil, fmt.Errorf("error parsing flag '%s' for object storage %s : %v", name, cmd.Name(), err)
+		}
+	}
+
+	return opts, nil
+}
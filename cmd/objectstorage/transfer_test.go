@@ -0,0 +1,266 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vultr/vultr-cli/v3/pkg/s3"
+)
+
+// fakeBucket is an in-memory S3 bucket serving path style requests
+type fakeBucket struct {
+	mu       sync.Mutex
+	name     string
+	objects  map[string][]byte
+	modified time.Time
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != b.name {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		type object struct {
+			Key          string
+			Size         int
+			LastModified time.Time
+		}
+		result := struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []object
+		}{}
+
+		prefix := r.URL.Query().Get("prefix")
+		for k, v := range b.objects {
+			if strings.HasPrefix(k, prefix) {
+				result.Contents = append(result.Contents, object{Key: k, Size: len(v), LastModified: b.modified})
+			}
+		}
+		_ = xml.NewEncoder(w).Encode(&result)
+	case r.Method == http.MethodGet:
+		body, ok := b.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		b.objects[key] = body
+	case r.Method == http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (b *fakeBucket) keys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var keys []string
+	for k := range b.objects {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func newFakeBucket(t *testing.T, objects map[string]string) (*fakeBucket, *s3.Client) {
+	t.Helper()
+
+	b := &fakeBucket{name: "bucket", objects: map[string][]byte{}, modified: time.Now().Add(-time.Minute).UTC()}
+	for k, v := range objects {
+		b.objects[k] = []byte(v)
+	}
+
+	srv := httptest.NewServer(b)
+	t.Cleanup(srv.Close)
+
+	c, err := s3.New(srv.URL, "access", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b, c
+}
+
+func TestRemoveRecursive(t *testing.T) {
+	b, c := newFakeBucket(t, map[string]string{
+		"logs/a":         "a",
+		"logs/b/c":       "c",
+		"logs-archive/d": "d",
+		"other":          "e",
+	})
+
+	for _, uri := range []string{"s3://bucket/logs", "s3://bucket/logs/"} {
+		loc, err := parseLocation(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		transfers, err := planRemove(context.Background(), c, loc, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sources []string
+		for i := range transfers {
+			sources = append(sources, transfers[i].Source)
+		}
+		slices.Sort(sources)
+
+		want := []string{"s3://bucket/logs/a", "s3://bucket/logs/b/c"}
+		if !slices.Equal(sources, want) {
+			t.Errorf("rm -r %s plans %v, want %v", uri, sources, want)
+		}
+	}
+
+	loc, _ := parseLocation("s3://bucket/logs")
+	transfers, err := planRemove(context.Background(), c, loc, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if failed := runTransfers(context.Background(), c, transfers, 2, false); failed != 0 {
+		t.Fatalf("%d deletes failed", failed)
+	}
+
+	if keys := b.keys(); !slices.Equal(keys, []string{"logs-archive/d", "other"}) {
+		t.Errorf("objects left = %v, want [logs-archive/d other]", keys)
+	}
+}
+
+func TestRemovePrefixNeedsRecursive(t *testing.T) {
+	_, c := newFakeBucket(t, nil)
+
+	loc, _ := parseLocation("s3://bucket/logs/")
+	if _, err := planRemove(context.Background(), c, loc, false); err == nil {
+		t.Error("rm of a prefix without --recursive succeeded")
+	}
+}
+
+func TestSyncUpload(t *testing.T) {
+	b, c := newFakeBucket(t, map[string]string{
+		"site/same.txt":        "same",
+		"site/changed.txt":     "old",
+		"site/extra.txt":       "extra",
+		"site-other/extra.txt": "kept",
+	})
+
+	dir := t.TempDir()
+	files := map[string]string{"same.txt": "same", "changed.txt": "changed", "sub/new.txt": "new"}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// older than the objects, so only size differences are copied
+		old := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src, dst, err := parseLocations(dir, "s3://bucket/site")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfers, err := planSync(context.Background(), c, src, dst, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var planned []string
+	for i := range transfers {
+		planned = append(planned, transfers[i].Action+" "+transfers[i].Source+" "+transfers[i].Destination)
+	}
+
+	want := []string{
+		transferUpload + " " + filepath.Join(dir, "changed.txt") + " s3://bucket/site/changed.txt",
+		transferUpload + " " + filepath.Join(dir, "sub", "new.txt") + " s3://bucket/site/sub/new.txt",
+		transferDelete + " s3://bucket/site/extra.txt ",
+	}
+	if !slices.Equal(planned, want) {
+		t.Fatalf("sync plans %q, want %q", planned, want)
+	}
+
+	if failed := runTransfers(context.Background(), c, transfers, 2, false); failed != 0 {
+		t.Fatalf("%d transfers failed", failed)
+	}
+
+	wantKeys := []string{"site-other/extra.txt", "site/changed.txt", "site/same.txt", "site/sub/new.txt"}
+	if keys := b.keys(); !slices.Equal(keys, wantKeys) {
+		t.Errorf("objects after sync = %v, want %v", keys, wantKeys)
+	}
+
+	if got := string(b.objects["site/changed.txt"]); got != "changed" {
+		t.Errorf("site/changed.txt = %q, want changed", got)
+	}
+}
+
+func TestSyncDownload(t *testing.T) {
+	_, c := newFakeBucket(t, map[string]string{
+		"site/a.txt":     "a",
+		"site/dir/b.txt": "b",
+		"site/dir/":      "",
+	})
+
+	dir := t.TempDir()
+	src, dst, err := parseLocations("s3://bucket/site", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfers, err := planSync(context.Background(), c, src, dst, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transfers) != 2 {
+		t.Fatalf("sync plans %d transfers, want 2 : %+v", len(transfers), transfers)
+	}
+
+	if failed := runTransfers(context.Background(), c, transfers, 2, false); failed != 0 {
+		t.Fatalf("%d transfers failed", failed)
+	}
+
+	for name, want := range map[string]string{"a.txt": "a", "dir/b.txt": "b"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q (%v), want %q", name, got, err, want)
+		}
+	}
+
+	// a second sync finds nothing to do
+	transfers, err = planSync(context.Background(), c, src, dst, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 0 {
+		t.Errorf("second sync plans %+v, want nothing", transfers)
+	}
+}
@@ -0,0 +1,40 @@
+package printer
+
+import "strings"
+
+// DisplayWithError displays the ResourceOutput and returns err. Unlike
+// Display, it does not exit after JSON and YAML output, so commands can show
+// a partial result and still fail with the error
+func (o *Output) DisplayWithError(r ResourceOutput, err error) error {
+	defer o.flush()
+
+	switch strings.ToLower(o.Output) {
+	case "json":
+		o.displayNonText(r.JSON())
+	case "yaml":
+		o.displayNonText(r.YAML())
+	default:
+		o.display(r.Columns())
+		o.display(r.Data())
+		if r.Paging() != nil {
+			o.display(r.Paging())
+		}
+	}
+
+	return err
+}
+
+// IsText reports whether the output is text rather than JSON or YAML, in
+// which case commands may display progress as it happens
+func (o *Output) IsText() bool {
+	output := strings.ToLower(o.Output)
+	return output != "json" && output != "yaml"
+}
+
+// ValueOr returns value, or fallback when it is empty
+func ValueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
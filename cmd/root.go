@@ -4,6 +4,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -57,6 +58,17 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// run the container registry credential helper when invoked through a
+	// docker-credential-vultr link. The engine reads the helper output, so a
+	// missing config file must not be reported on stdout
+	if filepath.Base(os.Args[0]) == containerregistry.CredentialHelperName {
+		args := []string{"container-registry", "credentials", "helper"}
+		if _, err := os.Stat(configHome()); err != nil {
+			args = append(args, "--config=")
+		}
+		rootCmd.SetArgs(append(args, os.Args[1:]...))
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -114,3 +114,62 @@ func (s *SnapshotPrinter) Data() [][]string {
 func (s *SnapshotPrinter) Paging() [][]string {
 	return nil
 }
+
+// ======================================
+
+// RotatePrinter ...
+type RotatePrinter struct {
+	Time      string        `json:"time"`
+	DryRun    bool          `json:"dry_run"`
+	Snapshots []RotateEntry `json:"snapshots"`
+}
+
+// JSON ...
+func (r *RotatePrinter) JSON() []byte {
+	return printer.MarshalObject(r, "json")
+}
+
+// YAML ...
+func (r *RotatePrinter) YAML() []byte {
+	return printer.MarshalObject(r, "yaml")
+}
+
+// Columns ...
+func (r *RotatePrinter) Columns() [][]string {
+	return [][]string{0: {
+		"INSTANCE ID",
+		"SNAPSHOT ID",
+		"DESCRIPTION",
+		"DATE CREATED",
+		"ACTION",
+		"RESULT",
+		"ERROR",
+	}}
+}
+
+// Data ...
+func (r *RotatePrinter) Data() [][]string {
+	if len(r.Snapshots) == 0 {
+		return [][]string{0: {"---", "---", "---", "---", "---", "---", "---"}}
+	}
+
+	var data [][]string
+	for i := range r.Snapshots {
+		data = append(data, []string{
+			r.Snapshots[i].InstanceID,
+			r.Snapshots[i].SnapshotID,
+			r.Snapshots[i].Description,
+			r.Snapshots[i].DateCreated,
+			r.Snapshots[i].Action,
+			r.Snapshots[i].Result,
+			r.Snapshots[i].Error,
+		})
+	}
+
+	return data
+}
+
+// Paging ...
+func (r *RotatePrinter) Paging() [][]string {
+	return nil
+}
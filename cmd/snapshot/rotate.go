@@ -0,0 +1,296 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vultr/govultr/v3"
+	"github.com/vultr/vultr-cli/v3/cmd/utils"
+)
+
+const (
+	rotateDefaultKeep   = 7
+	rotateWaitInterval  = 15 * time.Second
+	rotateWaitTimeout   = 2 * time.Hour
+	rotateTimestampForm = "20060102T150405Z"
+
+	statusComplete = "complete"
+
+	rotateActionCreate = "create"
+	rotateActionKeep   = "keep"
+	rotateActionDelete = "delete"
+
+	rotateResultPlanned = "planned"
+	rotateResultDone    = "done"
+	rotateResultKept    = "kept"
+	rotateResultFailed  = "failed"
+)
+
+var instanceIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// RotateEntry is the action taken on a snapshot by a rotation
+type RotateEntry struct {
+	InstanceID  string `json:"instance_id"`
+	SnapshotID  string `json:"snapshot_id,omitempty"`
+	Description string `json:"description"`
+	DateCreated string `json:"date_created,omitempty"`
+	Action      string `json:"action"`
+	Result      string `json:"result"`
+	Error       string `json:"error,omitempty"`
+}
+
+// rotateOptions holds the flags of snapshot rotate
+type rotateOptions struct {
+	Target string
+	Keep   int
+	Prefix string
+	DryRun bool
+}
+
+// rotationPrefix returns the description prefix of the rotated snapshots of
+// an instance. The instance ID is part of it since snapshots do not record
+// the instance they were taken from
+func rotationPrefix(prefix, instanceID string) string {
+	return prefix + instanceID + "-"
+}
+
+// planRotation returns the snapshots beyond the newest keep among those
+// whose description starts with match, most recent first
+func planRotation(snapshots []govultr.Snapshot, match string, keep int) (kept, expired []govultr.Snapshot) {
+	var matched []govultr.Snapshot
+	for i := range snapshots {
+		if strings.HasPrefix(snapshots[i].Description, match) {
+			matched = append(matched, snapshots[i])
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].DateCreated > matched[j].DateCreated
+	})
+
+	if len(matched) <= keep {
+		return matched, nil
+	}
+
+	return matched[:keep], matched[keep:]
+}
+
+// ======================================
+
+// resolveInstances returns the IDs of the instances targeted by the rotation,
+// either a single instance ID or every instance with the tag
+func (o *options) resolveInstances(target string) ([]string, error) {
+	if instanceIDPattern.MatchString(target) {
+		instance, _, err := o.Base.Client.Instance.Get(o.Base.Context, target)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving instance %s : %v", target, err)
+		}
+		return []string{instance.ID}, nil
+	}
+
+	var ids []string
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault, Tag: target}
+	for {
+		instances, meta, _, err := o.Base.Client.Instance.List(o.Base.Context, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving instances with tag %s : %v", target, err)
+		}
+		for i := range instances {
+			ids = append(ids, instances[i].ID)
+		}
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			break
+		}
+		opts.Cursor = meta.Links.Next
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no instances found with tag %s", target)
+	}
+
+	return ids, nil
+}
+
+// listAll retrieves every snapshot, following the paging cursors
+func (o *options) listAll() ([]govultr.Snapshot, error) {
+	var all []govultr.Snapshot
+	opts := &govultr.ListOptions{PerPage: utils.PerPageDefault}
+
+	for {
+		snapshots, meta, _, err := o.Base.Client.Snapshot.List(o.Base.Context, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, snapshots...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		opts.Cursor = meta.Links.Next
+	}
+}
+
+// waitForSnapshot polls a snapshot until it is complete or the timeout
+// passes
+func (o *options) waitForSnapshot(id string) (*govultr.Snapshot, error) {
+	deadline := time.Now().Add(rotateWaitTimeout)
+	for {
+		snapshot, _, err := o.Base.Client.Snapshot.Get(o.Base.Context, id)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving snapshot : %v", err)
+		}
+
+		if strings.EqualFold(snapshot.Status, statusComplete) {
+			return snapshot, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for snapshot %s, status is %s", id, snapshot.Status)
+		}
+
+		time.Sleep(rotateWaitInterval)
+	}
+}
+
+// rotateInstance creates a snapshot of an instance and, once it is complete,
+// deletes its rotated snapshots beyond the retention count. Nothing is
+// deleted when the new snapshot could not be completed
+func (o *options) rotateInstance(instanceID string, opts *rotateOptions, snapshots []govultr.Snapshot) []RotateEntry {
+	match := rotationPrefix(opts.Prefix, instanceID)
+	created := RotateEntry{
+		InstanceID:  instanceID,
+		Description: match + time.Now().UTC().Format(rotateTimestampForm),
+		Action:      rotateActionCreate,
+		Result:      rotateResultPlanned,
+	}
+
+	// the new snapshot takes one of the retained places
+	kept, expired := planRotation(snapshots, match, max(opts.Keep-1, 0))
+
+	if !opts.DryRun {
+		snapshot, _, err := o.Base.Client.Snapshot.Create(o.Base.Context, &govultr.SnapshotReq{
+			InstanceID:  instanceID,
+			Description: created.Description,
+		})
+		if err == nil {
+			created.SnapshotID = snapshot.ID
+			snapshot, err = o.waitForSnapshot(snapshot.ID)
+		}
+		if err != nil {
+			created.Result, created.Error = rotateResultFailed, err.Error()
+			return []RotateEntry{created}
+		}
+		created.DateCreated, created.Result = snapshot.DateCreated, rotateResultDone
+	}
+
+	entries := []RotateEntry{created}
+	for i := range kept {
+		entries = append(entries, RotateEntry{
+			InstanceID:  instanceID,
+			SnapshotID:  kept[i].ID,
+			Description: kept[i].Description,
+			DateCreated: kept[i].DateCreated,
+			Action:      rotateActionKeep,
+			Result:      rotateResultPlanned,
+		})
+	}
+
+	for i := range expired {
+		entry := RotateEntry{
+			InstanceID:  instanceID,
+			SnapshotID:  expired[i].ID,
+			Description: expired[i].Description,
+			DateCreated: expired[i].DateCreated,
+			Action:      rotateActionDelete,
+			Result:      rotateResultPlanned,
+		}
+
+		if !opts.DryRun {
+			entry.Result = rotateResultDone
+			if err := o.Base.Client.Snapshot.Delete(o.Base.Context, expired[i].ID); err != nil {
+				entry.Result, entry.Error = rotateResultFailed, err.Error()
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if !opts.DryRun {
+		for i := range entries {
+			if entries[i].Action == rotateActionKeep {
+				entries[i].Result = rotateResultKept
+			}
+		}
+	}
+
+	return entries
+}
+
+// rotate rotates the snapshots of every targeted instance
+func (o *options) rotate(opts *rotateOptions) ([]RotateEntry, error) {
+	ids, err := o.resolveInstances(opts.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := o.listAll()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving snapshot list : %v", err)
+	}
+
+	entries := []RotateEntry{}
+	var failed int
+	for _, id := range ids {
+		for _, entry := range o.rotateInstance(id, opts, snapshots) {
+			if entry.Result == rotateResultFailed {
+				failed++
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	if failed > 0 {
+		return entries, fmt.Errorf("%d snapshot actions failed", failed)
+	}
+
+	return entries, nil
+}
+
+// rotateFlags reads the flags of snapshot rotate
+func rotateFlags(cmd *cobra.Command) (*rotateOptions, error) {
+	target, errIn := cmd.Flags().GetString("instance")
+	if errIn != nil {
+		return nil, fmt.Errorf("error parsing flag 'instance' for rotate : %v", errIn)
+	}
+
+	keep, errKe := cmd.Flags().GetInt("keep")
+	if errKe != nil {
+		return nil, fmt.Errorf("error parsing flag 'keep' for rotate : %v", errKe)
+	}
+
+	prefix, errDp := cmd.Flags().GetString("description-prefix")
+	if errDp != nil {
+		return nil, fmt.Errorf("error parsing flag 'description-prefix' for rotate : %v", errDp)
+	}
+
+	dryRun, errDr := cmd.Flags().GetBool("dry-run")
+	if errDr != nil {
+		return nil, fmt.Errorf("error parsing flag 'dry-run' for rotate : %v", errDr)
+	}
+
+	if keep < 1 {
+		return nil, errors.New("keep must be at least 1")
+	}
+
+	if prefix == "" {
+		return nil, errors.New("please provide a description prefix to match the rotated snapshots")
+	}
+
+	return &rotateOptions{Target: target, Keep: keep, Prefix: prefix, DryRun: dryRun}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vultr/govultr/v3"
@@ -13,6 +14,28 @@ import (
 	"github.com/vultr/vultr-cli/v3/pkg/cli"
 )
 
+var (
+	rotateLong = `Create a snapshot of an instance, or of every instance with the tag given to --instance, wait for it
+to complete and delete the older snapshots of the instance beyond the --keep most recent. Nothing is deleted for an
+instance whose new snapshot fails. With --dry-run, the snapshots that would be created, kept and deleted are shown
+without changing anything.
+
+Snapshots do not record the instance they were taken from, so the instance ID is appended to --description-prefix.
+With --description-prefix="nightly-", snapshots are described as "nightly-<instance ID>-<creation time>" and only
+snapshots whose description starts with "nightly-<instance ID>-" are rotated. Existing snapshots described as
+"nightly-..." without the instance ID are never matched, kept or deleted`
+	rotateExample = `
+	# Keep the last 7 nightly snapshots of an instance
+	vultr-cli snapshot rotate --instance="a7898453-dd9e-4b47-bdab-9dd7a3448f1f" --keep=7 --description-prefix="nightly-"
+
+	# Rotate every instance with a tag, logging JSON from cron
+	vultr-cli snapshot rotate --instance="backup" --keep=14 --description-prefix="nightly-" --output="json"
+
+	# Show the planned changes
+	vultr-cli snapshot rotate --instance="backup" --description-prefix="nightly-" --dry-run
+	`
+)
+
 // NewCmdSnapshot provides the CLI command for snapshot functions
 func NewCmdSnapshot(base *cli.Base) *cobra.Command { //nolint:gocyclo
 	o := &options{Base: base}
@@ -182,12 +205,56 @@ func NewCmdSnapshot(base *cli.Base) *cobra.Command { //nolint:gocyclo
 		},
 	}
 
+	// Rotate
+	rotate := &cobra.Command{
+		Use:     "rotate",
+		Short:   "Create a snapshot and delete the ones beyond the retention count",
+		Long:    rotateLong,
+		Example: rotateExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := rotateFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			started := time.Now().UTC().Format(time.RFC3339)
+			entries, err := o.rotate(opts)
+			if entries == nil {
+				return fmt.Errorf("error rotating snapshots : %v", err)
+			}
+
+			data := &RotatePrinter{Time: started, DryRun: opts.DryRun, Snapshots: entries}
+			return o.Base.Printer.DisplayWithError(data, err)
+		},
+	}
+
+	rotate.Flags().StringP("instance", "i", "", "ID or tag of the instances to snapshot")
+	if err := rotate.MarkFlagRequired("instance"); err != nil {
+		fmt.Printf("error marking snapshot rotate 'instance' flag required: %v", err)
+		os.Exit(1)
+	}
+
+	rotate.Flags().IntP("keep", "k", rotateDefaultKeep, "(optional) number of snapshots to keep per instance")
+	rotate.Flags().StringP(
+		"description-prefix",
+		"d",
+		"",
+		"description prefix of the rotated snapshots, to which the instance ID and a dash are appended when matching",
+	)
+	if err := rotate.MarkFlagRequired("description-prefix"); err != nil {
+		fmt.Printf("error marking snapshot rotate 'description-prefix' flag required: %v", err)
+		os.Exit(1)
+	}
+
+	rotate.Flags().Bool("dry-run", false, "(optional) show the planned changes without applying them")
+
 	cmd.AddCommand(
 		list,
 		get,
 		create,
 		createURL,
 		del,
+		rotate,
 	)
 
 	return cmd
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// ParseAge parses an age such as 36h, 30d or 2w. Days and weeks are accepted
+// in addition to the units of time.ParseDuration
+func ParseAge(age string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": day, "w": week}
+
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(age, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q, use a duration such as 36h, 30d or 2w", age)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q, use a duration such as 36h, 30d or 2w", age)
+	}
+
+	return d, nil
+}
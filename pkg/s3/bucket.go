@@ -0,0 +1,478 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxLifecycleRules = 1000
+	maxCORSRules      = 100
+	maxRuleIDLength   = 255
+
+	lifecycleEnabled  = "Enabled"
+	lifecycleDisabled = "Disabled"
+
+	policyVersion = "2012-10-17"
+)
+
+// corsMethods are the methods a CORS rule may allow
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// LifecycleConfiguration is the lifecycle configuration of a bucket
+type LifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration" json:"-" yaml:"-"`
+	Rules   []LifecycleRule `xml:"Rule" json:"Rules" yaml:"Rules"`
+}
+
+// LifecycleRule expires the objects matching its filter
+type LifecycleRule struct {
+	ID                             string                          `xml:"ID,omitempty" json:"ID,omitempty" yaml:"ID,omitempty"` //nolint:lll
+	Status                         string                          `xml:"Status" json:"Status" yaml:"Status"`
+	Filter                         *LifecycleFilter                `xml:"Filter,omitempty" json:"Filter,omitempty" yaml:"Filter,omitempty"`                                                                         //nolint:lll
+	Expiration                     *LifecycleExpiration            `xml:"Expiration,omitempty" json:"Expiration,omitempty" yaml:"Expiration,omitempty"`                                                             //nolint:lll
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty" json:"NoncurrentVersionExpiration,omitempty" yaml:"NoncurrentVersionExpiration,omitempty"`          //nolint:lll
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty" json:"AbortIncompleteMultipartUpload,omitempty" yaml:"AbortIncompleteMultipartUpload,omitempty"` //nolint:lll
+}
+
+// LifecycleFilter selects the objects of a rule by prefix
+type LifecycleFilter struct {
+	Prefix string `xml:"Prefix" json:"Prefix" yaml:"Prefix"`
+}
+
+// LifecycleExpiration expires objects after a number of days or on a date
+type LifecycleExpiration struct {
+	Days                      int    `xml:"Days,omitempty" json:"Days,omitempty" yaml:"Days,omitempty"`
+	Date                      string `xml:"Date,omitempty" json:"Date,omitempty" yaml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool   `xml:"ExpiredObjectDeleteMarker,omitempty" json:"ExpiredObjectDeleteMarker,omitempty" yaml:"ExpiredObjectDeleteMarker,omitempty"` //nolint:lll
+}
+
+// NoncurrentVersionExpiration expires old versions of objects
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays" json:"NoncurrentDays" yaml:"NoncurrentDays"`
+}
+
+// AbortIncompleteMultipartUpload removes the parts of abandoned uploads
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation" json:"DaysAfterInitiation" yaml:"DaysAfterInitiation"`
+}
+
+// CORSConfiguration is the CORS configuration of a bucket
+type CORSConfiguration struct {
+	XMLName   xml.Name   `xml:"CORSConfiguration" json:"-" yaml:"-"`
+	CORSRules []CORSRule `xml:"CORSRule" json:"CORSRules" yaml:"CORSRules"`
+}
+
+// CORSRule allows cross origin requests from its origins
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty" json:"ID,omitempty" yaml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"AllowedOrigins" yaml:"AllowedOrigins"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"AllowedMethods" yaml:"AllowedMethods"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"AllowedHeaders,omitempty" yaml:"AllowedHeaders,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"ExposeHeaders,omitempty" yaml:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty" yaml:"MaxAgeSeconds,omitempty"`
+}
+
+// Policy is a bucket policy document
+type Policy struct {
+	Version   string            `json:"Version" yaml:"Version"`
+	ID        string            `json:"Id,omitempty" yaml:"Id,omitempty"`
+	Statement []PolicyStatement `json:"Statement" yaml:"Statement"`
+}
+
+// PolicyStatement allows or denies actions on resources. Principal, Action
+// and Resource may be a string or a list in the document
+type PolicyStatement struct {
+	Sid       string         `json:"Sid,omitempty" yaml:"Sid,omitempty"`
+	Effect    string         `json:"Effect" yaml:"Effect"`
+	Principal any            `json:"Principal,omitempty" yaml:"Principal,omitempty"`
+	Action    any            `json:"Action" yaml:"Action"`
+	Resource  any            `json:"Resource" yaml:"Resource"`
+	Condition map[string]any `json:"Condition,omitempty" yaml:"Condition,omitempty"`
+}
+
+// Validate checks the configuration against the limits of the S3 API
+func (l *LifecycleConfiguration) Validate() error {
+	if len(l.Rules) == 0 {
+		return errors.New("lifecycle configuration has no rules")
+	}
+
+	if len(l.Rules) > maxLifecycleRules {
+		return fmt.Errorf("lifecycle configuration has %d rules, the maximum is %d", len(l.Rules), maxLifecycleRules)
+	}
+
+	var problems []string
+	ids := map[string]bool{}
+	for i := range l.Rules {
+		r := &l.Rules[i]
+		name := fmt.Sprintf("rule %d", i+1)
+		if r.ID != "" {
+			name = fmt.Sprintf("rule %q", r.ID)
+		}
+
+		problems = append(problems, validateRuleID(name, r.ID, ids)...)
+
+		if r.Status != lifecycleEnabled && r.Status != lifecycleDisabled {
+			problems = append(problems, fmt.Sprintf("%s : status must be %s or %s", name, lifecycleEnabled, lifecycleDisabled))
+		}
+
+		if r.Expiration == nil && r.NoncurrentVersionExpiration == nil && r.AbortIncompleteMultipartUpload == nil {
+			problems = append(problems, fmt.Sprintf("%s : no expiration or abort action", name))
+		}
+
+		if e := r.Expiration; e != nil {
+			problems = append(problems, validateExpiration(name, e)...)
+		}
+
+		if n := r.NoncurrentVersionExpiration; n != nil && n.NoncurrentDays < 1 {
+			problems = append(problems, fmt.Sprintf("%s : noncurrent days must be at least 1", name))
+		}
+
+		if a := r.AbortIncompleteMultipartUpload; a != nil && a.DaysAfterInitiation < 1 {
+			problems = append(problems, fmt.Sprintf("%s : days after initiation must be at least 1", name))
+		}
+	}
+
+	return joinProblems("invalid lifecycle configuration", problems)
+}
+
+func validateExpiration(name string, e *LifecycleExpiration) []string {
+	set := 0
+	for _, ok := range []bool{e.Days != 0, e.Date != "", e.ExpiredObjectDeleteMarker} {
+		if ok {
+			set++
+		}
+	}
+
+	var problems []string
+	if set != 1 {
+		problems = append(problems, fmt.Sprintf("%s : expiration needs exactly one of days, date or delete marker", name))
+	}
+
+	if e.Days < 0 {
+		problems = append(problems, fmt.Sprintf("%s : expiration days must be at least 1", name))
+	}
+
+	if e.Date != "" {
+		t, err := time.Parse(time.RFC3339, e.Date)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, e.Date)
+		}
+		if err != nil || !t.Equal(t.UTC().Truncate(24*time.Hour)) {
+			problems = append(problems, fmt.Sprintf("%s : expiration date must be midnight UTC, such as 2025-01-31", name))
+		}
+	}
+
+	return problems
+}
+
+// Validate checks the configuration against the limits of the S3 API
+func (c *CORSConfiguration) Validate() error {
+	if len(c.CORSRules) == 0 {
+		return errors.New("CORS configuration has no rules")
+	}
+
+	if len(c.CORSRules) > maxCORSRules {
+		return fmt.Errorf("CORS configuration has %d rules, the maximum is %d", len(c.CORSRules), maxCORSRules)
+	}
+
+	var problems []string
+	ids := map[string]bool{}
+	for i := range c.CORSRules {
+		r := &c.CORSRules[i]
+		name := fmt.Sprintf("rule %d", i+1)
+		if r.ID != "" {
+			name = fmt.Sprintf("rule %q", r.ID)
+		}
+
+		problems = append(problems, validateRuleID(name, r.ID, ids)...)
+
+		if len(r.AllowedOrigins) == 0 {
+			problems = append(problems, fmt.Sprintf("%s : no allowed origins", name))
+		}
+
+		for _, origin := range r.AllowedOrigins {
+			if strings.Count(origin, "*") > 1 {
+				problems = append(problems, fmt.Sprintf("%s : origin %q has more than one wildcard", name, origin))
+			}
+		}
+
+		if len(r.AllowedMethods) == 0 {
+			problems = append(problems, fmt.Sprintf("%s : no allowed methods", name))
+		}
+
+		for _, method := range r.AllowedMethods {
+			if !slices.Contains(corsMethods, method) {
+				problems = append(problems, fmt.Sprintf(
+					"%s : method %q is not one of %s",
+					name,
+					method,
+					strings.Join(corsMethods, ", "),
+				))
+			}
+		}
+
+		for _, header := range r.AllowedHeaders {
+			if strings.Count(header, "*") > 1 {
+				problems = append(problems, fmt.Sprintf("%s : header %q has more than one wildcard", name, header))
+			}
+		}
+
+		if r.MaxAgeSeconds < 0 {
+			problems = append(problems, fmt.Sprintf("%s : max age must not be negative", name))
+		}
+	}
+
+	return joinProblems("invalid CORS configuration", problems)
+}
+
+// Validate checks the policy structure and that every resource is within the
+// bucket
+func (p *Policy) Validate(bucket string) error {
+	var problems []string
+	if p.Version != policyVersion {
+		problems = append(problems, fmt.Sprintf("version must be %s", policyVersion))
+	}
+
+	if len(p.Statement) == 0 {
+		problems = append(problems, "policy has no statements")
+	}
+
+	arn := "arn:aws:s3:::" + bucket
+	for i := range p.Statement {
+		s := &p.Statement[i]
+		name := fmt.Sprintf("statement %d", i+1)
+		if s.Sid != "" {
+			name = fmt.Sprintf("statement %q", s.Sid)
+		}
+
+		if s.Effect != "Allow" && s.Effect != "Deny" {
+			problems = append(problems, fmt.Sprintf("%s : effect must be Allow or Deny", name))
+		}
+
+		if s.Principal == nil {
+			problems = append(problems, fmt.Sprintf("%s : no principal", name))
+		}
+
+		actions, err := stringList(s.Action)
+		if err != nil || len(actions) == 0 {
+			problems = append(problems, fmt.Sprintf("%s : action must be a string or a list of strings", name))
+		}
+
+		for _, action := range actions {
+			if action != "*" && !strings.HasPrefix(action, "s3:") {
+				problems = append(problems, fmt.Sprintf("%s : action %q is not an s3 action", name, action))
+			}
+		}
+
+		resources, err := stringList(s.Resource)
+		if err != nil || len(resources) == 0 {
+			problems = append(problems, fmt.Sprintf("%s : resource must be a string or a list of strings", name))
+		}
+
+		for _, resource := range resources {
+			if resource != arn && !strings.HasPrefix(resource, arn+"/") {
+				problems = append(problems, fmt.Sprintf("%s : resource %q is not in bucket %s", name, resource, bucket))
+			}
+		}
+	}
+
+	return joinProblems("invalid bucket policy", problems)
+}
+
+// StringList returns a policy element which may be a string or a list of
+// strings as a list
+func StringList(v any) []string {
+	list, _ := stringList(v)
+	return list
+}
+
+func stringList(v any) ([]string, error) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%v is not a string", item)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	case []string:
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("%v is not a string or list", v)
+}
+
+func validateRuleID(name, id string, ids map[string]bool) []string {
+	var problems []string
+	if len(id) > maxRuleIDLength {
+		problems = append(problems, fmt.Sprintf("%s : ID is longer than %d characters", name, maxRuleIDLength))
+	}
+
+	if id != "" && ids[id] {
+		problems = append(problems, fmt.Sprintf("%s : ID is used by another rule", name))
+	}
+	ids[id] = true
+
+	return problems
+}
+
+func joinProblems(msg string, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s\n%s", msg, strings.Join(problems, "\n"))
+}
+
+// GetBucketLifecycle returns the lifecycle configuration of a bucket, which
+// is nil when the bucket has none
+func (c *Client) GetBucketLifecycle(ctx context.Context, bucket string) (*LifecycleConfiguration, error) {
+	out := &LifecycleConfiguration{}
+	if err := c.getSubresource(ctx, bucket, "lifecycle", out); err != nil {
+		return nil, ignoreNotFound(err)
+	}
+
+	return out, nil
+}
+
+// PutBucketLifecycle replaces the lifecycle configuration of a bucket.
+// Expiration dates without a time are sent as midnight UTC
+func (c *Client) PutBucketLifecycle(ctx context.Context, bucket string, config *LifecycleConfiguration) error {
+	normalized := *config
+	normalized.Rules = slices.Clone(config.Rules)
+	for i := range normalized.Rules {
+		if e := normalized.Rules[i].Expiration; e != nil && len(e.Date) == len(time.DateOnly) {
+			expiration := *e
+			expiration.Date += "T00:00:00Z"
+			normalized.Rules[i].Expiration = &expiration
+		}
+	}
+
+	body, err := xml.Marshal(&normalized)
+	if err != nil {
+		return err
+	}
+
+	return c.putSubresource(ctx, bucket, "lifecycle", body)
+}
+
+// DeleteBucketLifecycle removes the lifecycle configuration of a bucket
+func (c *Client) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return c.deleteSubresource(ctx, bucket, "lifecycle")
+}
+
+// GetBucketCORS returns the CORS configuration of a bucket, which is nil when
+// the bucket has none
+func (c *Client) GetBucketCORS(ctx context.Context, bucket string) (*CORSConfiguration, error) {
+	out := &CORSConfiguration{}
+	if err := c.getSubresource(ctx, bucket, "cors", out); err != nil {
+		return nil, ignoreNotFound(err)
+	}
+
+	return out, nil
+}
+
+// PutBucketCORS replaces the CORS configuration of a bucket
+func (c *Client) PutBucketCORS(ctx context.Context, bucket string, config *CORSConfiguration) error {
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return c.putSubresource(ctx, bucket, "cors", body)
+}
+
+// DeleteBucketCORS removes the CORS configuration of a bucket
+func (c *Client) DeleteBucketCORS(ctx context.Context, bucket string) error {
+	return c.deleteSubresource(ctx, bucket, "cors")
+}
+
+// GetBucketPolicy returns the policy document of a bucket, which is nil when
+// the bucket has none
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket string) (*Policy, error) {
+	resp, err := c.do(ctx, &request{method: http.MethodGet, bucket: bucket, query: url.Values{"policy": {""}}})
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	if err := json.Unmarshal(raw, policy); err != nil {
+		return nil, fmt.Errorf("error decoding bucket policy : %v", err)
+	}
+
+	return policy, nil
+}
+
+// PutBucketPolicy replaces the policy of a bucket
+func (c *Client) PutBucketPolicy(ctx context.Context, bucket string, policy *Policy) error {
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	return c.putSubresource(ctx, bucket, "policy", body)
+}
+
+// DeleteBucketPolicy removes the policy of a bucket
+func (c *Client) DeleteBucketPolicy(ctx context.Context, bucket string) error {
+	return c.deleteSubresource(ctx, bucket, "policy")
+}
+
+func (c *Client) getSubresource(ctx context.Context, bucket, name string, out any) error {
+	_, err := c.doXML(ctx, &request{method: http.MethodGet, bucket: bucket, query: url.Values{name: {""}}}, out)
+	return err
+}
+
+// putSubresource sends the document with the Content-MD5 header which the
+// lifecycle and CORS calls require
+func (c *Client) putSubresource(ctx context.Context, bucket, name string, body []byte) error {
+	sum := md5.Sum(body) //nolint:gosec
+	header := http.Header{}
+	header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+
+	_, err := c.doXML(
+		ctx,
+		&request{method: http.MethodPut, bucket: bucket, query: url.Values{name: {""}}, header: header, body: body},
+		nil,
+	)
+	return err
+}
+
+func (c *Client) deleteSubresource(ctx context.Context, bucket, name string) error {
+	_, err := c.doXML(ctx, &request{method: http.MethodDelete, bucket: bucket, query: url.Values{name: {""}}}, nil)
+	return err
+}
+
+// ignoreNotFound returns nil for the not found errors of missing bucket
+// configurations, while keeping missing bucket errors
+func ignoreNotFound(err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusNotFound && e.Code != "NoSuchBucket" {
+		return nil
+	}
+
+	return err
+}
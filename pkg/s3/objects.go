@@ -0,0 +1,413 @@
+package s3
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// downloadFilePerms is the mode of downloaded files before the umask applies
+const downloadFilePerms = 0o666
+
+// Bucket is a bucket owned by the credentials
+type Bucket struct {
+	Name         string    `xml:"Name" json:"name"`
+	CreationDate time.Time `xml:"CreationDate" json:"creation_date"`
+}
+
+// Object is an object in a bucket
+type Object struct {
+	Key          string    `xml:"Key" json:"key"`
+	LastModified time.Time `xml:"LastModified" json:"last_modified"`
+	ETag         string    `xml:"ETag" json:"etag"`
+	Size         int64     `xml:"Size" json:"size"`
+}
+
+// Listing is a page of objects and the common prefixes of a delimited list
+type Listing struct {
+	Objects  []Object
+	Prefixes []string
+}
+
+type listBucketsResult struct {
+	Buckets []Bucket `xml:"Buckets>Bucket"`
+}
+
+type listObjectsResult struct {
+	Contents       []Object `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+type initiateMultipartResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// ListBuckets returns the buckets owned by the credentials
+func (c *Client) ListBuckets(ctx context.Context) ([]Bucket, error) {
+	out := &listBucketsResult{}
+	if _, err := c.doXML(ctx, &request{method: http.MethodGet}, out); err != nil {
+		return nil, err
+	}
+
+	return out.Buckets, nil
+}
+
+// CreateBucket creates a bucket
+func (c *Client) CreateBucket(ctx context.Context, bucket string) error {
+	_, err := c.doXML(ctx, &request{method: http.MethodPut, bucket: bucket}, nil)
+	return err
+}
+
+// DeleteBucket deletes an empty bucket
+func (c *Client) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := c.doXML(ctx, &request{method: http.MethodDelete, bucket: bucket}, nil)
+	return err
+}
+
+// ListObjects returns every object under the prefix, following continuation
+// tokens. With a delimiter, keys past the delimiter are grouped into prefixes
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix, delimiter string) (*Listing, error) {
+	listing := &Listing{Objects: []Object{}}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		out := &listObjectsResult{}
+		if _, err := c.doXML(ctx, &request{method: http.MethodGet, bucket: bucket, query: query}, out); err != nil {
+			return nil, err
+		}
+
+		listing.Objects = append(listing.Objects, out.Contents...)
+		for i := range out.CommonPrefixes {
+			listing.Prefixes = append(listing.Prefixes, out.CommonPrefixes[i].Prefix)
+		}
+
+		if !out.IsTruncated || out.NextContinuationToken == "" {
+			return listing, nil
+		}
+		token = out.NextContinuationToken
+	}
+}
+
+// HeadObject returns the size and modification time of an object
+func (c *Client) HeadObject(ctx context.Context, bucket, key string) (*Object, error) {
+	resp, err := c.do(ctx, &request{method: http.MethodHead, bucket: bucket, key: key})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	o := &Object{Key: key, Size: resp.ContentLength, ETag: resp.Header.Get("ETag")}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		o.LastModified = t
+	}
+
+	return o, nil
+}
+
+// PutObject uploads a body held in memory as a single object
+func (c *Client) PutObject(ctx context.Context, bucket, key string, body []byte) error {
+	if body == nil {
+		body = []byte{}
+	}
+
+	_, err := c.doXML(ctx, &request{method: http.MethodPut, bucket: bucket, key: key, body: body}, nil)
+	return err
+}
+
+// GetObject returns the body of an object. The caller closes the body
+func (c *Client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, &request{method: http.MethodGet, bucket: bucket, key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// CopyObject copies an object within the object storage
+func (c *Client) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", escape("/"+srcBucket+"/"+srcKey, false))
+
+	_, err := c.doXML(ctx, &request{method: http.MethodPut, bucket: dstBucket, key: dstKey, header: header}, nil)
+	return err
+}
+
+// DeleteObject deletes an object
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.doXML(ctx, &request{method: http.MethodDelete, bucket: bucket, key: key}, nil)
+	return err
+}
+
+// UploadFile uploads a local file, using a multipart upload with parts sent
+// in parallel when the file is larger than the part size
+func (c *Client) UploadFile(ctx context.Context, path, bucket, key string) (int64, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	size := info.Size()
+	if size <= c.partSize(size) {
+		body, err := io.ReadAll(f)
+		if err != nil {
+			return 0, err
+		}
+		return size, c.PutObject(ctx, bucket, key, body)
+	}
+
+	return size, c.multipartUpload(ctx, f, size, bucket, key)
+}
+
+// partSize returns the part size for an upload, growing it when the
+// configured size would need more parts than allowed
+func (c *Client) partSize(size int64) int64 {
+	part := max(c.PartSize, minPartSize)
+	for size > part*maxParts {
+		part *= 2
+	}
+
+	return part
+}
+
+// multipartUpload uploads the file in parts, aborting the upload on failure
+// so that no incomplete parts are left behind
+func (c *Client) multipartUpload(ctx context.Context, f io.ReaderAt, size int64, bucket, key string) error {
+	out := &initiateMultipartResult{}
+	if _, err := c.doXML(
+		ctx,
+		&request{method: http.MethodPost, bucket: bucket, key: key, query: url.Values{"uploads": {""}}},
+		out,
+	); err != nil {
+		return fmt.Errorf("error starting multipart upload : %v", err)
+	}
+
+	uploadID := url.Values{"uploadId": {out.UploadID}}
+	parts, err := c.uploadParts(ctx, f, size, bucket, key, out.UploadID)
+	if err != nil {
+		abort := &request{method: http.MethodDelete, bucket: bucket, key: key, query: uploadID}
+		if _, errAb := c.doXML(context.WithoutCancel(ctx), abort, nil); errAb != nil {
+			return fmt.Errorf("%v (aborting upload %s failed : %v)", err, out.UploadID, errAb)
+		}
+		return err
+	}
+
+	body, err := xml.Marshal(&completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	// the complete request can fail after a 200 status, reported in the body
+	result := &struct {
+		XMLName xml.Name
+		Error
+	}{}
+	complete := &request{method: http.MethodPost, bucket: bucket, key: key, query: uploadID, body: body}
+	if _, err := c.doXML(ctx, complete, result); err != nil {
+		return fmt.Errorf("error completing multipart upload : %v", err)
+	}
+
+	if result.XMLName.Local == "Error" {
+		result.StatusCode = http.StatusInternalServerError
+		return fmt.Errorf("error completing multipart upload : %v", &result.Error)
+	}
+
+	return nil
+}
+
+// uploadParts uploads the parts of the file with up to Concurrency parts in
+// flight, returning the completed parts in order
+func (c *Client) uploadParts(
+	ctx context.Context,
+	f io.ReaderAt,
+	size int64,
+	bucket, key, uploadID string,
+) ([]completedPart, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partSize := c.partSize(size)
+	count := int((size + partSize - 1) / partSize)
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		parts = make([]completedPart, 0, count)
+		errs  []error
+		sem   = make(chan struct{}, max(c.Concurrency, 1))
+	)
+
+	for n := 1; n <= count; n++ {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(n int) {
+			defer func() { <-sem; wg.Done() }()
+
+			offset := int64(n-1) * partSize
+			etag, err := c.uploadPart(ctx, io.NewSectionReader(f, offset, min(partSize, size-offset)), bucket, key, uploadID, n)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error uploading part %d : %v", n, err))
+				cancel()
+				return
+			}
+			parts = append(parts, completedPart{PartNumber: n, ETag: etag})
+		}(n)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	return parts, nil
+}
+
+func (c *Client) uploadPart(
+	ctx context.Context,
+	r *io.SectionReader,
+	bucket, key, uploadID string,
+	n int,
+) (string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {uploadID}}
+	header, err := c.doXML(ctx, &request{method: http.MethodPut, bucket: bucket, key: key, query: query, body: body}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return header.Get("ETag"), nil
+}
+
+// DownloadFile writes an object to a local file, creating its directory. The
+// object is written to a temporary file which replaces the file once complete
+func (c *Client) DownloadFile(ctx context.Context, bucket, key, path string) (int64, error) {
+	body, err := c.GetObject(ctx, bucket, key)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close() //nolint:errcheck
+
+	path = filepath.Clean(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec
+		return 0, err
+	}
+
+	tmp, err := createTemp(path)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	n, err := io.Copy(tmp, body)
+	if errCl := tmp.Close(); err == nil {
+		err = errCl
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// an existing file keeps its mode, as it would when written in place
+	if info, errSt := os.Stat(path); errSt == nil {
+		if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, os.Rename(tmp.Name(), path)
+}
+
+// createTemp creates a hidden temporary file next to path. Unlike
+// os.CreateTemp, the file is created with the default file mode so the umask
+// applies to the download as it would to any new file
+func createTemp(path string) (*os.File, error) {
+	for range 10000 {
+		suffix := make([]byte, 6)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+
+		name := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"."+hex.EncodeToString(suffix))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, downloadFilePerms) //nolint:gosec
+		if !os.IsExist(err) {
+			return f, err
+		}
+	}
+
+	return nil, fmt.Errorf("unable to create a temporary file for %s", path)
+}
+
+// SplitURI splits an s3://bucket/key URI into its bucket and key
+func SplitURI(uri string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("invalid URI %q, expected s3://bucket/key", uri)
+	}
+
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid URI %q, missing bucket", uri)
+	}
+
+	return bucket, key, nil
+}
+
+// IsURI reports whether the path is an s3:// URI rather than a local path
+func IsURI(path string) bool {
+	return strings.HasPrefix(path, "s3://")
+}
@@ -0,0 +1,198 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// multipartServer records the requests of a multipart upload and fails the
+// upload of failPart, when set
+type multipartServer struct {
+	mu       sync.Mutex
+	parts    map[int][]byte
+	complete *completeMultipartUpload
+	aborted  bool
+	failPart int
+}
+
+func (s *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && query.Get("uploadId") == "upload-1":
+		n, _ := strconv.Atoi(query.Get("partNumber"))
+		if n == s.failPart {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.parts[n] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost && query.Get("uploadId") == "upload-1":
+		s.complete = &completeMultipartUpload{}
+		if err := xml.NewDecoder(r.Body).Decode(s.complete); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Key>key</Key></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && query.Get("uploadId") == "upload-1":
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// writeTestFile writes size bytes of a repeating pattern to a temporary file
+func writeTestFile(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	path := filepath.Join(t.TempDir(), "upload")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, data
+}
+
+func TestMultipartUpload(t *testing.T) {
+	s := &multipartServer{parts: map[int][]byte{}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	path, data := writeTestFile(t, 2*minPartSize+1024)
+
+	c := newTestClient(t, srv.URL)
+	c.PartSize = minPartSize
+
+	n, err := c.UploadFile(context.Background(), path, "bucket", "key")
+	if err != nil {
+		t.Fatalf("UploadFile : %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("UploadFile = %d bytes, want %d", n, len(data))
+	}
+
+	if s.complete == nil || len(s.complete.Parts) != 3 {
+		t.Fatalf("completed parts = %+v, want 3 parts", s.complete)
+	}
+
+	var uploaded []byte
+	for i, p := range s.complete.Parts {
+		if p.PartNumber != i+1 || p.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+			t.Errorf("completed part %d = %+v", i, p)
+		}
+		uploaded = append(uploaded, s.parts[p.PartNumber]...)
+	}
+
+	if !bytes.Equal(uploaded, data) {
+		t.Error("uploaded parts do not match the file")
+	}
+
+	if s.aborted {
+		t.Error("successful upload was aborted")
+	}
+}
+
+func TestMultipartUploadAbort(t *testing.T) {
+	s := &multipartServer{parts: map[int][]byte{}, failPart: 2}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	path, _ := writeTestFile(t, 2*minPartSize+1024)
+
+	c := newTestClient(t, srv.URL)
+	c.PartSize = minPartSize
+
+	if _, err := c.UploadFile(context.Background(), path, "bucket", "key"); err == nil {
+		t.Fatal("UploadFile succeeded with a failing part")
+	}
+
+	if !s.aborted {
+		t.Error("failed upload was not aborted")
+	}
+
+	if s.complete != nil {
+		t.Error("failed upload was completed")
+	}
+}
+
+func TestSmallUploadIsSinglePut(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+	}))
+	defer srv.Close()
+
+	path, _ := writeTestFile(t, 1024)
+
+	if _, err := newTestClient(t, srv.URL).UploadFile(context.Background(), path, "bucket", "dir/key"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || requests[0] != "PUT /bucket/dir/key" {
+		t.Errorf("requests = %v, want a single PUT /bucket/dir/key", requests)
+	}
+}
+
+func TestListObjectsPagination(t *testing.T) {
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("list-type") != "2" || query.Get("prefix") != "logs/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		token := query.Get("continuation-token")
+		tokens = append(tokens, token)
+		switch token {
+		case "":
+			fmt.Fprint(w, `<ListBucketResult><Contents><Key>logs/a</Key><Size>1</Size></Contents>`+
+				`<IsTruncated>true</IsTruncated><NextContinuationToken>page-2</NextContinuationToken></ListBucketResult>`)
+		case "page-2":
+			fmt.Fprint(w, `<ListBucketResult><Contents><Key>logs/b</Key><Size>2</Size></Contents>`+
+				`<CommonPrefixes><Prefix>logs/c/</Prefix></CommonPrefixes>`+
+				`<IsTruncated>false</IsTruncated></ListBucketResult>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	listing, err := newTestClient(t, srv.URL).ListObjects(context.Background(), "bucket", "logs/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tokens) != 2 || tokens[1] != "page-2" {
+		t.Errorf("continuation tokens = %q, want [\"\" \"page-2\"]", tokens)
+	}
+
+	if len(listing.Objects) != 2 || listing.Objects[0].Key != "logs/a" || listing.Objects[1].Key != "logs/b" {
+		t.Errorf("objects = %+v, want logs/a and logs/b", listing.Objects)
+	}
+
+	if len(listing.Prefixes) != 1 || listing.Prefixes[0] != "logs/c/" {
+		t.Errorf("prefixes = %q, want [logs/c/]", listing.Prefixes)
+	}
+}
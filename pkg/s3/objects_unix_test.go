@@ -0,0 +1,58 @@
+//go:build !windows
+
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestDownloadFileMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "content")
+	}))
+	defer srv.Close()
+
+	umask := syscall.Umask(0o022)
+	defer syscall.Umask(umask)
+
+	c := newTestClient(t, srv.URL)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "new", "file")
+	if _, err := c.DownloadFile(context.Background(), "bucket", "key", path); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o644 {
+		t.Errorf("new file mode = %v (%v), want -rw-r--r--", info.Mode().Perm(), err)
+	}
+
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.DownloadFile(context.Background(), "bucket", "key", existing); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(existing); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("existing file mode = %v (%v), want -rw-------", info.Mode().Perm(), err)
+	}
+
+	if content, _ := os.ReadFile(existing); string(content) != "content" {
+		t.Errorf("existing file content = %q, want content", content)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("download left temporary files behind : %v", entries)
+	}
+}
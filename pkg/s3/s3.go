@@ -0,0 +1,362 @@
+// Package s3 provides a minimal S3 client for Vultr object storage, signing
+// requests with AWS signature version 4
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is the signing region used for Vultr object storage
+	DefaultRegion = "us-east-1"
+	// DefaultPartSize is the size of each part of a multipart upload
+	DefaultPartSize = 16 << 20
+	// DefaultConcurrency is the number of parts or objects transferred at once
+	DefaultConcurrency = 4
+	// MaxPresignExpiry is the longest expiry of a presigned URL
+	MaxPresignExpiry = 7 * 24 * time.Hour
+
+	minPartSize     = 5 << 20
+	maxParts        = 10000
+	algorithm       = "AWS4-HMAC-SHA256"
+	service         = "s3"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateLayout   = "20060102T150405Z"
+	dateLayout      = "20060102"
+)
+
+// emptyPayloadHash is the SHA-256 hash of an empty body
+var emptyPayloadHash = hashHex(nil)
+
+// Client signs and sends S3 requests using path style addressing
+type Client struct {
+	Endpoint    *url.URL
+	AccessKey   string
+	SecretKey   string
+	Region      string
+	PartSize    int64
+	Concurrency int
+	HTTP        *http.Client
+
+	now func() time.Time
+}
+
+// Error is an error response returned by the S3 API
+type Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	Resource   string `xml:"Resource"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.StatusCode, e.Code)
+	}
+
+	return fmt.Sprintf("%d %s : %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound reports whether err is an S3 not found error
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// New returns a client for the endpoint. Endpoints without a scheme use
+// HTTPS
+func New(endpoint, accessKey, secretKey string) (*Client, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q : %v", endpoint, err)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q : missing host", endpoint)
+	}
+
+	return &Client{
+		Endpoint:    u,
+		AccessKey:   accessKey,
+		SecretKey:   secretKey,
+		Region:      DefaultRegion,
+		PartSize:    DefaultPartSize,
+		Concurrency: DefaultConcurrency,
+		HTTP:        http.DefaultClient,
+		now:         time.Now,
+	}, nil
+}
+
+// request describes a single S3 request
+type request struct {
+	method string
+	bucket string
+	key    string
+	query  url.Values
+	header http.Header
+	body   []byte
+}
+
+// objectURL returns the path style URL of a bucket or object
+func (c *Client) objectURL(bucket, key string, query url.Values) *url.URL {
+	u := *c.Endpoint
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if bucket != "" {
+		path += "/" + bucket
+		if key != "" {
+			path += "/" + key
+		}
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	u.Path = path
+	u.RawPath = escape(path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	return &u
+}
+
+// do signs and sends a request, returning the response when the status is
+// successful and an *Error otherwise
+func (c *Client) do(ctx context.Context, r *request) (*http.Response, error) {
+	u := c.objectURL(r.bucket, r.key, r.query)
+
+	var body io.Reader
+	payloadHash := emptyPayloadHash
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+		payloadHash = hashHex(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+
+	c.sign(req, payloadHash, c.now().UTC())
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close() //nolint:errcheck
+		return nil, parseError(resp)
+	}
+
+	return resp, nil
+}
+
+// doXML sends a request and decodes the XML response into out, when given
+func (c *Client) doXML(ctx context.Context, r *request, out any) (http.Header, error) {
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return resp.Header, err
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("error decoding %s response : %v", r.method, err)
+	}
+
+	return resp.Header, nil
+}
+
+func parseError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err == nil && len(raw) > 0 {
+		_ = xml.Unmarshal(raw, e)
+	}
+
+	return e
+}
+
+// sign adds the SigV4 authorization header to the request, signing the host
+// and every header already set
+func (c *Client) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.Format(amzDateLayout)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := c.scope(t)
+	signature := c.signature(t, stringToSign(amzDate, scope, canonical))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm,
+		c.AccessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// Presign returns a URL which allows the method on an object without
+// credentials until it expires
+func (c *Client) Presign(method, bucket, key string, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", fmt.Errorf("expiry must be between 1 second and %s", MaxPresignExpiry)
+	}
+
+	t := c.now().UTC()
+	amzDate := t.Format(amzDateLayout)
+	scope := c.scope(t)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", c.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	u := c.objectURL(bucket, key, query)
+
+	canonical := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	signature := c.signature(t, stringToSign(amzDate, scope, canonical))
+	u.RawQuery += "&X-Amz-Signature=" + signature
+
+	return u.String(), nil
+}
+
+func (c *Client) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(dateLayout), c.Region, service, "aws4_request"}, "/")
+}
+
+func (c *Client) signature(t time.Time, toSign string) string {
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), t.Format(dateLayout))
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func stringToSign(amzDate, scope, canonical string) string {
+	return strings.Join([]string{algorithm, amzDate, scope, hashHex([]byte(canonical))}, "\n")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalQuery encodes the query sorted by key as required by SigV4
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, escape(k, true)+"="+escape(v, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// escape percent encodes everything but the unreserved characters, and the
+// slash unless encodeSlash is set
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}